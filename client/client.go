@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"math/rand"
 	"sync"
 	"time"
@@ -8,8 +9,10 @@ import (
 	"github.com/teracrafts/flagkit-go/config"
 	"github.com/teracrafts/flagkit-go/errors"
 	"github.com/teracrafts/flagkit-go/internal/core"
+	"github.com/teracrafts/flagkit-go/internal/evaluation"
 	"github.com/teracrafts/flagkit-go/internal/http"
 	"github.com/teracrafts/flagkit-go/internal/persistence"
+	"github.com/teracrafts/flagkit-go/internal/storage"
 	inttypes "github.com/teracrafts/flagkit-go/internal/types"
 	"github.com/teracrafts/flagkit-go/internal/version"
 	"github.com/teracrafts/flagkit-go/security"
@@ -21,12 +24,19 @@ type (
 	Options              = config.Options
 	OptionFunc           = config.OptionFunc
 	EvaluationContext    = types.EvaluationContext
+	ContextKind          = types.ContextKind
+	Diagnostics          = types.Diagnostics
+	PollingDiagnostics   = types.PollingDiagnostics
 	EvaluationResult     = types.EvaluationResult
+	EvaluationReason     = types.EvaluationReason
 	FlagState            = types.FlagState
 	FlagType             = types.FlagType
+	InitializationStatus = types.InitializationStatus
+	Variant              = types.Variant
 	Logger               = types.Logger
 	EventPersistence     = persistence.EventPersistence
 	EventPersisterAdapter = persistence.EventPersisterAdapter
+	SnapshotStore        = types.SnapshotStore
 )
 
 // Function aliases
@@ -37,8 +47,10 @@ var (
 	InferFlagType               = types.InferFlagType
 	NewContext                  = types.NewContext
 	NewAnonymousContext         = types.NewAnonymousContext
+	NewMultiContext             = types.NewMultiContext
 	NewDefaultLogger            = types.NewDefaultLogger
 	NewEventPersistence         = persistence.NewEventPersistence
+	NewEventPersistenceWithConfig = persistence.NewEventPersistenceWithConfig
 	NewEventPersisterAdapter    = persistence.NewEventPersisterAdapter
 	CheckPIIWithStrictMode      = security.CheckPIIWithStrictMode
 	VerifyBootstrapSignature    = security.VerifyBootstrapSignature
@@ -52,7 +64,9 @@ var (
 
 // Error code aliases
 const (
-	ErrInitFailed = errors.ErrInitFailed
+	ErrInitFailed  = errors.ErrInitFailed
+	ErrInitTimeout = errors.ErrInitTimeout
+	ErrEvalError   = errors.ErrEvalError
 )
 
 // Config constant aliases
@@ -72,15 +86,24 @@ const (
 
 // EvaluationReason constant aliases
 const (
-	ReasonCached       = types.ReasonCached
-	ReasonFallthrough  = types.ReasonFallthrough
-	ReasonTargeted     = types.ReasonTargeted
-	ReasonDefault      = types.ReasonDefault
-	ReasonDisabled     = types.ReasonDisabled
-	ReasonFlagNotFound = types.ReasonFlagNotFound
-	ReasonError        = types.ReasonError
-	ReasonStaleCache   = types.ReasonStaleCache
-	ReasonBootstrap    = types.ReasonBootstrap
+	ReasonCached             = types.ReasonCached
+	ReasonFallthrough        = types.ReasonFallthrough
+	ReasonTargeted           = types.ReasonTargeted
+	ReasonDefault            = types.ReasonDefault
+	ReasonDisabled           = types.ReasonDisabled
+	ReasonFlagNotFound       = types.ReasonFlagNotFound
+	ReasonError              = types.ReasonError
+	ReasonStaleCache         = types.ReasonStaleCache
+	ReasonBootstrap          = types.ReasonBootstrap
+	ReasonPrerequisiteFailed = types.ReasonPrerequisiteFailed
+)
+
+// InitializationStatus constant aliases
+const (
+	InitStatusInitializing     = types.InitStatusInitializing
+	InitStatusReadyFromNetwork = types.InitStatusReadyFromNetwork
+	InitStatusReadyFromCache   = types.InitStatusReadyFromCache
+	InitStatusFailed           = types.InitStatusFailed
 )
 
 // NullLogger type alias
@@ -107,14 +130,47 @@ func init() {
 type Client struct {
 	options          *Options
 	cache            *core.Cache
+	contextCache     *core.ContextCache
 	httpClient       *http.HTTPClient
+	eventsClient     *http.HTTPClient
 	eventQueue       *core.EventQueue
+	impressions      *core.ImpressionTracker
 	pollingManager   *core.PollingManager
+	streamingManager *core.StreamingManager
 	eventPersistence *EventPersistence
+	snapshots        SnapshotStore
+	encryption       *storage.EncryptedStorage
+	evaluator        *evaluation.Evaluator
+	experiments      *evaluation.Experiments
+	segments         *evaluation.Segments
+	exposures        *core.ExposureTracker
+	listeners        *changeListeners
+	circuits         *circuitListeners
+	unobserve        func()
 	context          *EvaluationContext
 	sessionID        string
+	environmentID    string
 	lastUpdateTime   string
+	pollingInterval  time.Duration
+	localEvalActive  bool
+	rulesPending     bool
+	experimentsReady bool
+	segmentsReady    bool
+	snapshotLoaded   bool
+	serverFlags      map[string]inttypes.FlagState
+	storeMisses      map[string]struct{}
+	initBackoff      *http.RetryConfig
+	initStarted      bool
+	initRetrying     bool
+	initErr          error
+	lastFetch        time.Time
+	lastErr          error
+	lastErrTime      time.Time
+	serverRetryAt    time.Time
+	status           InitializationStatus
 	ready            bool
+	readyCh          chan struct{}
+	done             chan struct{}
 	closed           bool
 	logger           Logger
 	mu               sync.RWMutex
@@ -146,31 +202,72 @@ func NewClient(apiKey string, opts ...OptionFunc) (*Client, error) {
 
 	// Create cache
 	cache := core.NewCache(&core.CacheConfig{
-		TTL:     options.CacheTTL,
-		MaxSize: 1000,
-		Logger:  logger,
+		TTL:      options.CacheTTL,
+		MaxSize:  1000,
+		Logger:   logger,
+		OnLookup: cacheLookupRecorder(options.Metrics),
 	})
 
+	// Create HTTP transport with the configured proxy and TLS settings
+	transport := options.Transport
+	if transport == nil && (options.Proxy != "" || options.TLSConfig != nil) {
+		proxyTransport, err := http.NewTransport(options.Proxy, options.TLSConfig)
+		if err != nil {
+			return nil, err
+		}
+		transport = proxyTransport
+	}
+	if options.HTTPClient != nil && transport != nil {
+		logger.Warn("HTTP client is set, ignoring transport, proxy and TLS options")
+	}
+
 	// Create HTTP client
-	httpClient := http.NewHTTPClient(&http.HTTPClientConfig{
+	circuits := newCircuitListeners(logger)
+	httpConfig := &http.HTTPClientConfig{
+		BaseURL:                options.BaseURL,
 		APIKey:                 options.APIKey,
 		SecondaryAPIKey:        options.SecondaryAPIKey,
 		KeyRotationGracePeriod: options.KeyRotationGracePeriod,
 		EnableRequestSigning:   options.EnableRequestSigning,
 		Timeout:                options.Timeout,
-		Retry: &http.RetryConfig{
-			MaxAttempts:       options.Retries,
-			BaseDelay:         time.Second,
-			MaxDelay:          30 * time.Second,
-			BackoffMultiplier: 2.0,
-			Jitter:            100 * time.Millisecond,
-		},
-		Logger:    logger,
-	})
+		Retry:                  retryConfig(options.Retry, options.Retries),
+		CircuitBreaker:         circuitConfig(options.CircuitBreaker, CircuitAPI, circuits, logger),
+		Logger:                 logger,
+		Client:                 options.HTTPClient,
+		Transport:              transport,
+		OnRequest:              requestRecorder(options.Metrics),
+		OnRetry:                retryRecorder(options.Metrics),
+	}
+	if options.InitRetry != nil {
+		httpConfig.PathRetry = map[string]*http.RetryConfig{
+			"/sdk/init": retryConfig(options.InitRetry, options.Retries),
+		}
+	}
+	httpClient := http.NewHTTPClient(httpConfig)
+
+	// Events are sent through a separate HTTP client, with its own circuit
+	// breaker, when they have their own base URL or policies
+	eventsClient := httpClient
+	if options.EventsBaseURL != "" || options.EventsRetry != nil || options.EventsCircuitBreaker != nil {
+		eventsConfig := *httpConfig
+		if options.EventsBaseURL != "" {
+			eventsConfig.BaseURL = options.EventsBaseURL
+		}
+		if options.EventsRetry != nil {
+			eventsConfig.Retry = retryConfig(options.EventsRetry, options.Retries)
+		}
+		eventsCircuit := options.EventsCircuitBreaker
+		if eventsCircuit == nil {
+			eventsCircuit = options.CircuitBreaker
+		}
+		eventsConfig.CircuitBreaker = circuitConfig(eventsCircuit, CircuitEvents, circuits, logger)
+		eventsClient = http.NewHTTPClient(&eventsConfig)
+	}
 
 	// Create event persistence if enabled
 	var eventPersistence *EventPersistence
 	var persisterAdapter *EventPersisterAdapter
+	var eventQueue *core.EventQueue
 	if options.PersistEvents {
 		storagePath := options.EventStoragePath
 		maxEvents := options.MaxPersistedEvents
@@ -183,30 +280,57 @@ func NewClient(apiKey string, opts ...OptionFunc) (*Client, error) {
 		}
 
 		var err error
-		eventPersistence, err = NewEventPersistence(storagePath, maxEvents, flushInterval, logger)
+		var encryption *storage.EncryptedStorage
+		if options.EncryptEvents {
+			encryption, err = storage.NewEncryptedStorage(&storage.EncryptedStorageConfig{
+				APIKey: options.APIKey,
+				Logger: logger,
+			})
+		}
+		if err == nil {
+			eventPersistence, err = NewEventPersistenceWithConfig(&persistence.EventPersistenceConfig{
+				StoragePath:           storagePath,
+				Namespace:             apiKeyHash(options.APIKey),
+				AdoptDefaultNamespace: true,
+				MaxEvents:             maxEvents,
+				FlushInterval:         flushInterval,
+				Logger:                logger,
+				Encryption:            encryption,
+				Compression:           options.EventCompression,
+				MaxSegmentSize:        options.EventSegmentSize,
+				MaxDiskUsage:          options.EventDiskQuota,
+				OnDrop: func(count int) {
+					eventQueue.RecordDropped(core.DropDiskQuota, count)
+				},
+			})
+		}
 		if err != nil {
 			logger.Warn("Failed to create event persistence", "error", err.Error())
 		} else {
 			persisterAdapter = NewEventPersisterAdapter(eventPersistence)
-			eventPersistence.Start()
 		}
 	}
 
 	// Create event queue with persistence support
 	eventQueueOpts := &core.EventQueueOptions{
-		HTTPClient:     httpClient,
+		HTTPClient:     eventsClient,
 		SessionID:      sessionID,
 		SDKVersion:     SDKVersion,
 		Logger:         logger,
 		PersistEnabled: options.PersistEvents && persisterAdapter != nil,
+		Config:         eventQueueConfig(options),
+		OnDrop:         dropRecorder(options.Metrics),
+		OnHighWater:    options.OnEventQueueHighWater,
+		Processors:     eventProcessors(options.EventProcessors),
 	}
 	if persisterAdapter != nil {
 		eventQueueOpts.Persister = persisterAdapter
 	}
-	eventQueue := core.NewEventQueue(eventQueueOpts)
+	eventQueue = core.NewEventQueue(eventQueueOpts)
 
-	// Recover events if persistence is enabled
+	// Start persistence and recover events if persistence is enabled
 	if eventPersistence != nil {
+		eventPersistence.Start()
 		if err := eventQueue.RecoverEvents(); err != nil {
 			logger.Warn("Failed to recover persisted events", "error", err.Error())
 		}
@@ -216,56 +340,203 @@ func NewClient(apiKey string, opts ...OptionFunc) (*Client, error) {
 		options:          options,
 		cache:            cache,
 		httpClient:       httpClient,
+		eventsClient:     eventsClient,
 		eventQueue:       eventQueue,
 		eventPersistence: eventPersistence,
+		listeners:        newChangeListeners(),
+		circuits:         circuits,
+		initBackoff:      initRetry,
 		sessionID:        sessionID,
+		status:           InitStatusInitializing,
+		readyCh:          make(chan struct{}),
+		done:             make(chan struct{}),
 		logger:           logger,
 	}
 
+	if options.InitRetry != nil {
+		client.initBackoff = retryConfig(options.InitRetry, options.Retries)
+	}
+
+	if options.Metrics != nil {
+		client.observeMetrics(options.Metrics)
+	}
+
+	// Create per-context result cache if server evaluation is enabled
+	if options.ServerEvaluation {
+		client.contextCache = core.NewContextCache(&core.ContextCacheConfig{
+			TTL:     options.CacheTTL,
+			MaxSize: options.ContextCacheSize,
+			Logger:  logger,
+		})
+	}
+
+	// Create experiment assignment and exposure tracking
+	client.experiments = evaluation.NewExperiments(logger)
+	client.exposures = core.NewExposureTracker(eventQueue, 0, logger)
+
+	// Create impression tracker if evaluation events are enabled
+	if options.EvaluationEvents {
+		client.impressions = core.NewImpressionTracker(eventQueue, nil, logger)
+	}
+
+	// Create segment store, shared with the local evaluator for segment-match clauses
+	client.segments = evaluation.NewSegments(logger)
+
+	// Create local evaluator if enabled
+	if options.LocalEvaluation {
+		client.evaluator = evaluation.NewEvaluator(logger)
+		client.evaluator.SetSegments(client.segments)
+	}
+
 	// Apply bootstrap values
 	client.applyBootstrap()
 
+	// Load the last known good flag set, which takes precedence over bootstrap values
+	client.snapshots = newSnapshotStore(options)
+	if client.snapshots != nil {
+		if options.EnableCacheEncryption {
+			encryption, err := storage.NewEncryptedStorage(&storage.EncryptedStorageConfig{
+				APIKey: options.APIKey,
+				Logger: logger,
+			})
+			if err != nil {
+				logger.Warn("Failed to create snapshot encryption, snapshots disabled", "error", err.Error())
+				client.snapshots = nil
+			} else {
+				client.encryption = encryption
+			}
+		}
+		client.loadSnapshot()
+	}
+
 	logger.Info("FlagKit client created",
 		"offline", options.Offline,
 	)
 
+	if options.BackgroundInit {
+		client.startInBackground(options.StartWaitTimeout)
+	}
+
 	return client, nil
 }
 
+// eventQueueConfig returns the event queue configuration with unset options
+// taken from the defaults.
+func eventQueueConfig(options *Options) *core.EventQueueConfig {
+	config := core.DefaultEventQueueConfig()
+	if options.EventQueueSize > 0 {
+		config.MaxSize = options.EventQueueSize
+	}
+	if options.EventBatchSize > 0 {
+		config.BatchSize = options.EventBatchSize
+	}
+	if options.EventFlushInterval > 0 {
+		config.FlushInterval = options.EventFlushInterval
+	}
+	if options.EventOverflowPolicy != "" {
+		config.Overflow = options.EventOverflowPolicy
+	}
+	if options.EventBlockTimeout > 0 {
+		config.BlockTimeout = options.EventBlockTimeout
+	}
+	config.HighWaterMark = options.EventHighWaterMark
+	if options.EventRedelivery != nil {
+		config.Redelivery = redeliveryConfig(options.EventRedelivery)
+	}
+	return config
+}
+
 // Initialize initializes the SDK by fetching flag configurations.
 func (c *Client) Initialize() error {
+	return c.InitializeContext(context.Background())
+}
+
+// InitializeContext initializes the SDK by fetching flag configurations,
+// giving up when the context is done. The client is marked ready either way
+// and serves cached, bootstrap or default values until flags are fetched;
+// recoverable failures are retried in the background with backoff.
+// With background initialization, it waits for the background
+// initialization instead of starting another one.
+func (c *Client) InitializeContext(ctx context.Context) error {
 	c.mu.Lock()
 	if c.closed {
 		c.mu.Unlock()
 		return NewError(ErrInitFailed, "client is closed")
 	}
+	background := c.options.BackgroundInit && c.initStarted
+	c.initStarted = true
 	c.mu.Unlock()
 
+	if background {
+		if err := c.WaitForReadyContext(ctx); err != nil {
+			return err
+		}
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+		return c.initErr
+	}
+
+	return c.initialize(ctx)
+}
+
+// initialize performs an initialization attempt.
+func (c *Client) initialize(ctx context.Context) error {
 	if c.options.Offline {
 		c.logger.Info("Offline mode enabled, skipping initialization")
-		c.setReady()
+		c.setReady(InitStatusReadyFromCache)
 		return nil
 	}
 
+	if c.isDaemonMode() {
+		return c.initializeFromStore()
+	}
+
 	c.logger.Debug("Initializing SDK")
 
-	resp, err := c.httpClient.Get("/sdk/init")
+	data, err := c.fetchInit(ctx)
 	if err != nil {
 		c.logger.Error("SDK initialization failed", "error", err.Error())
 		if c.options.OnError != nil {
 			c.options.OnError(err)
 		}
 		// Mark as ready anyway (will use cache/bootstrap/defaults)
-		c.setReady()
+		c.initFailed(err)
 		return err
 	}
 
+	c.applyInit(ctx, data)
+	return nil
+}
+
+// fetchInit fetches and parses the init response.
+func (c *Client) fetchInit(ctx context.Context) (*types.InitResponse, error) {
+	resp, err := c.httpClient.GetWithContext(ctx, "/sdk/init")
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, NewErrorWithCause(ErrInitTimeout, "initialization timed out", err)
+		}
+		return nil, err
+	}
+
 	data, err := ParseInitResponse(resp.Body)
 	if err != nil {
-		c.logger.Error("Failed to parse init response", "error", err.Error())
-		c.setReady()
-		return NewErrorWithCause(ErrInitFailed, "failed to parse init response", err)
+		return nil, NewErrorWithCause(ErrInitFailed, "failed to parse init response", err)
 	}
+	return data, nil
+}
+
+// applyInit stores the flags of an init response and starts updates and
+// event delivery.
+func (c *Client) applyInit(ctx context.Context, data *types.InitResponse) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		c.logger.Debug("Client closed, discarding initialization response")
+		return
+	}
+	snapshotLoaded := c.snapshotLoaded
+	c.environmentID = data.EnvironmentID
+	c.mu.Unlock()
 
 	// Set environment ID for event tracking
 	c.eventQueue.SetEnvironmentID(data.EnvironmentID)
@@ -285,25 +556,72 @@ func (c *Client) Initialize() error {
 			LastModified: f.LastModified,
 		}
 	}
-	c.cache.SetMany(internalFlags, c.options.CacheTTL)
+	if snapshotLoaded || c.isStoreWriter() {
+		// Drop snapshot flags that no longer exist and initialize the flag store
+		c.replaceFlags(internalFlags, c.options.CacheTTL)
+	} else {
+		c.storeFlags(internalFlags, c.options.CacheTTL)
+	}
 	c.lastUpdateTime = data.ServerTime
+	c.recordServerFlags(internalFlags, true)
+	c.saveSnapshot(data.ServerTime)
+	c.recordFetch()
+
+	// Download segment definitions if supported
+	if data.Metadata != nil && data.Metadata.Features != nil && data.Metadata.Features.Segments {
+		if err := c.loadSegments(ctx); err != nil {
+			c.logger.Warn("Failed to load segment definitions", "error", err.Error())
+		}
+	}
 
-	// Start polling if enabled
-	if c.options.EnablePolling {
-		c.startPolling(time.Duration(data.PollingIntervalSeconds) * time.Second)
+	// Download rule definitions if local evaluation is enabled and supported
+	if c.evaluator != nil {
+		if data.Metadata != nil && data.Metadata.Features != nil && data.Metadata.Features.LocalEval {
+			if err := c.loadFlagDefinitions(ctx); err != nil {
+				c.logger.Warn("Failed to load flag definitions, using server-evaluated values", "error", err.Error())
+			}
+		} else {
+			c.logger.Info("Local evaluation not supported by server, using server-evaluated values")
+		}
 	}
 
-	// Start event queue
+	// Download experiment definitions if supported
+	if data.Metadata != nil && data.Metadata.Features != nil && data.Metadata.Features.Experiments {
+		if err := c.loadExperiments(ctx); err != nil {
+			c.logger.Warn("Failed to load experiment definitions", "error", err.Error())
+		}
+	}
+
+	c.mu.Lock()
+	c.pollingInterval = time.Duration(data.PollingIntervalSeconds) * time.Second
+	c.mu.Unlock()
+
+	// Start streaming if enabled and supported, otherwise poll if enabled
+	if c.shouldStream(data) {
+		c.startStreaming(data.StreamingURL)
+	} else if c.options.EnablePolling {
+		c.startPolling(c.pollingInterval)
+	}
+
+	// Start event queue, unless the client was closed in the meantime. The
+	// lock is held so that Close stops the queue after it is started.
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return
+	}
 	c.eventQueue.Start()
+	if c.impressions != nil {
+		c.impressions.Start()
+	}
+	c.mu.Unlock()
 
-	c.setReady()
+	c.setReady(InitStatusReadyFromNetwork)
 
 	c.logger.Info("SDK initialized",
 		"flag_count", len(data.Flags),
 		"environment", data.Environment,
 	)
-
-	return nil
 }
 
 // IsReady returns whether the SDK is ready.
@@ -321,33 +639,58 @@ func (c *Client) WaitForReady() {
 	_ = c.Initialize()
 }
 
+// WaitForReadyContext waits for the SDK to be ready, starting initialization
+// if it has not been started. Returns an ErrInitTimeout error if the context
+// is done first; initialization then continues in the background.
+func (c *Client) WaitForReadyContext(ctx context.Context) error {
+	c.mu.Lock()
+	if c.closed && !c.ready {
+		c.mu.Unlock()
+		return NewError(ErrInitFailed, "client is closed")
+	}
+	start := !c.initStarted
+	c.initStarted = true
+	c.mu.Unlock()
+
+	if start {
+		go func() { _ = c.initialize(context.Background()) }()
+	}
+
+	select {
+	case <-c.readyCh:
+		return nil
+	case <-ctx.Done():
+		return NewErrorWithCause(ErrInitTimeout, "timed out waiting for SDK to be ready", ctx.Err())
+	}
+}
+
 // GetBooleanValue evaluates a boolean flag.
 func (c *Client) GetBooleanValue(key string, defaultValue bool, ctx ...*EvaluationContext) bool {
-	result := c.evaluate(key, defaultValue, getContext(ctx), FlagTypeBoolean)
+	result := c.evaluate(context.Background(), key, defaultValue, getContext(ctx), FlagTypeBoolean)
 	return result.BoolValue()
 }
 
 // GetStringValue evaluates a string flag.
 func (c *Client) GetStringValue(key string, defaultValue string, ctx ...*EvaluationContext) string {
-	result := c.evaluate(key, defaultValue, getContext(ctx), FlagTypeString)
+	result := c.evaluate(context.Background(), key, defaultValue, getContext(ctx), FlagTypeString)
 	return result.StringValue()
 }
 
 // GetNumberValue evaluates a number flag.
 func (c *Client) GetNumberValue(key string, defaultValue float64, ctx ...*EvaluationContext) float64 {
-	result := c.evaluate(key, defaultValue, getContext(ctx), FlagTypeNumber)
+	result := c.evaluate(context.Background(), key, defaultValue, getContext(ctx), FlagTypeNumber)
 	return result.Float64Value()
 }
 
 // GetIntValue evaluates an integer flag.
 func (c *Client) GetIntValue(key string, defaultValue int, ctx ...*EvaluationContext) int {
-	result := c.evaluate(key, float64(defaultValue), getContext(ctx), FlagTypeNumber)
+	result := c.evaluate(context.Background(), key, float64(defaultValue), getContext(ctx), FlagTypeNumber)
 	return result.IntValue()
 }
 
 // GetJSONValue evaluates a JSON flag.
 func (c *Client) GetJSONValue(key string, defaultValue map[string]any, ctx ...*EvaluationContext) map[string]any {
-	result := c.evaluate(key, defaultValue, getContext(ctx), FlagTypeJSON)
+	result := c.evaluate(context.Background(), key, defaultValue, getContext(ctx), FlagTypeJSON)
 	if v := result.JSONValue(); v != nil {
 		return v
 	}
@@ -356,7 +699,7 @@ func (c *Client) GetJSONValue(key string, defaultValue map[string]any, ctx ...*E
 
 // Evaluate evaluates a flag and returns the full result.
 func (c *Client) Evaluate(key string, ctx ...*EvaluationContext) *EvaluationResult {
-	return c.evaluate(key, nil, getContext(ctx), "")
+	return c.evaluate(context.Background(), key, nil, getContext(ctx), "")
 }
 
 // EvaluateAll evaluates all flags.
@@ -368,11 +711,62 @@ func (c *Client) EvaluateAll(ctx ...*EvaluationContext) map[string]*EvaluationRe
 	return results
 }
 
+// GetBooleanValueContext evaluates a boolean flag. The context is passed to
+// server requests made during evaluation, for cancellation and tracing.
+func (c *Client) GetBooleanValueContext(reqCtx context.Context, key string, defaultValue bool, ctx ...*EvaluationContext) bool {
+	result := c.evaluate(reqCtx, key, defaultValue, getContext(ctx), FlagTypeBoolean)
+	return result.BoolValue()
+}
+
+// GetStringValueContext evaluates a string flag with a request context.
+func (c *Client) GetStringValueContext(reqCtx context.Context, key string, defaultValue string, ctx ...*EvaluationContext) string {
+	result := c.evaluate(reqCtx, key, defaultValue, getContext(ctx), FlagTypeString)
+	return result.StringValue()
+}
+
+// GetNumberValueContext evaluates a number flag with a request context.
+func (c *Client) GetNumberValueContext(reqCtx context.Context, key string, defaultValue float64, ctx ...*EvaluationContext) float64 {
+	result := c.evaluate(reqCtx, key, defaultValue, getContext(ctx), FlagTypeNumber)
+	return result.Float64Value()
+}
+
+// GetIntValueContext evaluates an integer flag with a request context.
+func (c *Client) GetIntValueContext(reqCtx context.Context, key string, defaultValue int, ctx ...*EvaluationContext) int {
+	result := c.evaluate(reqCtx, key, float64(defaultValue), getContext(ctx), FlagTypeNumber)
+	return result.IntValue()
+}
+
+// GetJSONValueContext evaluates a JSON flag with a request context.
+func (c *Client) GetJSONValueContext(reqCtx context.Context, key string, defaultValue map[string]any, ctx ...*EvaluationContext) map[string]any {
+	result := c.evaluate(reqCtx, key, defaultValue, getContext(ctx), FlagTypeJSON)
+	if v := result.JSONValue(); v != nil {
+		return v
+	}
+	return defaultValue
+}
+
+// EvaluateContext evaluates a flag with a request context and returns the full result.
+func (c *Client) EvaluateContext(reqCtx context.Context, key string, ctx ...*EvaluationContext) *EvaluationResult {
+	return c.evaluate(reqCtx, key, nil, getContext(ctx), "")
+}
+
+// EvaluateAllContext evaluates all flags with a request context.
+func (c *Client) EvaluateAllContext(reqCtx context.Context, ctx ...*EvaluationContext) map[string]*EvaluationResult {
+	results := make(map[string]*EvaluationResult)
+	for _, key := range c.GetAllFlagKeys() {
+		results[key] = c.EvaluateContext(reqCtx, key, ctx...)
+	}
+	return results
+}
+
 // HasFlag checks if a flag exists.
 func (c *Client) HasFlag(key string) bool {
 	if c.cache.Has(key) {
 		return true
 	}
+	if c.isLocalEvalActive() && c.evaluator.Has(key) {
+		return true
+	}
 	_, ok := c.options.Bootstrap[key]
 	return ok
 }
@@ -383,6 +777,11 @@ func (c *Client) GetAllFlagKeys() []string {
 	for _, k := range c.cache.GetAllKeys() {
 		keys[k] = true
 	}
+	if c.isLocalEvalActive() {
+		for _, k := range c.evaluator.Keys() {
+			keys[k] = true
+		}
+	}
 	for k := range c.options.Bootstrap {
 		keys[k] = true
 	}
@@ -462,55 +861,127 @@ func (c *Client) Reset() {
 	c.eventQueue.Track("context.reset", nil)
 }
 
-// Track tracks a custom event.
+// Track tracks a custom event attributed to the global context.
 // Returns an error if StrictPIIMode is enabled and PII is detected in event data.
 func (c *Client) Track(eventType string, data ...map[string]any) error {
+	return c.TrackWithContext(eventType, nil, data...)
+}
+
+// TrackWithContext tracks a custom event attributed to a context, merged over
+// the global context. Private attributes are not sent.
+// Returns an error if StrictPIIMode is enabled and PII is detected in event data.
+func (c *Client) TrackWithContext(eventType string, ctx *EvaluationContext, data ...map[string]any) error {
 	var eventData map[string]any
 	if len(data) > 0 {
 		eventData = data[0]
-
-		// Security: check for potential PII in event data
-		if err := CheckPIIWithStrictMode(eventData, "event", c.options.StrictPIIMode, c.logger); err != nil {
+		if err := c.checkEventData(eventData); err != nil {
 			return err
 		}
 	}
-	c.eventQueue.Track(eventType, eventData)
+	c.eventQueue.TrackWithContext(eventType, eventData, c.internalContext(ctx))
+	return nil
+}
+
+// TrackMetric tracks a custom event with a numeric value, such as revenue or
+// latency, attributed to the global context or the given context merged over it.
+// Returns an error if StrictPIIMode is enabled and PII is detected in event data.
+func (c *Client) TrackMetric(eventType string, value float64, data map[string]any, ctx ...*EvaluationContext) error {
+	if err := c.checkEventData(data); err != nil {
+		return err
+	}
+	c.eventQueue.TrackMetric(eventType, value, data, c.internalContext(getContext(ctx)))
 	return nil
 }
 
+// checkEventData checks event data for potential PII.
+func (c *Client) checkEventData(data map[string]any) error {
+	if data == nil {
+		return nil
+	}
+	return CheckPIIWithStrictMode(data, "event", c.options.StrictPIIMode, c.logger)
+}
+
 // Flush flushes pending events.
 func (c *Client) Flush() {
-	c.eventQueue.Flush()
+	_ = c.FlushContext(context.Background())
+}
+
+// FlushContext flushes pending events, giving up when the context is done.
+// Returns the error of the send request.
+func (c *Client) FlushContext(ctx context.Context) error {
+	if c.impressions != nil {
+		c.impressions.Flush()
+	}
+	return c.eventQueue.FlushContext(ctx)
+}
+
+// DroppedEvents returns the number of events dropped without being sent, by
+// reason: "queue_full" when the event queue overflowed, "send_failed" when
+// sending failed the maximum number of attempts and "disk_quota" when
+// persisted events were removed to stay within the disk quota.
+func (c *Client) DroppedEvents() map[string]int64 {
+	return c.eventQueue.DroppedEvents()
 }
 
 // Refresh forces a refresh of flags from the server.
 func (c *Client) Refresh() {
+	c.RefreshContext(context.Background())
+}
+
+// RefreshContext forces a refresh of flags from the server, giving up when
+// the context is done.
+func (c *Client) RefreshContext(ctx context.Context) {
 	if c.options.Offline || c.closed {
 		return
 	}
 
-	c.refresh()
+	c.refreshContext(ctx)
 }
 
 // Close closes the client and cleans up resources.
 func (c *Client) Close() error {
+	return c.CloseContext(context.Background())
+}
+
+// CloseContext closes the client and cleans up resources. Pending events are
+// sent until the context is done; the context error is returned if events
+// could not be sent before the deadline.
+func (c *Client) CloseContext(ctx context.Context) error {
 	c.mu.Lock()
 	if c.closed {
 		c.mu.Unlock()
 		return nil
 	}
 	c.closed = true
+	close(c.done)
 	c.mu.Unlock()
 
 	c.logger.Debug("Closing SDK")
 
+	// Stop streaming
+	if sm := c.getStreamingManager(); sm != nil {
+		sm.Disconnect()
+	}
+
 	// Stop polling
-	if c.pollingManager != nil {
-		c.pollingManager.Stop()
+	if pm := c.getPollingManager(); pm != nil {
+		pm.Stop()
 	}
 
 	// Flush and stop events
-	c.eventQueue.Stop()
+	var drainErr error
+	if c.impressions != nil {
+		c.impressions.Stop()
+	}
+	if err := c.eventQueue.StopContext(ctx); err != nil && ctx.Err() != nil {
+		c.logger.Warn("Deadline reached before pending events were sent", "error", err.Error())
+		drainErr = ctx.Err()
+	}
+
+	// Stop reporting gauges
+	if c.unobserve != nil {
+		c.unobserve()
+	}
 
 	// Close event persistence
 	if c.eventPersistence != nil {
@@ -523,13 +994,30 @@ func (c *Client) Close() error {
 	if err := c.httpClient.Close(); err != nil {
 		c.logger.Warn("Failed to close HTTP client", "error", err.Error())
 	}
+	if c.eventsClient != c.httpClient {
+		if err := c.eventsClient.Close(); err != nil {
+			c.logger.Warn("Failed to close events HTTP client", "error", err.Error())
+		}
+	}
 
 	c.logger.Info("SDK closed")
-	return nil
+	return drainErr
 }
 
-// evaluate performs flag evaluation.
-func (c *Client) evaluate(key string, defaultValue any, ctx *EvaluationContext, expectedType FlagType) *EvaluationResult {
+// evaluate performs flag evaluation and records the evaluation if enabled.
+// reqCtx is passed to server requests made during evaluation.
+func (c *Client) evaluate(reqCtx context.Context, key string, defaultValue any, ctx *EvaluationContext, expectedType FlagType) *EvaluationResult {
+	start := time.Now()
+	result := c.evaluateFlag(reqCtx, key, defaultValue, ctx, expectedType)
+	c.recordEvaluation(result, start)
+	if c.impressions != nil && key != "" {
+		c.recordImpression(result, ctx)
+	}
+	return result
+}
+
+// evaluateFlag resolves a flag value from local rules, cache, bootstrap or the default.
+func (c *Client) evaluateFlag(reqCtx context.Context, key string, defaultValue any, ctx *EvaluationContext, expectedType FlagType) *EvaluationResult {
 	// Apply evaluation jitter if enabled (cache timing attack protection)
 	if c.options.EvaluationJitter.Enabled {
 		c.applyEvaluationJitter()
@@ -541,8 +1029,29 @@ func (c *Client) evaluate(key string, defaultValue any, ctx *EvaluationContext,
 		return createDefaultResult(key, defaultValue, ReasonDefault)
 	}
 
+	// Try local rule evaluation
+	if c.isLocalEvalActive() {
+		if detail, ok := c.evaluator.Evaluate(key, c.internalContext(ctx)); ok {
+			return c.localResult(detail, defaultValue, expectedType)
+		}
+	}
+
+	// Try per-context server evaluation when rules are not available locally
+	if c.isServerEvalEnabled() && !c.isLocalEvalActive() {
+		if result, ok := c.serverResult(reqCtx, key, defaultValue, ctx, expectedType); ok {
+			return result
+		}
+	}
+
+	// Read flags missing from the cache through from the flag store
+	if c.isDaemonMode() && c.cache.Get(key) == nil {
+		c.readThroughStore(key)
+	}
+
+	cached, lookup := c.cache.Lookup(key)
+
 	// Try cache first
-	if cached := c.cache.Get(key); cached != nil {
+	if lookup == core.CacheHit {
 		// Type check if expected type provided
 		if expectedType != "" && FlagType(cached.FlagType) != expectedType {
 			c.logger.Warn("Flag type mismatch",
@@ -564,14 +1073,14 @@ func (c *Client) evaluate(key string, defaultValue any, ctx *EvaluationContext,
 	}
 
 	// Try stale cache
-	if stale := c.cache.GetStale(key); stale != nil {
+	if lookup == core.CacheStale {
 		c.logger.Debug("Using stale cached value", "key", key)
 		return &EvaluationResult{
 			FlagKey:   key,
-			Value:     stale.Value,
-			Enabled:   stale.Enabled,
+			Value:     cached.Value,
+			Enabled:   cached.Enabled,
 			Reason:    ReasonStaleCache,
-			Version:   stale.Version,
+			Version:   cached.Version,
 			Timestamp: time.Now(),
 		}
 	}
@@ -642,7 +1151,9 @@ func (c *Client) applyBootstrap() {
 
 // startPolling starts background polling.
 func (c *Client) startPolling(interval time.Duration) {
-	if c.pollingManager != nil {
+	c.mu.Lock()
+	if c.pollingManager != nil || c.closed {
+		c.mu.Unlock()
 		return
 	}
 
@@ -656,22 +1167,42 @@ func (c *Client) startPolling(interval time.Duration) {
 		BackoffMultiplier: 2.0,
 		MaxInterval:       5 * time.Minute,
 	}, c.logger)
+	pm := c.pollingManager
+	c.mu.Unlock()
 
-	c.pollingManager.Start()
+	pm.Start()
+}
+
+// getPollingManager returns the polling manager, if polling has started.
+func (c *Client) getPollingManager() *core.PollingManager {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.pollingManager
 }
 
 // refresh refreshes flags from the server.
 func (c *Client) refresh() {
+	c.refreshContext(context.Background())
+}
+
+// refreshContext refreshes flags from the server, giving up when the context is done.
+func (c *Client) refreshContext(ctx context.Context) {
+	if c.isDaemonMode() {
+		c.refreshFromStore()
+		return
+	}
+
 	since := c.lastUpdateTime
 	if since == "" {
 		since = time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
 	}
 
-	resp, err := c.httpClient.Get("/sdk/updates?since=" + since)
+	resp, err := c.httpClient.GetWithContext(ctx, "/sdk/updates?since="+since)
 	if err != nil {
 		c.logger.Warn("Failed to refresh flags", "error", err.Error())
-		if c.pollingManager != nil {
-			c.pollingManager.OnError()
+		c.recordError(err)
+		if pm := c.getPollingManager(); pm != nil {
+			pm.OnError()
 		}
 		return
 	}
@@ -679,8 +1210,10 @@ func (c *Client) refresh() {
 	data, err := ParseUpdatesResponse(resp.Body)
 	if err != nil {
 		c.logger.Warn("Failed to parse updates response", "error", err.Error())
+		c.recordError(err)
 		return
 	}
+	c.recordFetch()
 
 	if len(data.Flags) > 0 {
 		// Convert to internal FlagState
@@ -695,8 +1228,10 @@ func (c *Client) refresh() {
 				LastModified: f.LastModified,
 			}
 		}
-		c.cache.SetMany(internalFlags)
+		c.storeFlags(internalFlags)
 		c.lastUpdateTime = data.CheckedAt
+		c.recordServerFlags(internalFlags, false)
+		c.saveSnapshot(data.CheckedAt)
 
 		c.logger.Debug("Flags refreshed", "count", len(data.Flags))
 
@@ -705,15 +1240,27 @@ func (c *Client) refresh() {
 		}
 	}
 
-	if c.pollingManager != nil {
-		c.pollingManager.OnSuccess()
+	// Refresh rule definitions if local evaluation is active
+	c.refreshSegments(ctx)
+	c.refreshFlagDefinitions(ctx)
+	c.refreshExperiments(ctx)
+
+	if pm := c.getPollingManager(); pm != nil {
+		pm.OnSuccess()
 	}
 }
 
-// setReady marks the client as ready.
-func (c *Client) setReady() {
+// setReady marks the client as ready with the given initialization status.
+func (c *Client) setReady(status InitializationStatus) {
 	c.mu.Lock()
+	if !c.ready {
+		close(c.readyCh)
+	}
 	c.ready = true
+	c.status = status
+	if status == InitStatusReadyFromNetwork {
+		c.initErr = nil
+	}
 	c.mu.Unlock()
 
 	if c.options.OnReady != nil {
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+	"time"
+)
+
+// Diagnostics returns a snapshot of the state of the client. Counting
+// persisted events reads the event files, so avoid calling it in hot paths.
+func (c *Client) Diagnostics() *Diagnostics {
+	c.mu.RLock()
+	diagnostics := &Diagnostics{
+		Status: c.status,
+		Ready:  c.ready,
+	}
+	if !c.lastFetch.IsZero() {
+		lastFetch := c.lastFetch
+		diagnostics.LastSuccessfulFetch = &lastFetch
+	}
+	if c.lastErr != nil {
+		lastErrTime := c.lastErrTime
+		diagnostics.LastError = c.lastErr.Error()
+		diagnostics.LastErrorTime = &lastErrTime
+	}
+	pm := c.pollingManager
+	sm := c.streamingManager
+	c.mu.RUnlock()
+
+	diagnostics.CircuitBreakers = map[string]map[string]any{
+		CircuitAPI: c.httpClient.CircuitStats(),
+	}
+	if c.eventsClient != c.httpClient {
+		diagnostics.CircuitBreakers[CircuitEvents] = c.eventsClient.CircuitStats()
+	}
+
+	if pm != nil {
+		diagnostics.Polling = &PollingDiagnostics{
+			Active:            pm.IsActive(),
+			CurrentIntervalMs: pm.GetCurrentInterval().Milliseconds(),
+		}
+	}
+	if sm != nil {
+		diagnostics.StreamingState = string(sm.GetState())
+	}
+
+	diagnostics.Cache = c.cache.Stats()
+	diagnostics.EventQueueSize = c.eventQueue.QueueSize()
+	diagnostics.EventsDropped = c.eventQueue.DroppedEvents()
+
+	if c.eventPersistence != nil {
+		backlog, err := c.eventPersistence.Backlog()
+		if err != nil {
+			c.logger.Warn("Failed to count persisted events", "error", err.Error())
+		} else {
+			diagnostics.PersistedEvents = &backlog
+		}
+	}
+
+	diagnostics.KeyID = c.httpClient.GetKeyID()
+	diagnostics.KeyRotation = c.httpClient.IsInKeyRotation()
+
+	return diagnostics
+}
+
+// DiagnosticsHandler returns an HTTP handler that serves the diagnostics of
+// the client as JSON, for health check and debug endpoints. It responds with
+// 503 Service Unavailable until the client is ready or when only default
+// values can be served.
+func (c *Client) DiagnosticsHandler() nethttp.Handler {
+	return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, req *nethttp.Request) {
+		if req.Method != nethttp.MethodGet && req.Method != nethttp.MethodHead {
+			w.WriteHeader(nethttp.StatusMethodNotAllowed)
+			return
+		}
+
+		diagnostics := c.Diagnostics()
+		status := nethttp.StatusOK
+		if !diagnostics.Healthy() {
+			status = nethttp.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(diagnostics)
+	})
+}
+
+// recordFetch records that flags were fetched from the API.
+func (c *Client) recordFetch() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastFetch = time.Now()
+}
+
+// recordError records an error fetching flags.
+func (c *Client) recordError(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastErr = err
+	c.lastErrTime = time.Now()
+}
@@ -0,0 +1,125 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/teracrafts/flagkit-go/internal/convert"
+	"github.com/teracrafts/flagkit-go/internal/core"
+	"github.com/teracrafts/flagkit-go/internal/evaluation"
+	inttypes "github.com/teracrafts/flagkit-go/internal/types"
+)
+
+// loadFlagDefinitions downloads flag rule definitions for local evaluation.
+func (c *Client) loadFlagDefinitions(ctx context.Context) error {
+	resp, err := c.httpClient.GetWithContext(ctx, "/sdk/rules")
+	if err != nil {
+		return err
+	}
+
+	var data inttypes.RulesResponse
+	if err := json.Unmarshal(resp.Body, &data); err != nil {
+		return NewErrorWithCause(ErrInitFailed, "failed to parse rules response", err)
+	}
+
+	c.evaluator.SetFlags(data.Flags)
+
+	c.mu.Lock()
+	c.localEvalActive = true
+	c.mu.Unlock()
+
+	c.logger.Debug("Flag definitions loaded", "count", len(data.Flags))
+	return nil
+}
+
+// isLocalEvalActive returns whether flag definitions are loaded for local evaluation.
+func (c *Client) isLocalEvalActive() bool {
+	if c.evaluator == nil {
+		return false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.localEvalActive
+}
+
+// FlagDependencies returns the keys of all flags that a flag depends on through
+// prerequisites, direct prerequisites first.
+// Returns nil if the flag has no prerequisites or flag definitions are not
+// loaded (see WithLocalEvaluation).
+func (c *Client) FlagDependencies(key string) []string {
+	if !c.isLocalEvalActive() {
+		return nil
+	}
+	deps, ok := c.evaluator.Dependencies(key)
+	if !ok || len(deps) == 0 {
+		return nil
+	}
+	return deps
+}
+
+// resolveContext merges the per-call context over the global context.
+func (c *Client) resolveContext(ctx *EvaluationContext) *EvaluationContext {
+	global := c.GetContext()
+	if global == nil {
+		return ctx
+	}
+	if ctx == nil {
+		return global
+	}
+	return global.Merge(ctx)
+}
+
+// internalContext resolves the effective context and converts it to the internal type.
+func (c *Client) internalContext(ctx *EvaluationContext) *inttypes.EvaluationContext {
+	return convert.Context(c.resolveContext(ctx))
+}
+
+// localResult converts a local evaluation detail into an EvaluationResult.
+func (c *Client) localResult(detail *evaluation.Detail, defaultValue any, expectedType FlagType) *EvaluationResult {
+	if expectedType != "" && detail.FlagType != "" && FlagType(detail.FlagType) != expectedType {
+		c.logger.Warn("Flag type mismatch",
+			"key", detail.FlagKey,
+			"expected", expectedType,
+			"got", detail.FlagType,
+		)
+		return createDefaultResult(detail.FlagKey, defaultValue, ReasonError)
+	}
+
+	result := &EvaluationResult{
+		FlagKey:         detail.FlagKey,
+		Value:           defaultValue,
+		Enabled:         detail.Enabled,
+		Reason:          EvaluationReason(detail.Reason),
+		Version:         detail.Version,
+		Timestamp:       time.Now(),
+		RuleID:          detail.RuleID,
+		InRollout:       detail.InRollout,
+		PrerequisiteKey: detail.PrerequisiteKey,
+	}
+
+	if detail.VariationIndex >= 0 {
+		index := detail.VariationIndex
+		result.Value = detail.Value
+		result.VariationIndex = &index
+	}
+
+	return result
+}
+
+// recordImpression records an evaluation for analytics.
+func (c *Client) recordImpression(result *EvaluationResult, ctx *EvaluationContext) {
+	var contextKey string
+	if resolved := c.resolveContext(ctx); resolved != nil {
+		contextKey = resolved.CanonicalKey()
+	}
+
+	c.impressions.Record(core.Impression{
+		FlagKey:        result.FlagKey,
+		Value:          result.Value,
+		Version:        result.Version,
+		Reason:         string(result.Reason),
+		VariationIndex: result.VariationIndex,
+		ContextKey:     contextKey,
+	})
+}
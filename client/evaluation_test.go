@@ -0,0 +1,54 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/teracrafts/flagkit-go/config"
+	inttypes "github.com/teracrafts/flagkit-go/internal/types"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func newLocalEvalClient(t *testing.T, defs ...inttypes.FlagDefinition) *Client {
+	t.Helper()
+
+	c := newTestClient(t, config.WithLocalEvaluation())
+	require.NotNil(t, c.evaluator)
+	c.evaluator.SetFlags(defs)
+	c.localEvalActive = true
+	return c
+}
+
+func TestEvaluatePrerequisiteFailed(t *testing.T) {
+	c := newLocalEvalClient(t,
+		inttypes.FlagDefinition{
+			Key: "launch", FlagType: inttypes.FlagTypeBoolean, Enabled: false,
+			Variations: []any{false, true}, OffVariation: intPtr(0),
+			Fallthrough: inttypes.VariationOrRollout{Variation: intPtr(1)},
+		},
+		inttypes.FlagDefinition{
+			Key: "checkout-v2", FlagType: inttypes.FlagTypeBoolean, Enabled: true,
+			Variations: []any{false, true}, OffVariation: intPtr(0),
+			Prerequisites: []inttypes.Prerequisite{{Key: "launch", Variation: 1}},
+			Fallthrough:   inttypes.VariationOrRollout{Variation: intPtr(1)},
+		},
+	)
+
+	result := c.Evaluate("checkout-v2", NewContext("user-1"))
+
+	assert.Equal(t, ReasonPrerequisiteFailed, result.Reason)
+	assert.Equal(t, "launch", result.PrerequisiteKey)
+	assert.Equal(t, false, result.Value)
+	assert.Equal(t, []string{"launch"}, c.FlagDependencies("checkout-v2"))
+}
+
+func TestFlagDependenciesWithoutLocalEvaluation(t *testing.T) {
+	c := newTestClient(t)
+
+	assert.Nil(t, c.FlagDependencies("checkout-v2"))
+}
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"github.com/teracrafts/flagkit-go/internal/core"
+	"github.com/teracrafts/flagkit-go/types"
+)
+
+// EventProcessor processes analytics events before they are queued.
+type EventProcessor = types.EventProcessor
+
+// eventProcessors adapts event processors to the event queue.
+func eventProcessors(processors []EventProcessor) []core.EventProcessor {
+	if len(processors) == 0 {
+		return nil
+	}
+	result := make([]core.EventProcessor, len(processors))
+	for i, processor := range processors {
+		result[i] = processEvent(processor)
+	}
+	return result
+}
+
+// processEvent runs a processor on a queue event.
+func processEvent(processor EventProcessor) core.EventProcessor {
+	return func(event *core.Event) bool {
+		analytics := types.AnalyticsEvent{
+			Type:        event.Type,
+			Data:        event.Data,
+			Context:     event.Context,
+			MetricValue: event.MetricValue,
+			Metadata:    event.Metadata,
+		}
+		if !processor.Process(&analytics) {
+			return false
+		}
+		event.Type = analytics.Type
+		event.Data = analytics.Data
+		event.Context = analytics.Context
+		event.MetricValue = analytics.MetricValue
+		event.Metadata = analytics.Metadata
+		return true
+	}
+}
@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/teracrafts/flagkit-go/internal/core"
+	inttypes "github.com/teracrafts/flagkit-go/internal/types"
+	"github.com/teracrafts/flagkit-go/types"
+)
+
+// loadExperiments downloads experiment definitions.
+func (c *Client) loadExperiments(ctx context.Context) error {
+	resp, err := c.httpClient.GetWithContext(ctx, "/sdk/experiments")
+	if err != nil {
+		return err
+	}
+
+	var data inttypes.ExperimentsResponse
+	if err := json.Unmarshal(resp.Body, &data); err != nil {
+		return NewErrorWithCause(ErrInitFailed, "failed to parse experiments response", err)
+	}
+
+	c.experiments.SetExperiments(data.Experiments)
+
+	c.mu.Lock()
+	c.experimentsReady = true
+	c.mu.Unlock()
+
+	c.logger.Debug("Experiment definitions loaded", "count", len(data.Experiments))
+	return nil
+}
+
+// isExperimentsReady returns whether experiment definitions are loaded.
+func (c *Client) isExperimentsReady() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.experimentsReady
+}
+
+// refreshExperiments reloads experiment definitions if they were loaded before.
+func (c *Client) refreshExperiments(ctx context.Context) {
+	if !c.isExperimentsReady() {
+		return
+	}
+	if err := c.loadExperiments(ctx); err != nil {
+		c.logger.Warn("Failed to refresh experiment definitions", "error", err.Error())
+	}
+}
+
+// GetVariant assigns the context to a variant of an experiment.
+// An exposure event is tracked the first time an enrolled context sees a
+// variant; contexts are told apart by the experiment's bucketing attribute.
+// If the experiment is unknown, an empty Variant with InExperiment false is returned.
+func (c *Client) GetVariant(experimentKey string, ctx ...*EvaluationContext) *types.Variant {
+	variant := &types.Variant{ExperimentKey: experimentKey}
+
+	evalCtx := c.internalContext(getContext(ctx))
+	assignment, ok := c.experiments.Assign(experimentKey, evalCtx)
+	if !ok {
+		c.logger.Debug("Experiment not found", "key", experimentKey)
+		return variant
+	}
+
+	variant.Name = assignment.Variant
+	variant.Payload = assignment.Payload
+	variant.Version = assignment.Version
+	variant.InExperiment = assignment.InExperiment
+
+	if assignment.InExperiment && assignment.ContextKey != "" {
+		c.exposures.Expose(assignment.ContextKey, core.Exposure{
+			ExperimentKey: assignment.ExperimentKey,
+			Variant:       assignment.Variant,
+			Version:       assignment.Version,
+		}, evalCtx)
+	}
+
+	return variant
+}
+
+// TrackConversion tracks a conversion for a metric, attributed to the
+// experiment variants the context has been exposed to. Nothing is tracked
+// if the context has no value for the bucketing attribute of any experiment.
+func (c *Client) TrackConversion(metricKey string, value float64, ctx ...*EvaluationContext) {
+	evalCtx := c.internalContext(getContext(ctx))
+
+	contextKeys := c.experiments.ContextKeys(evalCtx)
+	if len(contextKeys) == 0 {
+		c.logger.Debug("Conversion not tracked, context has no bucketing value", "metric", metricKey)
+		return
+	}
+
+	c.exposures.Convert(metricKey, value, contextKeys, evalCtx)
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	inttypes "github.com/teracrafts/flagkit-go/internal/types"
+)
+
+func TestGetVariantTracksExposureOnce(t *testing.T) {
+	c := newTestClient(t)
+	c.experiments.SetExperiments([]inttypes.ExperimentDefinition{{
+		Key:      "pricing-page",
+		Version:  1,
+		Status:   inttypes.ExperimentStatusRunning,
+		Variants: []inttypes.ExperimentVariant{{Key: "control", Weight: 1}, {Key: "discount", Weight: 1}},
+	}})
+
+	ctx := NewContext("user-1")
+	first := c.GetVariant("pricing-page", ctx)
+	second := c.GetVariant("pricing-page", ctx)
+
+	assert.True(t, first.InExperiment)
+	assert.Equal(t, first.Name, second.Name)
+	assert.Equal(t, 1, c.eventQueue.QueueSize())
+
+	exposures := c.exposures.Exposures("userId:user-1")
+	assert.Len(t, exposures, 1)
+	assert.Equal(t, first.Name, exposures[0].Variant)
+
+	c.TrackConversion("checkout", 42.5, ctx)
+	assert.Equal(t, 2, c.eventQueue.QueueSize())
+}
+
+func TestGetVariantUnknownExperiment(t *testing.T) {
+	c := newTestClient(t)
+
+	variant := c.GetVariant("missing", NewContext("user-1"))
+
+	assert.Equal(t, "missing", variant.ExperimentKey)
+	assert.False(t, variant.InExperiment)
+	assert.Empty(t, variant.Name)
+	assert.Equal(t, 0, c.eventQueue.QueueSize())
+}
+
+func TestExperimentExposuresUseBucketingAttribute(t *testing.T) {
+	c := newTestClient(t)
+	c.experiments.SetExperiments([]inttypes.ExperimentDefinition{{
+		Key:      "team-dashboard",
+		Version:  1,
+		Status:   inttypes.ExperimentStatusRunning,
+		BucketBy: "teamId",
+		Variants: []inttypes.ExperimentVariant{{Key: "control", Weight: 1}, {Key: "new", Weight: 1}},
+	}})
+
+	// Contexts without the bucketing attribute are served the control
+	// variant and nothing is tracked
+	variant := c.GetVariant("team-dashboard", NewContext("user-1"))
+	assert.False(t, variant.InExperiment)
+	c.TrackConversion("upgrade", 1, NewContext("user-1"))
+	assert.Equal(t, 0, c.eventQueue.QueueSize())
+
+	// Users of the same team share their exposure
+	first := c.GetVariant("team-dashboard", NewContext("user-1").WithCustom("teamId", "team-1"))
+	second := c.GetVariant("team-dashboard", NewContext("user-2").WithCustom("teamId", "team-1"))
+	assert.True(t, first.InExperiment)
+	assert.Equal(t, first.Name, second.Name)
+	assert.Equal(t, 1, c.eventQueue.QueueSize())
+
+	exposures := c.exposures.Exposures("teamId:team-1")
+	assert.Len(t, exposures, 1)
+
+	c.TrackConversion("upgrade", 1, NewContext("user-3").WithCustom("teamId", "team-1"))
+	assert.Equal(t, 2, c.eventQueue.QueueSize())
+}
@@ -0,0 +1,167 @@
+package client
+
+import (
+	inttypes "github.com/teracrafts/flagkit-go/internal/types"
+)
+
+// isDaemonMode returns whether flags are read from the flag store instead of the API.
+func (c *Client) isDaemonMode() bool {
+	return c.options.DaemonMode && c.options.FlagStore != nil
+}
+
+// isStoreWriter returns whether received flags are written to the flag store.
+func (c *Client) isStoreWriter() bool {
+	return c.options.FlagStore != nil && !c.options.DaemonMode
+}
+
+// initializeFromStore initializes the client from the flag store in daemon mode.
+func (c *Client) initializeFromStore() error {
+	c.logger.Debug("Initializing SDK from flag store")
+
+	initialized, err := c.options.FlagStore.Initialized()
+	if err != nil {
+		c.logger.Error("Failed to read flag store", "error", err.Error())
+		if c.options.OnError != nil {
+			c.options.OnError(err)
+		}
+		// Mark as ready anyway (will use cache/bootstrap/defaults)
+		c.setReady(c.fallbackStatus())
+		return NewErrorWithCause(ErrInitFailed, "failed to read flag store", err)
+	}
+
+	if initialized {
+		if err := c.syncFromStore(); err != nil {
+			c.logger.Warn("Failed to load flags from flag store", "error", err.Error())
+		}
+	} else {
+		c.logger.Warn("Flag store has not been initialized by a writer, using defaults")
+	}
+
+	if c.options.EnablePolling {
+		c.startPolling(c.options.PollingInterval)
+	}
+
+	// Start event queue
+	c.eventQueue.Start()
+	if c.impressions != nil {
+		c.impressions.Start()
+	}
+
+	c.setReady(c.fallbackStatus())
+
+	c.logger.Info("SDK initialized from flag store", "flag_count", c.cache.Size())
+	return nil
+}
+
+// syncFromStore replaces the cached flags with the contents of the flag store.
+func (c *Client) syncFromStore() error {
+	flags, err := c.options.FlagStore.GetAll()
+	if err != nil {
+		return err
+	}
+
+	internalFlags := make([]inttypes.FlagState, len(flags))
+	for i, f := range flags {
+		internalFlags[i] = toInternalFlagState(f)
+	}
+	c.replaceFlags(internalFlags, c.options.CacheTTL)
+
+	c.mu.Lock()
+	c.storeMisses = nil
+	c.mu.Unlock()
+	return nil
+}
+
+// refreshFromStore reloads flags from the flag store in daemon mode.
+func (c *Client) refreshFromStore() {
+	if err := c.syncFromStore(); err != nil {
+		c.logger.Warn("Failed to refresh flags from flag store", "error", err.Error())
+		if pm := c.getPollingManager(); pm != nil {
+			pm.OnError()
+		}
+		return
+	}
+
+	if pm := c.getPollingManager(); pm != nil {
+		pm.OnSuccess()
+	}
+}
+
+// readThroughStore loads a flag that is missing from the cache, or expired,
+// from the flag store in daemon mode. Flags the store does not have are not
+// looked up again until the next store refresh.
+func (c *Client) readThroughStore(key string) {
+	c.mu.RLock()
+	_, missing := c.storeMisses[key]
+	c.mu.RUnlock()
+	if missing {
+		return
+	}
+
+	flag, err := c.options.FlagStore.Get(key)
+	if err != nil {
+		c.logger.Warn("Failed to read flag from flag store", "key", key, "error", err.Error())
+		return
+	}
+	if flag == nil {
+		c.mu.Lock()
+		if c.storeMisses == nil {
+			c.storeMisses = make(map[string]struct{})
+		}
+		c.storeMisses[key] = struct{}{}
+		c.mu.Unlock()
+		return
+	}
+	c.storeFlags([]inttypes.FlagState{toInternalFlagState(*flag)}, c.options.CacheTTL)
+}
+
+// writeStoreFlags writes received flags to the flag store in writer mode.
+func (c *Client) writeStoreFlags(flags []inttypes.FlagState) {
+	if !c.isStoreWriter() || len(flags) == 0 {
+		return
+	}
+	publicFlags := make([]FlagState, len(flags))
+	for i, f := range flags {
+		publicFlags[i] = toPublicFlagState(f)
+	}
+	if err := c.options.FlagStore.UpsertAll(publicFlags); err != nil {
+		c.logger.Warn("Failed to write flags to flag store", "count", len(flags), "error", err.Error())
+	}
+}
+
+// writeStoreDelete removes a deleted flag from the flag store in writer mode.
+func (c *Client) writeStoreDelete(key string) {
+	if !c.isStoreWriter() {
+		return
+	}
+	if err := c.options.FlagStore.Delete(key); err != nil {
+		c.logger.Warn("Failed to delete flag from flag store", "key", key, "error", err.Error())
+	}
+}
+
+// writeStoreInit replaces the flag store contents in writer mode and marks
+// the store as initialized.
+func (c *Client) writeStoreInit(flags []inttypes.FlagState) {
+	if !c.isStoreWriter() {
+		return
+	}
+	publicFlags := make([]FlagState, len(flags))
+	for i, f := range flags {
+		publicFlags[i] = toPublicFlagState(f)
+	}
+	if err := c.options.FlagStore.Init(publicFlags); err != nil {
+		c.logger.Warn("Failed to initialize flag store", "error", err.Error())
+	}
+}
+
+// toInternalFlagState converts a public FlagState to the internal type.
+func toInternalFlagState(f FlagState) inttypes.FlagState {
+	return inttypes.FlagState{
+		Key:          f.Key,
+		Value:        f.Value,
+		Enabled:      f.Enabled,
+		Version:      f.Version,
+		FlagType:     inttypes.FlagType(f.FlagType),
+		LastModified: f.LastModified,
+	}
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/teracrafts/flagkit-go/config"
+	inttypes "github.com/teracrafts/flagkit-go/internal/types"
+	"github.com/teracrafts/flagkit-go/store"
+)
+
+func TestFlagStoreWriterMode(t *testing.T) {
+	s := store.NewMemoryStore()
+	c := newTestClient(t, config.WithFlagStore(s))
+
+	c.replaceFlags([]inttypes.FlagState{
+		{Key: "checkout", Value: true, Enabled: true, Version: 1, FlagType: inttypes.FlagTypeBoolean},
+		{Key: "banner", Value: "hi", Enabled: true, Version: 1, FlagType: inttypes.FlagTypeString},
+	})
+	initialized, _ := s.Initialized()
+	assert.True(t, initialized)
+
+	c.storeFlags([]inttypes.FlagState{
+		{Key: "checkout", Value: false, Enabled: true, Version: 2, FlagType: inttypes.FlagTypeBoolean},
+	})
+	flag, _ := s.Get("checkout")
+	require.NotNil(t, flag)
+	assert.Equal(t, false, flag.Value)
+
+	c.removeFlag("banner")
+	flag, _ = s.Get("banner")
+	assert.Nil(t, flag)
+}
+
+func TestFlagStoreDaemonMode(t *testing.T) {
+	s := store.NewMemoryStore()
+	require.NoError(t, s.Init([]FlagState{
+		{Key: "checkout", Value: true, Enabled: true, Version: 1, FlagType: FlagTypeBoolean},
+	}))
+
+	c, err := NewClient("sdk_test_key_12345", config.WithDaemonMode(s), config.WithPollingDisabled())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+
+	require.NoError(t, c.Initialize())
+	assert.True(t, c.IsReady())
+	assert.True(t, c.GetBooleanValue("checkout", false))
+
+	// Flags added to the store after initialization are read through
+	require.NoError(t, s.Upsert(FlagState{Key: "banner", Value: "hello", Enabled: true, Version: 1, FlagType: FlagTypeString}))
+	assert.Equal(t, "hello", c.GetStringValue("banner", "default"))
+
+	// Reloading the store picks up changes and notifies listeners
+	var changed []string
+	c.OnAnyChange(func(old, new EvaluationResult) {
+		changed = append(changed, new.FlagKey)
+	})
+	require.NoError(t, s.Upsert(FlagState{Key: "checkout", Value: false, Enabled: true, Version: 2, FlagType: FlagTypeBoolean}))
+	c.Refresh()
+
+	assert.False(t, c.GetBooleanValue("checkout", true))
+	assert.Equal(t, []string{"checkout"}, changed)
+
+	// The daemon never writes to the store
+	flag, _ := s.Get("checkout")
+	assert.Equal(t, 2, flag.Version)
+}
+
+func TestDaemonModeRequiresStore(t *testing.T) {
+	_, err := NewClient("sdk_test_key_12345", config.WithDaemonMode(nil))
+	assert.Error(t, err)
+}
+
+// countingStore counts flag lookups of a memory store.
+type countingStore struct {
+	*store.MemoryStore
+	gets int
+}
+
+func (s *countingStore) Get(key string) (*FlagState, error) {
+	s.gets++
+	return s.MemoryStore.Get(key)
+}
+
+func TestDaemonModeRemembersStoreMisses(t *testing.T) {
+	s := &countingStore{MemoryStore: store.NewMemoryStore()}
+	require.NoError(t, s.Init(nil))
+
+	c, err := NewClient("sdk_test_key_12345",
+		config.WithDaemonMode(s), config.WithPollingDisabled(), config.WithServerEvaluation())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	require.NoError(t, c.Initialize())
+
+	// Daemon clients do not evaluate on the server
+	assert.False(t, c.isServerEvalEnabled())
+
+	assert.False(t, c.GetBooleanValue("missing", false, NewContext("user-1")))
+	assert.False(t, c.GetBooleanValue("missing", false, NewContext("user-1")))
+	assert.Equal(t, 1, s.gets)
+
+	// Misses are forgotten when the store is reloaded
+	require.NoError(t, s.Upsert(FlagState{Key: "missing", Value: true, Enabled: true, Version: 1, FlagType: FlagTypeBoolean}))
+	c.Refresh()
+	assert.True(t, c.GetBooleanValue("missing", false))
+}
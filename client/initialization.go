@@ -0,0 +1,143 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"github.com/teracrafts/flagkit-go/internal/http"
+	inttypes "github.com/teracrafts/flagkit-go/internal/types"
+)
+
+// initRetry is the default backoff for retrying a failed initialization.
+var initRetry = &http.RetryConfig{
+	BaseDelay:         time.Second,
+	MaxDelay:          time.Minute,
+	BackoffMultiplier: 2.0,
+	Jitter:            100 * time.Millisecond,
+}
+
+// Ready returns a channel that is closed when the first initialization
+// attempt completes, whether flags were fetched or not. Use
+// InitializationStatus to find out where flags are served from.
+func (c *Client) Ready() <-chan struct{} {
+	return c.readyCh
+}
+
+// InitializationStatus returns the progress of initialization.
+func (c *Client) InitializationStatus() InitializationStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.status
+}
+
+// startInBackground starts initialization in the background and waits up to
+// timeout for it to complete.
+func (c *Client) startInBackground(timeout time.Duration) {
+	c.mu.Lock()
+	c.initStarted = true
+	c.mu.Unlock()
+
+	go func() { _ = c.initialize(context.Background()) }()
+
+	if timeout <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-c.readyCh:
+	case <-timer.C:
+		c.logger.Warn("SDK not initialized within start wait timeout, continuing in the background", "timeout", timeout.String())
+	}
+}
+
+// initFailed marks the client as ready after a failed initialization attempt
+// and retries initialization in the background if the error is recoverable.
+func (c *Client) initFailed(err error) {
+	c.mu.Lock()
+	c.initErr = err
+	c.mu.Unlock()
+	c.recordError(err)
+
+	c.setReady(c.fallbackStatus())
+
+	if isInitRetryable(err) {
+		c.retryInitialize()
+	}
+}
+
+// fallbackStatus returns the initialization status when flags could not be
+// fetched from the API.
+func (c *Client) fallbackStatus() InitializationStatus {
+	if c.cache.Size() > 0 {
+		return InitStatusReadyFromCache
+	}
+	return InitStatusFailed
+}
+
+// retryInitialize retries initialization with backoff until it succeeds,
+// fails with an error that is not recoverable or the client is closed.
+func (c *Client) retryInitialize() {
+	c.mu.Lock()
+	if c.closed || c.initRetrying {
+		c.mu.Unlock()
+		return
+	}
+	c.initRetrying = true
+	c.mu.Unlock()
+
+	go func() {
+		defer func() {
+			c.mu.Lock()
+			c.initRetrying = false
+			c.mu.Unlock()
+		}()
+
+		for attempt := 1; ; attempt++ {
+			delay := http.CalculateBackoff(attempt, c.initBackoff)
+			c.logger.Debug("Retrying initialization", "attempt", attempt, "delay", delay.String())
+
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-c.done:
+				timer.Stop()
+				return
+			}
+
+			// Initialize or a previous retry may have succeeded in the meantime
+			if c.InitializationStatus() == InitStatusReadyFromNetwork {
+				return
+			}
+
+			data, err := c.fetchInit(context.Background())
+			if err != nil {
+				c.logger.Warn("Initialization retry failed", "attempt", attempt, "error", err.Error())
+				c.mu.Lock()
+				c.initErr = err
+				c.mu.Unlock()
+				c.recordError(err)
+				if !isInitRetryable(err) {
+					return
+				}
+				continue
+			}
+
+			c.applyInit(context.Background(), data)
+			return
+		}
+	}()
+}
+
+// isInitRetryable returns whether a failed initialization should be retried.
+// Network errors are retried; authentication errors are not.
+func isInitRetryable(err error) bool {
+	var fkErr *inttypes.FlagKitError
+	if stderrors.As(err, &fkErr) {
+		return fkErr.Recoverable
+	}
+	return true
+}
@@ -0,0 +1,26 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/teracrafts/flagkit-go/types"
+)
+
+func TestApplyInitAfterClose(t *testing.T) {
+	c := newTestClient(t)
+	require.NoError(t, c.Close())
+
+	// A response arriving after Close is discarded
+	c.applyInit(context.Background(), &types.InitResponse{
+		EnvironmentID: "env-1",
+		Flags:         []types.FlagState{{Key: "checkout", Value: true, Enabled: true, Version: 1}},
+	})
+
+	assert.False(t, c.IsReady())
+	assert.Empty(t, c.environmentID)
+	assert.False(t, c.GetBooleanValue("checkout", false))
+}
@@ -0,0 +1,239 @@
+package client
+
+import (
+	"reflect"
+	"sync"
+	"time"
+
+	inttypes "github.com/teracrafts/flagkit-go/internal/types"
+)
+
+// FlagChangeListener is called when a flag changes.
+// old and new describe the flag before and after the change; a flag that did
+// not exist (or was deleted) is reported with ReasonFlagNotFound.
+type FlagChangeListener func(old, new EvaluationResult)
+
+// changeListeners holds registered flag change listeners.
+type changeListeners struct {
+	nextID uint64
+	byKey  map[string]map[uint64]FlagChangeListener
+	any    map[uint64]FlagChangeListener
+	mu     sync.RWMutex
+}
+
+// flagChange describes a single flag change.
+type flagChange struct {
+	key string
+	old *inttypes.FlagState
+	new *inttypes.FlagState
+}
+
+// newChangeListeners creates an empty listener registry.
+func newChangeListeners() *changeListeners {
+	return &changeListeners{
+		byKey: make(map[string]map[uint64]FlagChangeListener),
+		any:   make(map[uint64]FlagChangeListener),
+	}
+}
+
+// empty returns whether no listeners are registered.
+func (l *changeListeners) empty() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.byKey) == 0 && len(l.any) == 0
+}
+
+// add registers a listener for key, or for all flags if key is empty.
+// Returns a function that removes the listener.
+func (l *changeListeners) add(key string, listener FlagChangeListener) func() {
+	l.mu.Lock()
+	l.nextID++
+	id := l.nextID
+	if key == "" {
+		l.any[id] = listener
+	} else {
+		if l.byKey[key] == nil {
+			l.byKey[key] = make(map[uint64]FlagChangeListener)
+		}
+		l.byKey[key][id] = listener
+	}
+	l.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			if key == "" {
+				delete(l.any, id)
+				return
+			}
+			delete(l.byKey[key], id)
+			if len(l.byKey[key]) == 0 {
+				delete(l.byKey, key)
+			}
+		})
+	}
+}
+
+// forKey returns the listeners interested in changes to key.
+func (l *changeListeners) forKey(key string) []FlagChangeListener {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	listeners := make([]FlagChangeListener, 0, len(l.byKey[key])+len(l.any))
+	for _, listener := range l.byKey[key] {
+		listeners = append(listeners, listener)
+	}
+	for _, listener := range l.any {
+		listeners = append(listeners, listener)
+	}
+	return listeners
+}
+
+// OnFlagChange registers a listener that is called whenever the flag with the
+// given key changes, whether from polling, streaming or initialization.
+// Returns a function that unsubscribes the listener.
+func (c *Client) OnFlagChange(key string, listener FlagChangeListener) func() {
+	if key == "" || listener == nil {
+		return func() {}
+	}
+	return c.listeners.add(key, listener)
+}
+
+// OnAnyChange registers a listener that is called whenever any flag changes.
+// Returns a function that unsubscribes the listener.
+func (c *Client) OnAnyChange(listener FlagChangeListener) func() {
+	if listener == nil {
+		return func() {}
+	}
+	return c.listeners.add("", listener)
+}
+
+// storeFlags stores flags in the cache and flag store and notifies change listeners.
+func (c *Client) storeFlags(flags []inttypes.FlagState, ttl ...time.Duration) {
+	c.invalidateContextCache()
+	c.writeStoreFlags(flags)
+	if c.listeners.empty() {
+		c.cache.SetMany(flags, ttl...)
+		return
+	}
+
+	changes := make([]flagChange, 0, len(flags))
+	for i := range flags {
+		old := c.cache.GetStale(flags[i].Key)
+		c.cache.Set(flags[i].Key, flags[i], ttl...)
+		if flagChanged(old, &flags[i]) {
+			changes = append(changes, flagChange{key: flags[i].Key, old: old, new: &flags[i]})
+		}
+	}
+	c.notifyFlagChanges(changes)
+}
+
+// removeFlag removes a flag from the cache and notifies change listeners.
+func (c *Client) removeFlag(key string) {
+	c.writeStoreDelete(key)
+	old := c.cache.GetStale(key)
+	if !c.cache.Delete(key) {
+		return
+	}
+	c.invalidateContextCache()
+	c.notifyFlagChanges([]flagChange{{key: key, old: old}})
+}
+
+// replaceFlags replaces the cache contents and notifies change listeners,
+// including for flags that are no longer present.
+func (c *Client) replaceFlags(flags []inttypes.FlagState, ttl ...time.Duration) {
+	c.invalidateContextCache()
+	c.writeStoreInit(flags)
+	if c.listeners.empty() {
+		c.cache.Clear()
+		c.cache.SetMany(flags, ttl...)
+		return
+	}
+
+	previous := make(map[string]inttypes.FlagState)
+	for _, f := range c.cache.GetAll() {
+		previous[f.Key] = f
+	}
+
+	c.cache.Clear()
+	c.cache.SetMany(flags, ttl...)
+
+	changes := make([]flagChange, 0, len(flags))
+	for i := range flags {
+		var old *inttypes.FlagState
+		if f, ok := previous[flags[i].Key]; ok {
+			old = &f
+			delete(previous, flags[i].Key)
+		}
+		if flagChanged(old, &flags[i]) {
+			changes = append(changes, flagChange{key: flags[i].Key, old: old, new: &flags[i]})
+		}
+	}
+	for key, f := range previous {
+		f := f
+		changes = append(changes, flagChange{key: key, old: &f})
+	}
+	c.notifyFlagChanges(changes)
+}
+
+// notifyFlagChanges calls the registered listeners for each change.
+func (c *Client) notifyFlagChanges(changes []flagChange) {
+	for _, change := range changes {
+		listeners := c.listeners.forKey(change.key)
+		if len(listeners) == 0 {
+			continue
+		}
+
+		old := flagResult(change.key, change.old)
+		updated := flagResult(change.key, change.new)
+		for _, listener := range listeners {
+			c.callListener(listener, old, updated)
+		}
+	}
+}
+
+// callListener invokes a listener, recovering from panics so that one faulty
+// listener cannot break flag updates.
+func (c *Client) callListener(listener FlagChangeListener, old, updated EvaluationResult) {
+	defer recoverListener(c.logger, "Flag change listener panic recovered", "key", updated.FlagKey)
+	listener(old, updated)
+}
+
+// recoverListener recovers from a listener panic and logs it with message and
+// the given key-value pairs. It must be deferred directly.
+func recoverListener(logger Logger, message string, keysAndValues ...any) {
+	if r := recover(); r != nil {
+		logger.Error(message, append(keysAndValues, "error", r)...)
+	}
+}
+
+// flagChanged returns whether a flag differs from its previous state.
+func flagChanged(old, updated *inttypes.FlagState) bool {
+	if old == nil || updated == nil {
+		return old != updated
+	}
+	return old.Enabled != updated.Enabled ||
+		old.Version != updated.Version ||
+		!reflect.DeepEqual(old.Value, updated.Value)
+}
+
+// flagResult converts a cached flag state into an EvaluationResult.
+func flagResult(key string, flag *inttypes.FlagState) EvaluationResult {
+	if flag == nil {
+		return EvaluationResult{
+			FlagKey:   key,
+			Reason:    ReasonFlagNotFound,
+			Timestamp: time.Now(),
+		}
+	}
+	return EvaluationResult{
+		FlagKey:   key,
+		Value:     flag.Value,
+		Enabled:   flag.Enabled,
+		Reason:    ReasonCached,
+		Version:   flag.Version,
+		Timestamp: time.Now(),
+	}
+}
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/teracrafts/flagkit-go/config"
+	inttypes "github.com/teracrafts/flagkit-go/internal/types"
+)
+
+func newTestClient(t *testing.T, opts ...OptionFunc) *Client {
+	t.Helper()
+
+	opts = append([]OptionFunc{config.WithOffline()}, opts...)
+	c, err := NewClient("sdk_test_key_12345", opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func TestOnFlagChangeFiresOnValueChange(t *testing.T) {
+	c := newTestClient(t, config.WithBootstrap(map[string]any{"kill-switch": false}))
+
+	var calls []EvaluationResult
+	c.OnFlagChange("kill-switch", func(old, new EvaluationResult) {
+		calls = append(calls, old, new)
+	})
+
+	c.handleStreamFlagUpdate(&inttypes.FlagState{
+		Key: "kill-switch", Value: true, Enabled: true, Version: 2, FlagType: inttypes.FlagTypeBoolean,
+	})
+
+	require.Len(t, calls, 2)
+	assert.Equal(t, false, calls[0].Value)
+	assert.Equal(t, 0, calls[0].Version)
+	assert.Equal(t, true, calls[1].Value)
+	assert.Equal(t, 2, calls[1].Version)
+	assert.Equal(t, ReasonCached, calls[1].Reason)
+}
+
+func TestOnFlagChangeIgnoresUnchangedAndOtherFlags(t *testing.T) {
+	c := newTestClient(t)
+
+	flag := inttypes.FlagState{Key: "a", Value: "x", Enabled: true, Version: 1, FlagType: inttypes.FlagTypeString}
+	c.storeFlags([]inttypes.FlagState{flag})
+
+	count := 0
+	c.OnFlagChange("a", func(old, new EvaluationResult) { count++ })
+
+	c.storeFlags([]inttypes.FlagState{flag})
+	c.storeFlags([]inttypes.FlagState{{Key: "b", Value: 1.0, Version: 1, FlagType: inttypes.FlagTypeNumber}})
+
+	assert.Equal(t, 0, count)
+}
+
+func TestOnAnyChangeReportsDeletesAndResets(t *testing.T) {
+	c := newTestClient(t)
+	c.storeFlags([]inttypes.FlagState{
+		{Key: "a", Value: true, Enabled: true, Version: 1},
+		{Key: "b", Value: true, Enabled: true, Version: 1},
+	})
+
+	changes := make(map[string][2]EvaluationResult)
+	c.OnAnyChange(func(old, new EvaluationResult) {
+		changes[new.FlagKey] = [2]EvaluationResult{old, new}
+	})
+
+	c.handleStreamFlagDelete("a")
+	require.Contains(t, changes, "a")
+	assert.Equal(t, ReasonFlagNotFound, changes["a"][1].Reason)
+	assert.Equal(t, true, changes["a"][0].Value)
+
+	changes = make(map[string][2]EvaluationResult)
+	c.handleStreamFlagsReset([]*inttypes.FlagState{{Key: "c", Value: "new", Enabled: true, Version: 1}})
+
+	assert.Len(t, changes, 2)
+	assert.Equal(t, ReasonFlagNotFound, changes["b"][1].Reason)
+	assert.Equal(t, ReasonFlagNotFound, changes["c"][0].Reason)
+	assert.Equal(t, "new", changes["c"][1].Value)
+}
+
+func TestUnsubscribeStopsNotifications(t *testing.T) {
+	c := newTestClient(t)
+
+	count := 0
+	unsubscribe := c.OnFlagChange("a", func(old, new EvaluationResult) { count++ })
+
+	c.storeFlags([]inttypes.FlagState{{Key: "a", Value: 1.0, Version: 1}})
+	unsubscribe()
+	unsubscribe()
+	c.storeFlags([]inttypes.FlagState{{Key: "a", Value: 2.0, Version: 2}})
+
+	assert.Equal(t, 1, count)
+	assert.True(t, c.listeners.empty())
+}
+
+func TestListenerPanicDoesNotBlockOthers(t *testing.T) {
+	c := newTestClient(t)
+
+	called := false
+	c.OnAnyChange(func(old, new EvaluationResult) { panic("boom") })
+	c.OnAnyChange(func(old, new EvaluationResult) { called = true })
+
+	c.storeFlags([]inttypes.FlagState{{Key: "a", Value: true, Version: 1}})
+
+	assert.True(t, called)
+	assert.True(t, c.HasFlag("a"))
+}
+
+func TestCircuitListenerPanicDoesNotBlockOthers(t *testing.T) {
+	c := newTestClient(t)
+
+	called := false
+	c.OnCircuitStateChange(func(name string, from, to CircuitState) { panic("boom") })
+	c.OnCircuitStateChange(func(name string, from, to CircuitState) { called = true })
+
+	c.circuits.notify(CircuitAPI, CircuitClosed, CircuitOpen)
+
+	assert.True(t, called)
+}
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"time"
+
+	"github.com/teracrafts/flagkit-go/internal/core"
+	"github.com/teracrafts/flagkit-go/internal/http"
+	"github.com/teracrafts/flagkit-go/types"
+)
+
+// Metrics receives measurements from clients.
+type Metrics = types.Metrics
+
+// cacheLookupRecorder returns a cache lookup callback that records lookups,
+// or nil without metrics.
+func cacheLookupRecorder(metrics Metrics) func(core.CacheResult) {
+	if metrics == nil {
+		return nil
+	}
+	return func(result core.CacheResult) {
+		metrics.RecordCacheLookup(types.CacheResult(result))
+	}
+}
+
+// requestRecorder returns a request callback that records API requests, or
+// nil without metrics.
+func requestRecorder(metrics Metrics) http.RequestCallback {
+	if metrics == nil {
+		return nil
+	}
+	return metrics.RecordRequest
+}
+
+// retryRecorder returns a retry callback that records retried requests, or
+// nil without metrics.
+func retryRecorder(metrics Metrics) http.RetryCallback {
+	if metrics == nil {
+		return nil
+	}
+	return func(endpoint string, attempt int) {
+		metrics.RecordRetry(endpoint)
+	}
+}
+
+// dropRecorder returns an event queue callback that records dropped events,
+// or nil without metrics.
+func dropRecorder(metrics Metrics) func(reason string, count int) {
+	if metrics == nil {
+		return nil
+	}
+	return metrics.RecordEventsDropped
+}
+
+// observeMetrics registers the client gauges and circuit state changes with
+// the metrics hook.
+func (c *Client) observeMetrics(metrics Metrics) {
+	c.circuits.add(func(name string, from, to CircuitState) {
+		metrics.RecordCircuitStateChange(name, from.String(), to.String())
+	})
+	c.unobserve = metrics.ObserveEvents(c.eventStats)
+}
+
+// eventStats returns the event gauges of the client.
+func (c *Client) eventStats() types.EventStats {
+	stats := types.EventStats{QueueDepth: c.eventQueue.QueueSize()}
+	if c.eventPersistence != nil {
+		if backlog, err := c.eventPersistence.Backlog(); err == nil {
+			stats.PersistedEvents = backlog
+		}
+	}
+	return stats
+}
+
+// recordEvaluation records an evaluation that started at start.
+func (c *Client) recordEvaluation(result *EvaluationResult, start time.Time) {
+	if c.options.Metrics == nil {
+		return
+	}
+	c.options.Metrics.RecordEvaluation(result.FlagKey, result.Reason, time.Since(start))
+}
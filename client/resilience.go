@@ -0,0 +1,169 @@
+package client
+
+import (
+	"sync"
+	"time"
+
+	"github.com/teracrafts/flagkit-go/internal/core"
+	"github.com/teracrafts/flagkit-go/internal/http"
+)
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState = http.CircuitState
+
+// CircuitState constant aliases
+const (
+	CircuitClosed   = http.CircuitClosed
+	CircuitOpen     = http.CircuitOpen
+	CircuitHalfOpen = http.CircuitHalfOpen
+)
+
+// Circuit breaker names passed to circuit state listeners.
+const (
+	// CircuitAPI is the circuit breaker of API requests.
+	CircuitAPI = "api"
+	// CircuitEvents is the circuit breaker of event requests, when events
+	// have their own.
+	CircuitEvents = "events"
+)
+
+// CircuitStateListener is called when a circuit breaker changes state.
+// name is CircuitAPI or CircuitEvents.
+type CircuitStateListener func(name string, from, to CircuitState)
+
+// circuitListeners holds registered circuit state listeners.
+type circuitListeners struct {
+	nextID    uint64
+	listeners map[uint64]CircuitStateListener
+	logger    Logger
+	mu        sync.RWMutex
+}
+
+// newCircuitListeners creates an empty listener registry.
+func newCircuitListeners(logger Logger) *circuitListeners {
+	return &circuitListeners{listeners: make(map[uint64]CircuitStateListener), logger: logger}
+}
+
+// add registers a listener and returns a function that removes it.
+func (l *circuitListeners) add(listener CircuitStateListener) func() {
+	l.mu.Lock()
+	l.nextID++
+	id := l.nextID
+	l.listeners[id] = listener
+	l.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			delete(l.listeners, id)
+		})
+	}
+}
+
+// notify calls all listeners with a state change.
+func (l *circuitListeners) notify(name string, from, to CircuitState) {
+	l.mu.RLock()
+	listeners := make([]CircuitStateListener, 0, len(l.listeners))
+	for _, listener := range l.listeners {
+		listeners = append(listeners, listener)
+	}
+	l.mu.RUnlock()
+
+	for _, listener := range listeners {
+		l.call(listener, name, from, to)
+	}
+}
+
+// call invokes a listener, recovering from panics so that one faulty
+// listener cannot break requests.
+func (l *circuitListeners) call(listener CircuitStateListener, name string, from, to CircuitState) {
+	defer recoverListener(l.logger, "Circuit state listener panic recovered", "circuit", name)
+	listener(name, from, to)
+}
+
+// OnCircuitStateChange registers a listener called when a circuit breaker
+// opens, half-opens or closes. Returns a function that removes the listener.
+func (c *Client) OnCircuitStateChange(listener CircuitStateListener) func() {
+	return c.circuits.add(listener)
+}
+
+// circuitConfig returns a circuit breaker configuration with unset fields
+// taken from the defaults, reporting state changes under the given name.
+func circuitConfig(config *http.CircuitBreakerConfig, name string, listeners *circuitListeners, logger Logger) *http.CircuitBreakerConfig {
+	result := *http.DefaultCircuitBreakerConfig()
+	if config != nil {
+		if config.FailureThreshold > 0 {
+			result.FailureThreshold = config.FailureThreshold
+		}
+		if config.SuccessThreshold > 0 {
+			result.SuccessThreshold = config.SuccessThreshold
+		}
+		if config.ResetTimeout > 0 {
+			result.ResetTimeout = config.ResetTimeout
+		}
+		if config.HalfOpenMaxAllowed > 0 {
+			result.HalfOpenMaxAllowed = config.HalfOpenMaxAllowed
+		}
+	}
+
+	var userCallback func(from, to CircuitState)
+	if config != nil {
+		userCallback = config.OnStateChange
+	}
+	result.OnStateChange = func(from, to CircuitState) {
+		logger.Info("Circuit breaker state changed", "circuit", name, "from", from.String(), "to", to.String())
+		if userCallback != nil {
+			userCallback(from, to)
+		}
+		listeners.notify(name, from, to)
+	}
+	return &result
+}
+
+// retryConfig returns a retry policy with unset fields taken from the
+// defaults. attempts is the number of attempts used when none is set.
+func retryConfig(config *http.RetryConfig, attempts int) *http.RetryConfig {
+	result := http.RetryConfig{
+		MaxAttempts:       attempts,
+		BaseDelay:         time.Second,
+		MaxDelay:          30 * time.Second,
+		BackoffMultiplier: 2.0,
+		Jitter:            100 * time.Millisecond,
+	}
+	if result.MaxAttempts < 1 {
+		result.MaxAttempts = 1
+	}
+	return mergeRetryConfig(&result, config)
+}
+
+// redeliveryConfig returns the redelivery policy of failed event batches with
+// unset fields taken from the defaults.
+func redeliveryConfig(config *http.RetryConfig) *http.RetryConfig {
+	return mergeRetryConfig(core.DefaultRedeliveryConfig(), config)
+}
+
+// mergeRetryConfig overrides the defaults with the set fields of config.
+func mergeRetryConfig(result *http.RetryConfig, config *http.RetryConfig) *http.RetryConfig {
+	if config == nil {
+		return result
+	}
+
+	if config.MaxAttempts > 0 {
+		result.MaxAttempts = config.MaxAttempts
+	}
+	if config.BaseDelay > 0 {
+		result.BaseDelay = config.BaseDelay
+	}
+	if config.MaxDelay > 0 {
+		result.MaxDelay = config.MaxDelay
+	}
+	if config.BackoffMultiplier > 0 {
+		result.BackoffMultiplier = config.BackoffMultiplier
+	}
+	if config.Jitter > 0 {
+		result.Jitter = config.Jitter
+	}
+	return result
+}
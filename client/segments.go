@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+
+	inttypes "github.com/teracrafts/flagkit-go/internal/types"
+)
+
+// loadSegments downloads segment definitions.
+func (c *Client) loadSegments(ctx context.Context) error {
+	resp, err := c.httpClient.GetWithContext(ctx, "/sdk/segments")
+	if err != nil {
+		return err
+	}
+
+	var data inttypes.SegmentsResponse
+	if err := json.Unmarshal(resp.Body, &data); err != nil {
+		return NewErrorWithCause(ErrInitFailed, "failed to parse segments response", err)
+	}
+
+	c.segments.SetSegments(data.Segments)
+
+	c.mu.Lock()
+	c.segmentsReady = true
+	c.mu.Unlock()
+
+	c.logger.Debug("Segment definitions loaded", "count", len(data.Segments))
+	return nil
+}
+
+// isSegmentsReady returns whether segment definitions are loaded.
+func (c *Client) isSegmentsReady() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.segmentsReady
+}
+
+// refreshSegments reloads segment definitions if they were loaded before.
+func (c *Client) refreshSegments(ctx context.Context) {
+	if !c.isSegmentsReady() {
+		return
+	}
+	if err := c.loadSegments(ctx); err != nil {
+		c.logger.Warn("Failed to refresh segment definitions", "error", err.Error())
+	}
+}
+
+// IsInSegment checks whether the context is a member of a segment.
+// Returns false if the segment is unknown or segments are not loaded.
+func (c *Client) IsInSegment(segmentKey string, ctx ...*EvaluationContext) bool {
+	return c.segments.Contains(segmentKey, c.internalContext(getContext(ctx)))
+}
@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/teracrafts/flagkit-go/internal/core"
+	inttypes "github.com/teracrafts/flagkit-go/internal/types"
+)
+
+// serverEvalBackoff is how long server evaluation is skipped after a failed
+// evaluate request, so that evaluations fall back to cached values instead of
+// each waiting for the request timeout during an outage.
+const serverEvalBackoff = 5 * time.Second
+
+// serverEvaluate returns the server-evaluated flags for a context, from the
+// per-context cache or by calling the evaluate endpoint.
+// All flags are evaluated in one request, so EvaluateAll costs a single call
+// per context.
+func (c *Client) serverEvaluate(reqCtx context.Context, ctx *EvaluationContext) (map[string]inttypes.EvaluatedFlag, error) {
+	contextMap := ctx.StripPrivateAttributes().ToMap()
+	fingerprint, err := core.ContextFingerprint(contextMap)
+	if err != nil {
+		// Contexts that cannot be serialized cannot be sent either
+		return nil, NewErrorWithCause(ErrEvalError, "failed to serialize evaluation context", err)
+	}
+
+	if flags, ok := c.contextCache.Get(fingerprint); ok {
+		return flags, nil
+	}
+
+	c.mu.RLock()
+	retryAt := c.serverRetryAt
+	c.mu.RUnlock()
+	if time.Now().Before(retryAt) {
+		return nil, NewError(ErrEvalError, "server evaluation unavailable after a failed request")
+	}
+
+	resp, err := c.httpClient.PostWithContext(reqCtx, "/sdk/evaluate", inttypes.EvaluateRequest{Context: contextMap})
+	if err != nil {
+		c.mu.Lock()
+		c.serverRetryAt = time.Now().Add(serverEvalBackoff)
+		c.mu.Unlock()
+		return nil, err
+	}
+
+	var data inttypes.EvaluateResponse
+	if err := json.Unmarshal(resp.Body, &data); err != nil {
+		return nil, NewErrorWithCause(ErrEvalError, "failed to parse evaluate response", err)
+	}
+
+	c.contextCache.Set(fingerprint, data.Flags)
+	c.logger.Debug("Flags evaluated by server", "count", len(data.Flags))
+
+	flags, _ := c.contextCache.Get(fingerprint)
+	return flags, nil
+}
+
+// serverResult evaluates a flag on the server for the resolved context.
+// Returns false if server evaluation is not possible or the flag is unknown,
+// in which case the caller falls back to the shared cache.
+func (c *Client) serverResult(reqCtx context.Context, key string, defaultValue any, ctx *EvaluationContext, expectedType FlagType) (*EvaluationResult, bool) {
+	resolved := c.resolveContext(ctx)
+	if resolved == nil {
+		return nil, false
+	}
+
+	flags, err := c.serverEvaluate(reqCtx, resolved)
+	if err != nil {
+		c.logger.Warn("Server evaluation failed, using cached values", "error", err.Error())
+		return nil, false
+	}
+
+	flag, ok := flags[key]
+	if !ok {
+		return nil, false
+	}
+
+	if expectedType != "" && FlagType(flag.FlagType) != expectedType {
+		c.logger.Warn("Flag type mismatch",
+			"key", key,
+			"expected", expectedType,
+			"got", flag.FlagType,
+		)
+		return createDefaultResult(key, defaultValue, ReasonError), true
+	}
+
+	reason := EvaluationReason(flag.Reason)
+	if reason == "" {
+		reason = ReasonTargeted
+	}
+
+	return &EvaluationResult{
+		FlagKey:   key,
+		Value:     flag.Value,
+		Enabled:   flag.Enabled,
+		Reason:    reason,
+		Version:   flag.Version,
+		Timestamp: time.Now(),
+	}, true
+}
+
+// isServerEvalEnabled returns whether per-context server evaluation should be
+// used. Daemon clients never call the API.
+func (c *Client) isServerEvalEnabled() bool {
+	return c.contextCache != nil && !c.options.Offline && !c.isDaemonMode()
+}
+
+// invalidateContextCache drops server-evaluated results after flags change.
+func (c *Client) invalidateContextCache() {
+	if c.contextCache != nil {
+		c.contextCache.Clear()
+	}
+}
@@ -0,0 +1,164 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/teracrafts/flagkit-go/internal/storage"
+	inttypes "github.com/teracrafts/flagkit-go/internal/types"
+)
+
+// newSnapshotStore returns the configured snapshot store, if any.
+func newSnapshotStore(options *Options) SnapshotStore {
+	if options.SnapshotStore != nil {
+		return options.SnapshotStore
+	}
+	if options.SnapshotPath != "" {
+		return storage.NewFileSnapshotStore(options.SnapshotPath)
+	}
+	return nil
+}
+
+// loadSnapshot loads the last known good flag set into the cache.
+// Snapshots of another API key, or older than SnapshotMaxAge, are ignored.
+func (c *Client) loadSnapshot() {
+	if c.snapshots == nil {
+		return
+	}
+
+	data, err := c.snapshots.Load()
+	if err != nil {
+		c.logger.Warn("Failed to load flag snapshot", "error", err.Error())
+		return
+	}
+	if len(data) == 0 {
+		return
+	}
+
+	if storage.IsEncrypted(string(data)) {
+		if c.encryption == nil {
+			c.logger.Warn("Flag snapshot is encrypted but cache encryption is disabled")
+			return
+		}
+		plaintext, err := c.encryption.Decrypt(string(data))
+		if err != nil {
+			c.logger.Warn("Failed to decrypt flag snapshot", "error", err.Error())
+			return
+		}
+		data = []byte(plaintext)
+	}
+
+	var snapshot inttypes.FlagSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		c.logger.Warn("Failed to parse flag snapshot", "error", err.Error())
+		return
+	}
+
+	if snapshot.KeyHash != c.snapshotKeyHash() {
+		c.logger.Warn("Ignoring flag snapshot of another environment", "environmentId", snapshot.EnvironmentID)
+		return
+	}
+
+	serverTime, err := time.Parse(time.RFC3339, snapshot.ServerTime)
+	if err != nil || time.Since(serverTime) > c.options.SnapshotMaxAge {
+		c.logger.Warn("Ignoring stale flag snapshot", "serverTime", snapshot.ServerTime)
+		return
+	}
+
+	c.cache.SetMany(snapshot.Flags, c.options.CacheTTL)
+	c.recordServerFlags(snapshot.Flags, true)
+
+	c.mu.Lock()
+	c.environmentID = snapshot.EnvironmentID
+	c.snapshotLoaded = true
+	c.mu.Unlock()
+
+	c.logger.Info("Flag snapshot loaded",
+		"flag_count", len(snapshot.Flags),
+		"serverTime", snapshot.ServerTime,
+	)
+}
+
+// saveSnapshot saves the flags received from the server as the last known
+// good flag set. Bootstrap values and flags read from the flag store are not
+// saved.
+// serverTime is the server time the flags are current at; if empty, the
+// current time is used.
+func (c *Client) saveSnapshot(serverTime string) {
+	if c.snapshots == nil {
+		return
+	}
+	if serverTime == "" {
+		serverTime = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	c.mu.RLock()
+	environmentID := c.environmentID
+	flags := make([]inttypes.FlagState, 0, len(c.serverFlags))
+	for _, flag := range c.serverFlags {
+		flags = append(flags, flag)
+	}
+	c.mu.RUnlock()
+
+	data, err := json.Marshal(inttypes.FlagSnapshot{
+		EnvironmentID: environmentID,
+		KeyHash:       c.snapshotKeyHash(),
+		ServerTime:    serverTime,
+		Flags:         flags,
+	})
+	if err != nil {
+		c.logger.Warn("Failed to serialize flag snapshot", "error", err.Error())
+		return
+	}
+
+	if c.encryption != nil {
+		encrypted, err := c.encryption.Encrypt(string(data))
+		if err != nil {
+			c.logger.Warn("Failed to encrypt flag snapshot", "error", err.Error())
+			return
+		}
+		data = []byte(encrypted)
+	}
+
+	if err := c.snapshots.Save(data); err != nil {
+		c.logger.Warn("Failed to save flag snapshot", "error", err.Error())
+	}
+}
+
+// recordServerFlags records flags received from the server for the snapshot.
+// With replace, flags that are not included are dropped.
+func (c *Client) recordServerFlags(flags []inttypes.FlagState, replace bool) {
+	if c.snapshots == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if replace || c.serverFlags == nil {
+		c.serverFlags = make(map[string]inttypes.FlagState, len(flags))
+	}
+	for _, flag := range flags {
+		c.serverFlags[flag.Key] = flag
+	}
+}
+
+// forgetServerFlag drops a flag deleted on the server from the snapshot.
+func (c *Client) forgetServerFlag(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.serverFlags, key)
+}
+
+// snapshotKeyHash returns a hash identifying the API key, so that snapshots
+// are not shared between environments. The key itself is never persisted.
+func (c *Client) snapshotKeyHash() string {
+	return apiKeyHash(c.options.APIKey)
+}
+
+// apiKeyHash returns a hash identifying an API key without revealing it.
+func apiKeyHash(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/teracrafts/flagkit-go/config"
+	"github.com/teracrafts/flagkit-go/internal/storage"
+	inttypes "github.com/teracrafts/flagkit-go/internal/types"
+)
+
+// memorySnapshotStore is an in-memory SnapshotStore.
+type memorySnapshotStore struct {
+	data []byte
+}
+
+func (s *memorySnapshotStore) Load() ([]byte, error) { return s.data, nil }
+
+func (s *memorySnapshotStore) Save(data []byte) error {
+	s.data = data
+	return nil
+}
+
+func saveTestSnapshot(t *testing.T, serverTime string, opts ...OptionFunc) {
+	t.Helper()
+
+	c := newTestClient(t, opts...)
+	c.environmentID = "env-1"
+	flags := []inttypes.FlagState{
+		{Key: "checkout", Value: true, Enabled: true, Version: 4, FlagType: inttypes.FlagTypeBoolean},
+	}
+	c.storeFlags(flags)
+	c.recordServerFlags(flags, true)
+	c.saveSnapshot(serverTime)
+}
+
+func TestSnapshotWarmStart(t *testing.T) {
+	store := &memorySnapshotStore{}
+	saveTestSnapshot(t, "", config.WithSnapshotStore(store))
+	require.NotEmpty(t, store.data)
+
+	c := newTestClient(t, config.WithSnapshotStore(store))
+
+	result := c.Evaluate("checkout")
+	assert.Equal(t, true, result.Value)
+	assert.Equal(t, ReasonCached, result.Reason)
+	assert.Equal(t, "env-1", c.environmentID)
+}
+
+func TestSnapshotEncrypted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+	saveTestSnapshot(t, "", config.WithSnapshot(path), config.WithCacheEncryption())
+
+	data, err := storage.NewFileSnapshotStore(path).Load()
+	require.NoError(t, err)
+	assert.True(t, storage.IsEncrypted(string(data)))
+	assert.NotContains(t, string(data), "checkout")
+
+	c := newTestClient(t, config.WithSnapshot(path), config.WithCacheEncryption())
+	assert.True(t, c.GetBooleanValue("checkout", false))
+
+	// An encrypted snapshot cannot be read without encryption enabled
+	plain := newTestClient(t, config.WithSnapshot(path))
+	assert.False(t, plain.GetBooleanValue("checkout", false))
+}
+
+func TestSnapshotRejectsStaleSnapshot(t *testing.T) {
+	store := &memorySnapshotStore{}
+	saveTestSnapshot(t, time.Now().Add(-2*time.Hour).UTC().Format(time.RFC3339), config.WithSnapshotStore(store))
+
+	c := newTestClient(t, config.WithSnapshotStore(store), config.WithSnapshotMaxAge(time.Hour))
+
+	assert.False(t, c.GetBooleanValue("checkout", false))
+}
+
+func TestSnapshotRejectsOtherEnvironment(t *testing.T) {
+	store := &memorySnapshotStore{}
+	saveTestSnapshot(t, "", config.WithSnapshotStore(store))
+
+	c, err := NewClient("sdk_other_key_67890", config.WithOffline(), config.WithSnapshotStore(store))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+
+	assert.False(t, c.GetBooleanValue("checkout", false))
+}
+
+func TestSnapshotSavesOnlyServerFlags(t *testing.T) {
+	store := &memorySnapshotStore{}
+	c := newTestClient(t, config.WithSnapshotStore(store), config.WithBootstrap(map[string]any{"banner": "hello"}))
+
+	flags := []inttypes.FlagState{
+		{Key: "checkout", Value: true, Enabled: true, Version: 4, FlagType: inttypes.FlagTypeBoolean},
+		{Key: "legacy", Value: true, Enabled: true, Version: 1, FlagType: inttypes.FlagTypeBoolean},
+	}
+	c.storeFlags(flags)
+	c.recordServerFlags(flags, true)
+	c.removeFlag("legacy")
+	c.forgetServerFlag("legacy")
+	c.saveSnapshot("")
+
+	// Bootstrap values and deleted flags are not saved
+	restarted := newTestClient(t, config.WithSnapshotStore(store))
+	assert.True(t, restarted.GetBooleanValue("checkout", false))
+	assert.False(t, restarted.GetBooleanValue("legacy", false))
+	assert.Equal(t, "default", restarted.GetStringValue("banner", "default"))
+}
@@ -0,0 +1,204 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/teracrafts/flagkit-go/internal/core"
+	inttypes "github.com/teracrafts/flagkit-go/internal/types"
+	"github.com/teracrafts/flagkit-go/types"
+)
+
+// flagDefinitionsRefreshDelay is how long rule definitions are reloaded after
+// a streamed change, so that a burst of changes causes a single reload.
+const flagDefinitionsRefreshDelay = time.Second
+
+// shouldStream returns whether streaming should be used for this init response.
+func (c *Client) shouldStream(data *types.InitResponse) bool {
+	if !c.options.Streaming {
+		return false
+	}
+	if data.StreamingURL != "" {
+		return true
+	}
+	return data.Metadata != nil && data.Metadata.Features != nil && data.Metadata.Features.Streaming
+}
+
+// startStreaming connects to the SSE stream for real-time flag updates.
+// A configured streaming base URL takes precedence over streamingURL; if both
+// are empty, the API base URL is used.
+func (c *Client) startStreaming(streamingURL string) {
+	if c.options.StreamingBaseURL != "" {
+		streamingURL = strings.TrimRight(c.options.StreamingBaseURL, "/")
+	}
+	if streamingURL == "" {
+		streamingURL = c.httpClient.GetBaseURL()
+	}
+
+	c.mu.Lock()
+	if c.streamingManager != nil || c.closed {
+		c.mu.Unlock()
+		return
+	}
+	streamingConfig := core.DefaultStreamingConfig()
+	streamingConfig.HTTPClient = c.httpClient.StreamingClient()
+	streamingConfig.OnConnected = c.handleStreamConnected
+	c.streamingManager = core.NewStreamingManager(
+		streamingURL,
+		c.httpClient.GetActiveAPIKey,
+		streamingConfig,
+		c.handleStreamFlagUpdate,
+		c.handleStreamFlagDelete,
+		c.handleStreamFlagsReset,
+		c.handleStreamFallback,
+		c.options.OnSubscriptionError,
+		c.options.OnConnectionLimitError,
+		c.logger,
+	)
+	sm := c.streamingManager
+	c.mu.Unlock()
+
+	c.logger.Debug("Starting streaming", "url", streamingURL)
+	sm.Connect()
+}
+
+// getStreamingManager returns the streaming manager, if streaming has started.
+func (c *Client) getStreamingManager() *core.StreamingManager {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.streamingManager
+}
+
+// handleStreamFlagUpdate applies a flag_updated event to the cache.
+func (c *Client) handleStreamFlagUpdate(flag *inttypes.FlagState) {
+	if flag == nil || flag.Key == "" {
+		return
+	}
+
+	c.storeFlags([]inttypes.FlagState{*flag}, c.options.CacheTTL)
+	c.recordServerFlags([]inttypes.FlagState{*flag}, false)
+	c.saveSnapshot("")
+	c.logger.Debug("Flag updated via stream", "key", flag.Key, "version", flag.Version)
+
+	c.scheduleFlagDefinitionsRefresh()
+
+	if c.options.OnUpdate != nil {
+		c.options.OnUpdate([]FlagState{toPublicFlagState(*flag)})
+	}
+}
+
+// handleStreamFlagDelete applies a flag_deleted event to the cache.
+func (c *Client) handleStreamFlagDelete(key string) {
+	c.removeFlag(key)
+	c.forgetServerFlag(key)
+	c.saveSnapshot("")
+	if c.evaluator != nil {
+		c.evaluator.Delete(key)
+	}
+	c.logger.Debug("Flag deleted via stream", "key", key)
+}
+
+// handleStreamFlagsReset applies a flags_reset event, replacing the cache contents.
+func (c *Client) handleStreamFlagsReset(flags []*inttypes.FlagState) {
+	internalFlags := make([]inttypes.FlagState, 0, len(flags))
+	for _, f := range flags {
+		if f != nil {
+			internalFlags = append(internalFlags, *f)
+		}
+	}
+
+	c.replaceFlags(internalFlags, c.options.CacheTTL)
+	c.recordServerFlags(internalFlags, true)
+	c.saveSnapshot("")
+	c.logger.Debug("Flags reset via stream", "count", len(internalFlags))
+
+	c.scheduleFlagDefinitionsRefresh()
+
+	if c.options.OnUpdate != nil {
+		publicFlags := make([]FlagState, len(internalFlags))
+		for i, f := range internalFlags {
+			publicFlags[i] = toPublicFlagState(f)
+		}
+		c.options.OnUpdate(publicFlags)
+	}
+}
+
+// handleStreamFallback starts polling when streaming is unavailable.
+func (c *Client) handleStreamFallback() {
+	c.logger.Info("Streaming unavailable, falling back to polling")
+
+	c.mu.RLock()
+	interval := c.pollingInterval
+	c.mu.RUnlock()
+
+	c.startPolling(interval)
+}
+
+// handleStreamConnected stops polling started by handleStreamFallback once
+// the stream is connected again.
+func (c *Client) handleStreamConnected() {
+	c.mu.Lock()
+	pm := c.pollingManager
+	c.pollingManager = nil
+	c.mu.Unlock()
+
+	if pm != nil {
+		pm.Stop()
+		c.logger.Info("Streaming reconnected, stopped polling")
+	}
+}
+
+// scheduleFlagDefinitionsRefresh reloads rule definitions after
+// flagDefinitionsRefreshDelay. Changes streamed before the reload starts are
+// covered by it and do not schedule another.
+func (c *Client) scheduleFlagDefinitionsRefresh() {
+	if !c.isLocalEvalActive() {
+		return
+	}
+
+	c.mu.Lock()
+	if c.rulesPending || c.closed {
+		c.mu.Unlock()
+		return
+	}
+	c.rulesPending = true
+	c.mu.Unlock()
+
+	go func() {
+		select {
+		case <-time.After(flagDefinitionsRefreshDelay):
+		case <-c.done:
+			return
+		}
+
+		c.mu.Lock()
+		c.rulesPending = false
+		c.mu.Unlock()
+
+		c.refreshFlagDefinitions(context.Background())
+	}()
+}
+
+// refreshFlagDefinitions reloads rule definitions after a streamed change so
+// that local evaluation does not serve outdated rules.
+func (c *Client) refreshFlagDefinitions(ctx context.Context) {
+	if !c.isLocalEvalActive() {
+		return
+	}
+	if err := c.loadFlagDefinitions(ctx); err != nil {
+		c.logger.Warn("Failed to refresh flag definitions", "error", err.Error())
+	}
+}
+
+// toPublicFlagState converts an internal FlagState to the public type.
+func toPublicFlagState(f inttypes.FlagState) FlagState {
+	return FlagState{
+		Key:          f.Key,
+		Value:        f.Value,
+		Enabled:      f.Enabled,
+		Version:      f.Version,
+		FlagType:     FlagType(f.FlagType),
+		LastModified: f.LastModified,
+	}
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/teracrafts/flagkit-go/config"
+	inttypes "github.com/teracrafts/flagkit-go/internal/types"
+)
+
+func TestStreamUpdatesCoalesceDefinitionRefreshes(t *testing.T) {
+	var rulesRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/sdk/rules" {
+			rulesRequests.Add(1)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"flags":[]}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, config.WithLocalEvaluation(), config.WithBaseURL(server.URL))
+	c.localEvalActive = true
+
+	for i := 1; i <= 5; i++ {
+		c.handleStreamFlagUpdate(&inttypes.FlagState{
+			Key: "checkout", Value: true, Enabled: true, Version: i, FlagType: inttypes.FlagTypeBoolean,
+		})
+	}
+	assert.Equal(t, int32(0), rulesRequests.Load())
+
+	require.Eventually(t, func() bool {
+		return rulesRequests.Load() == 1
+	}, 3*flagDefinitionsRefreshDelay, 50*time.Millisecond)
+
+	// Changes streamed after the reload schedule another one
+	c.handleStreamFlagUpdate(&inttypes.FlagState{
+		Key: "checkout", Value: false, Enabled: true, Version: 6, FlagType: inttypes.FlagTypeBoolean,
+	})
+	require.Eventually(t, func() bool {
+		return rulesRequests.Load() == 2
+	}, 3*flagDefinitionsRefreshDelay, 50*time.Millisecond)
+}
+
+func TestStreamReconnectStopsPolling(t *testing.T) {
+	c := newTestClient(t, config.WithStreaming())
+
+	c.handleStreamFallback()
+	pm := c.getPollingManager()
+	require.NotNil(t, pm)
+	assert.True(t, pm.IsActive())
+
+	c.handleStreamConnected()
+	assert.Nil(t, c.getPollingManager())
+	assert.False(t, pm.IsActive())
+
+	// Polling starts again if the stream fails again
+	c.handleStreamFallback()
+	assert.NotNil(t, c.getPollingManager())
+}
@@ -0,0 +1,88 @@
+// Command flagkit-relay runs a FlagKit relay proxy.
+//
+// The relay keeps the flags of one or more environments up to date and serves
+// them to SDKs on the internal network, so that only the relay connects to
+// the FlagKit API. Point SDKs at the relay with WithBaseURL.
+//
+// SDK keys are read from the FLAGKIT_RELAY_KEYS environment variable as a
+// comma-separated list, to keep them out of process listings:
+//
+//	FLAGKIT_RELAY_KEYS=sdk_key_one,sdk_key_two flagkit-relay -addr :8030 -streaming
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/teracrafts/flagkit-go/relay"
+	"github.com/teracrafts/flagkit-go/types"
+)
+
+func main() {
+	addr := flag.String("addr", ":8030", "address to listen on")
+	baseURL := flag.String("base-url", "", "FlagKit API base URL")
+	streaming := flag.Bool("streaming", false, "use streaming for real-time updates when available")
+	pollingInterval := flag.Duration("polling-interval", relay.DefaultPollingInterval, "interval for polling the API for updates")
+	eventBufferSize := flag.Int("event-buffer-size", relay.DefaultEventBufferSize, "maximum number of events buffered per environment")
+	eventFlushInterval := flag.Duration("event-flush-interval", relay.DefaultEventFlushInterval, "interval for forwarding buffered events")
+	debug := flag.Bool("debug", false, "enable debug logging")
+	flag.Parse()
+
+	var environments []relay.EnvironmentConfig
+	for _, key := range strings.Split(os.Getenv("FLAGKIT_RELAY_KEYS"), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			environments = append(environments, relay.EnvironmentConfig{APIKey: key})
+		}
+	}
+
+	r, err := relay.New(relay.Config{
+		Environments:       environments,
+		BaseURL:            *baseURL,
+		Streaming:          *streaming,
+		PollingInterval:    *pollingInterval,
+		EventBufferSize:    *eventBufferSize,
+		EventFlushInterval: *eventFlushInterval,
+		Logger:             types.NewDefaultLogger(*debug),
+	})
+	if err != nil {
+		log.Fatalf("flagkit-relay: %v", err)
+	}
+
+	// Environments that fail to load are retried in the background.
+	if err := r.Start(); err != nil {
+		log.Printf("flagkit-relay: %v", err)
+	}
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           r.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("flagkit-relay: %v", err)
+		}
+	}()
+	log.Printf("flagkit-relay: listening on %s", *addr)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	<-signals
+
+	// Streams stay open until closed by the relay, so close the relay first.
+	_ = r.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("flagkit-relay: %v", err)
+	}
+}
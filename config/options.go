@@ -1,9 +1,15 @@
 package config
 
 import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/teracrafts/flagkit-go/errors"
+	"github.com/teracrafts/flagkit-go/internal/core"
+	inthttp "github.com/teracrafts/flagkit-go/internal/http"
+	"github.com/teracrafts/flagkit-go/internal/persistence"
 	"github.com/teracrafts/flagkit-go/types"
 )
 
@@ -12,6 +18,38 @@ type Logger = types.Logger
 type FlagState = types.FlagState
 type ErrorSanitizationConfig = errors.ErrorSanitizationConfig
 type NullLogger = types.NullLogger
+type SnapshotStore = types.SnapshotStore
+type FlagStore = types.FlagStore
+type Metrics = types.Metrics
+type EventProcessor = types.EventProcessor
+
+// RetryConfig configures retries of failed requests.
+type RetryConfig = inthttp.RetryConfig
+
+// CircuitBreakerConfig configures a circuit breaker.
+type CircuitBreakerConfig = inthttp.CircuitBreakerConfig
+
+// EventCompression is the compression of rotated event log segments.
+type EventCompression = persistence.Compression
+
+// Event log compressions.
+const (
+	EventCompressionNone = persistence.CompressionNone
+	EventCompressionGzip = persistence.CompressionGzip
+	EventCompressionZstd = persistence.CompressionZstd
+)
+
+// EventOverflowPolicy is what the event queue does with new events while it
+// is full.
+type EventOverflowPolicy = core.OverflowPolicy
+
+// Event queue overflow policies.
+const (
+	EventOverflowDropNewest = core.OverflowDropNewest
+	EventOverflowDropOldest = core.OverflowDropOldest
+	EventOverflowSpill      = core.OverflowSpill
+	EventOverflowBlock      = core.OverflowBlock
+)
 
 // UsageMetrics contains usage metrics extracted from API response headers.
 type UsageMetrics struct {
@@ -35,6 +73,7 @@ var (
 const (
 	ErrConfigMissingRequired = errors.ErrConfigMissingRequired
 	ErrConfigInvalidInterval = errors.ErrConfigInvalidInterval
+	ErrConfigInvalidURL      = errors.ErrConfigInvalidURL
 	ErrAuthInvalidKey        = errors.ErrAuthInvalidKey
 )
 
@@ -55,7 +94,7 @@ const (
 	DefaultRetries = 3
 
 	// SDKVersion is the current SDK version.
-	SDKVersion = "1.1.0"
+	SDKVersion = "1.2.0"
 )
 
 // Options configures the FlagKit client.
@@ -72,25 +111,77 @@ type Options struct {
 	// Default: 5 minutes.
 	KeyRotationGracePeriod time.Duration
 
-	// BaseURL is the FlagKit API base URL.
+	// BaseURL is the FlagKit API base URL used to fetch flags.
 	BaseURL string
 
+	// StreamingBaseURL is the base URL of the streaming service.
+	// Default: the streaming URL advertised by the server, or BaseURL.
+	StreamingBaseURL string
+
+	// EventsBaseURL is the base URL analytics events are sent to.
+	// Default: BaseURL.
+	EventsBaseURL string
+
 	// PollingInterval is the interval between flag updates.
 	PollingInterval time.Duration
 
 	// EnablePolling enables background polling for flag updates.
 	EnablePolling bool
 
+	// Streaming enables real-time flag updates over Server-Sent Events.
+	// Streaming is used only when the server advertises support for it;
+	// the SDK falls back to polling if the stream becomes unavailable.
+	Streaming bool
+
 	// CacheEnabled enables local caching of flag values.
 	CacheEnabled bool
 
 	// CacheTTL is the time-to-live for cached values.
 	CacheTTL time.Duration
 
+	// LocalEvaluation enables in-process rule-based evaluation.
+	// When enabled and the server advertises local evaluation support, flag
+	// rule definitions are downloaded and evaluated against the context
+	// passed to each evaluation instead of serving a single cached value.
+	LocalEvaluation bool
+
+	// ServerEvaluation enables per-context evaluation by the server when local
+	// evaluation is unavailable. The context is sent to the evaluate endpoint
+	// with private attributes removed, and the returned flag set is cached
+	// per context for CacheTTL.
+	ServerEvaluation bool
+
+	// ContextCacheSize is the maximum number of contexts whose server-evaluated
+	// flags are cached. Default: 1000.
+	ContextCacheSize int
+
 	// EnableCacheEncryption enables AES-256-GCM encryption for cached data.
 	// The encryption key is derived from the API key using PBKDF2.
 	EnableCacheEncryption bool
 
+	// SnapshotPath is the file where the last known good flag set is saved.
+	// The snapshot is loaded when the client is created, so a client that
+	// restarts while the API is unreachable serves last known values.
+	// Snapshots are encrypted when EnableCacheEncryption is set.
+	SnapshotPath string
+
+	// SnapshotStore is a custom store for flag snapshots.
+	// Takes precedence over SnapshotPath.
+	SnapshotStore SnapshotStore
+
+	// SnapshotMaxAge is the maximum age of a snapshot that is loaded,
+	// based on the server time it was fetched at. Default: 7 days.
+	SnapshotMaxAge time.Duration
+
+	// FlagStore is a shared store of flag states. Unless DaemonMode is set,
+	// the client writes every flag it receives to the store.
+	FlagStore FlagStore
+
+	// DaemonMode makes the client read flags from FlagStore instead of
+	// requesting them from the API. The store is reloaded every
+	// PollingInterval. Analytics events are still sent.
+	DaemonMode bool
+
 	// Offline mode disables network requests.
 	Offline bool
 
@@ -100,6 +191,56 @@ type Options struct {
 	// Retries is the number of retry attempts for failed requests.
 	Retries int
 
+	// Retry is the retry policy for API requests and takes precedence over
+	// Retries. Unset fields other than Jitter use the defaults.
+	Retry *RetryConfig
+
+	// InitRetry is the retry policy for the init request. Its delays are also
+	// used between background initialization retries. Default: Retry.
+	InitRetry *RetryConfig
+
+	// EventsRetry is the retry policy for sending events. Default: Retry.
+	EventsRetry *RetryConfig
+
+	// EventRedelivery is the backoff policy for event batches that failed to
+	// send. Batches are kept and resent until MaxAttempts sends have failed.
+	// Unset fields other than Jitter use the defaults.
+	EventRedelivery *RetryConfig
+
+	// CircuitBreaker configures the circuit breaker for API requests.
+	// Unset fields use the defaults.
+	CircuitBreaker *CircuitBreakerConfig
+
+	// EventsCircuitBreaker configures the circuit breaker for sending events.
+	// Events have their own circuit breaker when EventsBaseURL, EventsRetry or
+	// EventsCircuitBreaker is set. Default: CircuitBreaker.
+	EventsCircuitBreaker *CircuitBreakerConfig
+
+	// BackgroundInit starts initialization in the background when the client
+	// is created. Set by WithStartWaitTimeout.
+	BackgroundInit bool
+
+	// StartWaitTimeout is how long NewClient waits for background
+	// initialization before returning. Zero returns immediately.
+	StartWaitTimeout time.Duration
+
+	// HTTPClient is the HTTP client used for API requests. Streaming uses a
+	// copy of it without a timeout. Takes precedence over Timeout,
+	// Transport, Proxy and TLSConfig.
+	HTTPClient *http.Client
+
+	// Transport is the HTTP transport used for all requests, for example a
+	// tracing wrapper. Takes precedence over Proxy and TLSConfig.
+	Transport http.RoundTripper
+
+	// Proxy is the URL of the proxy used for all requests.
+	// Default: the proxy from the HTTP_PROXY and HTTPS_PROXY environment variables.
+	Proxy string
+
+	// TLSConfig is the TLS configuration used for all requests, for example
+	// to trust a custom CA bundle or present client certificates for mTLS.
+	TLSConfig *tls.Config
+
 	// Bootstrap provides initial flag values (legacy format).
 	Bootstrap map[string]any
 
@@ -124,6 +265,10 @@ type Options struct {
 	// Logger is a custom logger implementation.
 	Logger Logger
 
+	// Metrics receives evaluation, cache, request, circuit breaker and event
+	// measurements. See the metrics/otel and metrics/prometheus modules.
+	Metrics Metrics
+
 	// OnReady is called when the SDK is ready.
 	OnReady func()
 
@@ -145,6 +290,43 @@ type Options struct {
 	// Applications can use this to close other connections or implement backoff.
 	OnConnectionLimitError func()
 
+	// EvaluationEvents enables flag.evaluated analytics events.
+	// Evaluations are deduplicated per context, flag and version, and
+	// summarized into periodic counters rather than sent one per call.
+	EvaluationEvents bool
+
+	// EventQueueSize is the maximum number of events queued in memory.
+	// Default: 1000.
+	EventQueueSize int
+
+	// EventBatchSize is the number of queued events that triggers a flush.
+	// Default: 10.
+	EventBatchSize int
+
+	// EventFlushInterval is the interval between event flushes.
+	// Default: 30 seconds.
+	EventFlushInterval time.Duration
+
+	// EventOverflowPolicy is what happens to new events while the event
+	// queue is full: drop them (the default), drop the oldest queued event,
+	// spill them to the event log (requires PersistEvents) or block Track
+	// for up to EventBlockTimeout. Dropped events are counted by reason in
+	// Diagnostics and reported to Metrics.
+	EventOverflowPolicy EventOverflowPolicy
+
+	// EventBlockTimeout is the longest time Track blocks for room in the
+	// event queue with EventOverflowBlock. Default: 1 second.
+	EventBlockTimeout time.Duration
+
+	// EventHighWaterMark is the number of queued events at which
+	// OnEventQueueHighWater is called. Zero disables the callback.
+	EventHighWaterMark int
+
+	// OnEventQueueHighWater is called with the number of queued events when
+	// the event queue reaches EventHighWaterMark. It is called again only
+	// after the queue has been flushed below the mark.
+	OnEventQueueHighWater func(queued int)
+
 	// PersistEvents enables crash-resilient event persistence.
 	// When enabled, events are written to disk before being queued for sending.
 	PersistEvents bool
@@ -161,6 +343,28 @@ type Options struct {
 	// Default: 1 second.
 	PersistenceFlushInterval time.Duration
 
+	// EncryptEvents encrypts each persisted event record with AES-GCM,
+	// using a key derived from the API key.
+	EncryptEvents bool
+
+	// EventCompression compresses rotated event log segments.
+	// Default: none.
+	EventCompression EventCompression
+
+	// EventSegmentSize is the size in bytes at which the event log is rotated
+	// to a new segment. Default: 1 MiB.
+	EventSegmentSize int64
+
+	// EventDiskQuota is the maximum total size in bytes of the event log.
+	// When it is exceeded, sent events are compacted away and then the
+	// oldest segments are removed. Default: 50 MiB.
+	EventDiskQuota int64
+
+	// EventProcessors run in order on each analytics event before it is
+	// queued, to sample, filter, redact or enrich events. See the events
+	// package for built-in processors.
+	EventProcessors []EventProcessor
+
 	// EvaluationJitter configures timing jitter for flag evaluations.
 	// This provides protection against cache timing attacks.
 	EvaluationJitter EvaluationJitterConfig
@@ -212,6 +416,12 @@ type BootstrapVerificationConfig struct {
 	OnFailure string
 }
 
+// DefaultContextCacheSize is the default number of cached server-evaluated contexts.
+const DefaultContextCacheSize = 1000
+
+// DefaultSnapshotMaxAge is the default maximum age of a loaded flag snapshot.
+const DefaultSnapshotMaxAge = 7 * 24 * time.Hour
+
 // DefaultKeyRotationGracePeriod is the default grace period for key rotation.
 const DefaultKeyRotationGracePeriod = 5 * time.Minute
 
@@ -248,6 +458,8 @@ func DefaultOptions(apiKey string) *Options {
 		Bootstrap:              make(map[string]any),
 		Debug:                  false,
 		KeyRotationGracePeriod: DefaultKeyRotationGracePeriod,
+		ContextCacheSize:       DefaultContextCacheSize,
+		SnapshotMaxAge:         DefaultSnapshotMaxAge,
 		EnableRequestSigning:   true,
 		EvaluationJitter: EvaluationJitterConfig{
 			Enabled: false,
@@ -281,6 +493,28 @@ func (o *Options) Validate() error {
 		o.BaseURL = DefaultBaseURL
 	}
 
+	if err := validateURL("base URL", o.BaseURL); err != nil {
+		return err
+	}
+
+	if o.StreamingBaseURL != "" {
+		if err := validateURL("streaming base URL", o.StreamingBaseURL); err != nil {
+			return err
+		}
+	}
+
+	if o.EventsBaseURL != "" {
+		if err := validateURL("events base URL", o.EventsBaseURL); err != nil {
+			return err
+		}
+	}
+
+	if o.Proxy != "" {
+		if err := validateURL("proxy URL", o.Proxy, "http", "https", "socks5"); err != nil {
+			return err
+		}
+	}
+
 	if o.PollingInterval < time.Second {
 		return NewError(ErrConfigInvalidInterval, "Polling interval must be at least 1 second")
 	}
@@ -293,6 +527,10 @@ func (o *Options) Validate() error {
 		o.Retries = 0
 	}
 
+	if o.StartWaitTimeout < 0 {
+		o.StartWaitTimeout = 0
+	}
+
 	if o.CacheTTL <= 0 {
 		o.CacheTTL = DefaultCacheTTL
 	}
@@ -301,9 +539,39 @@ func (o *Options) Validate() error {
 		o.KeyRotationGracePeriod = DefaultKeyRotationGracePeriod
 	}
 
+	if o.ContextCacheSize <= 0 {
+		o.ContextCacheSize = DefaultContextCacheSize
+	}
+
+	if o.SnapshotMaxAge <= 0 {
+		o.SnapshotMaxAge = DefaultSnapshotMaxAge
+	}
+
+	if o.DaemonMode && o.FlagStore == nil {
+		return NewError(ErrConfigMissingRequired, "flag store is required in daemon mode")
+	}
+
 	return nil
 }
 
+// validateURL checks that a URL is an absolute URL with one of the given
+// schemes, or http or https if none are given.
+func validateURL(name, value string, schemes ...string) error {
+	if len(schemes) == 0 {
+		schemes = []string{"http", "https"}
+	}
+
+	u, err := url.Parse(value)
+	if err == nil && u.Host != "" {
+		for _, scheme := range schemes {
+			if u.Scheme == scheme {
+				return nil
+			}
+		}
+	}
+	return NewError(ErrConfigInvalidURL, "invalid "+name+": "+value)
+}
+
 // OptionFunc is a function that modifies Options.
 type OptionFunc func(*Options)
 
@@ -314,6 +582,50 @@ func WithBaseURL(url string) OptionFunc {
 	}
 }
 
+// WithStreamingBaseURL sets the base URL of the streaming service.
+func WithStreamingBaseURL(url string) OptionFunc {
+	return func(o *Options) {
+		o.StreamingBaseURL = url
+	}
+}
+
+// WithEventsBaseURL sets the base URL analytics events are sent to,
+// for example a relay proxy.
+func WithEventsBaseURL(url string) OptionFunc {
+	return func(o *Options) {
+		o.EventsBaseURL = url
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for API requests.
+// Streaming uses a copy of the client without a timeout.
+func WithHTTPClient(client *http.Client) OptionFunc {
+	return func(o *Options) {
+		o.HTTPClient = client
+	}
+}
+
+// WithTransport sets the HTTP transport used for all requests.
+func WithTransport(transport http.RoundTripper) OptionFunc {
+	return func(o *Options) {
+		o.Transport = transport
+	}
+}
+
+// WithProxy sets the URL of the proxy used for all requests.
+func WithProxy(url string) OptionFunc {
+	return func(o *Options) {
+		o.Proxy = url
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used for all requests.
+func WithTLSConfig(config *tls.Config) OptionFunc {
+	return func(o *Options) {
+		o.TLSConfig = config
+	}
+}
+
 // WithPollingInterval sets the polling interval.
 func WithPollingInterval(d time.Duration) OptionFunc {
 	return func(o *Options) {
@@ -328,6 +640,13 @@ func WithPollingDisabled() OptionFunc {
 	}
 }
 
+// WithStreaming enables real-time flag updates over Server-Sent Events.
+func WithStreaming() OptionFunc {
+	return func(o *Options) {
+		o.Streaming = true
+	}
+}
+
 // WithCacheTTL sets the cache TTL.
 func WithCacheTTL(d time.Duration) OptionFunc {
 	return func(o *Options) {
@@ -342,6 +661,27 @@ func WithCacheDisabled() OptionFunc {
 	}
 }
 
+// WithLocalEvaluation enables in-process rule-based evaluation.
+func WithLocalEvaluation() OptionFunc {
+	return func(o *Options) {
+		o.LocalEvaluation = true
+	}
+}
+
+// WithServerEvaluation enables per-context evaluation by the server.
+func WithServerEvaluation() OptionFunc {
+	return func(o *Options) {
+		o.ServerEvaluation = true
+	}
+}
+
+// WithContextCacheSize sets the maximum number of cached server-evaluated contexts.
+func WithContextCacheSize(size int) OptionFunc {
+	return func(o *Options) {
+		o.ContextCacheSize = size
+	}
+}
+
 // WithOffline enables offline mode.
 func WithOffline() OptionFunc {
 	return func(o *Options) {
@@ -363,6 +703,59 @@ func WithRetries(n int) OptionFunc {
 	}
 }
 
+// WithRetryPolicy sets the retry policy for API requests.
+func WithRetryPolicy(retry *RetryConfig) OptionFunc {
+	return func(o *Options) {
+		o.Retry = retry
+	}
+}
+
+// WithInitRetryPolicy sets the retry policy for the init request.
+func WithInitRetryPolicy(retry *RetryConfig) OptionFunc {
+	return func(o *Options) {
+		o.InitRetry = retry
+	}
+}
+
+// WithEventsRetryPolicy sets the retry policy for sending events.
+func WithEventsRetryPolicy(retry *RetryConfig) OptionFunc {
+	return func(o *Options) {
+		o.EventsRetry = retry
+	}
+}
+
+// WithEventRedeliveryPolicy sets the backoff policy for event batches that
+// failed to send.
+func WithEventRedeliveryPolicy(redelivery *RetryConfig) OptionFunc {
+	return func(o *Options) {
+		o.EventRedelivery = redelivery
+	}
+}
+
+// WithCircuitBreaker configures the circuit breaker for API requests.
+func WithCircuitBreaker(config *CircuitBreakerConfig) OptionFunc {
+	return func(o *Options) {
+		o.CircuitBreaker = config
+	}
+}
+
+// WithEventsCircuitBreaker configures a separate circuit breaker for sending events.
+func WithEventsCircuitBreaker(config *CircuitBreakerConfig) OptionFunc {
+	return func(o *Options) {
+		o.EventsCircuitBreaker = config
+	}
+}
+
+// WithStartWaitTimeout starts initialization in the background when the
+// client is created. NewClient waits up to d for it to complete and returns
+// the client either way; use Ready or InitializationStatus to follow progress.
+func WithStartWaitTimeout(d time.Duration) OptionFunc {
+	return func(o *Options) {
+		o.BackgroundInit = true
+		o.StartWaitTimeout = d
+	}
+}
+
 // WithBootstrap sets bootstrap values.
 func WithBootstrap(values map[string]any) OptionFunc {
 	return func(o *Options) {
@@ -384,6 +777,13 @@ func WithLogger(logger Logger) OptionFunc {
 	}
 }
 
+// WithMetrics sets the metrics hook that receives client measurements.
+func WithMetrics(metrics Metrics) OptionFunc {
+	return func(o *Options) {
+		o.Metrics = metrics
+	}
+}
+
 // WithOnReady sets the ready callback.
 func WithOnReady(fn func()) OptionFunc {
 	return func(o *Options) {
@@ -466,6 +866,96 @@ func WithCacheEncryption() OptionFunc {
 	}
 }
 
+// WithSnapshot saves the last known good flag set to the file at path and
+// loads it when the client is created.
+func WithSnapshot(path string) OptionFunc {
+	return func(o *Options) {
+		o.SnapshotPath = path
+	}
+}
+
+// WithSnapshotStore sets a custom store for flag snapshots.
+func WithSnapshotStore(store SnapshotStore) OptionFunc {
+	return func(o *Options) {
+		o.SnapshotStore = store
+	}
+}
+
+// WithSnapshotMaxAge sets the maximum age of a snapshot that is loaded.
+func WithSnapshotMaxAge(maxAge time.Duration) OptionFunc {
+	return func(o *Options) {
+		o.SnapshotMaxAge = maxAge
+	}
+}
+
+// WithFlagStore writes every flag the client receives to a shared store.
+func WithFlagStore(store FlagStore) OptionFunc {
+	return func(o *Options) {
+		o.FlagStore = store
+	}
+}
+
+// WithDaemonMode reads flags from a shared store instead of the API.
+func WithDaemonMode(store FlagStore) OptionFunc {
+	return func(o *Options) {
+		o.FlagStore = store
+		o.DaemonMode = true
+	}
+}
+
+// WithEvaluationEvents enables flag evaluation analytics events.
+func WithEvaluationEvents() OptionFunc {
+	return func(o *Options) {
+		o.EvaluationEvents = true
+	}
+}
+
+// WithEventQueueSize sets the maximum number of events queued in memory.
+func WithEventQueueSize(size int) OptionFunc {
+	return func(o *Options) {
+		o.EventQueueSize = size
+	}
+}
+
+// WithEventBatchSize sets the number of queued events that triggers a flush.
+func WithEventBatchSize(size int) OptionFunc {
+	return func(o *Options) {
+		o.EventBatchSize = size
+	}
+}
+
+// WithEventFlushInterval sets the interval between event flushes.
+func WithEventFlushInterval(interval time.Duration) OptionFunc {
+	return func(o *Options) {
+		o.EventFlushInterval = interval
+	}
+}
+
+// WithEventOverflowPolicy sets what happens to new events while the event
+// queue is full.
+func WithEventOverflowPolicy(policy EventOverflowPolicy) OptionFunc {
+	return func(o *Options) {
+		o.EventOverflowPolicy = policy
+	}
+}
+
+// WithEventBlockTimeout sets the longest time Track blocks for room in the
+// event queue with EventOverflowBlock.
+func WithEventBlockTimeout(timeout time.Duration) OptionFunc {
+	return func(o *Options) {
+		o.EventBlockTimeout = timeout
+	}
+}
+
+// WithEventHighWaterMark sets a callback called with the number of queued
+// events when the event queue reaches mark.
+func WithEventHighWaterMark(mark int, callback func(queued int)) OptionFunc {
+	return func(o *Options) {
+		o.EventHighWaterMark = mark
+		o.OnEventQueueHighWater = callback
+	}
+}
+
 // WithPersistEvents enables crash-resilient event persistence.
 // When enabled, events are written to disk before being queued for sending.
 func WithPersistEvents(enabled bool) OptionFunc {
@@ -495,6 +985,42 @@ func WithPersistenceFlushInterval(interval time.Duration) OptionFunc {
 	}
 }
 
+// WithEventProcessors adds processors that run in order on each analytics
+// event before it is queued.
+func WithEventProcessors(processors ...EventProcessor) OptionFunc {
+	return func(o *Options) {
+		o.EventProcessors = append(o.EventProcessors, processors...)
+	}
+}
+
+// WithEventEncryption encrypts persisted events with a key derived from the API key.
+func WithEventEncryption() OptionFunc {
+	return func(o *Options) {
+		o.EncryptEvents = true
+	}
+}
+
+// WithEventCompression sets the compression of rotated event log segments.
+func WithEventCompression(compression EventCompression) OptionFunc {
+	return func(o *Options) {
+		o.EventCompression = compression
+	}
+}
+
+// WithEventSegmentSize sets the size in bytes at which the event log is rotated.
+func WithEventSegmentSize(size int64) OptionFunc {
+	return func(o *Options) {
+		o.EventSegmentSize = size
+	}
+}
+
+// WithEventDiskQuota sets the maximum total size in bytes of the event log.
+func WithEventDiskQuota(quota int64) OptionFunc {
+	return func(o *Options) {
+		o.EventDiskQuota = quota
+	}
+}
+
 // WithEvaluationJitter configures evaluation jitter for cache timing attack protection.
 // When enabled, a random delay between minMs and maxMs is added at the start of each flag evaluation.
 func WithEvaluationJitter(enabled bool, minMs, maxMs int) OptionFunc {
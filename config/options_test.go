@@ -1,10 +1,15 @@
 package config
 
 import (
+	"crypto/tls"
+	"net/http"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/teracrafts/flagkit-go/errors"
 )
 
 func TestDefaultOptions(t *testing.T) {
@@ -81,6 +86,81 @@ func TestWithBaseURL(t *testing.T) {
 	assert.Equal(t, "https://custom.api.com", opts.BaseURL)
 }
 
+func TestWithStreamingBaseURL(t *testing.T) {
+	opts := DefaultOptions("sdk_test_key")
+	WithStreamingBaseURL("https://stream.custom.api.com")(opts)
+
+	assert.Equal(t, "https://stream.custom.api.com", opts.StreamingBaseURL)
+	assert.NoError(t, opts.Validate())
+}
+
+func TestWithEventsBaseURL(t *testing.T) {
+	opts := DefaultOptions("sdk_test_key")
+	WithEventsBaseURL("http://relay.internal:8030")(opts)
+
+	assert.Equal(t, "http://relay.internal:8030", opts.EventsBaseURL)
+	assert.NoError(t, opts.Validate())
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	client := &http.Client{Timeout: time.Second}
+	opts := DefaultOptions("sdk_test_key")
+	WithHTTPClient(client)(opts)
+
+	assert.Same(t, client, opts.HTTPClient)
+}
+
+func TestWithTransport(t *testing.T) {
+	transport := &http.Transport{}
+	opts := DefaultOptions("sdk_test_key")
+	WithTransport(transport)(opts)
+
+	assert.Same(t, transport, opts.Transport)
+}
+
+func TestWithProxy(t *testing.T) {
+	opts := DefaultOptions("sdk_test_key")
+	WithProxy("http://proxy.corp.example:3128")(opts)
+
+	assert.Equal(t, "http://proxy.corp.example:3128", opts.Proxy)
+	assert.NoError(t, opts.Validate())
+
+	WithProxy("socks5://proxy.corp.example:1080")(opts)
+	assert.NoError(t, opts.Validate())
+}
+
+func TestWithTLSConfig(t *testing.T) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	opts := DefaultOptions("sdk_test_key")
+	WithTLSConfig(tlsConfig)(opts)
+
+	assert.Same(t, tlsConfig, opts.TLSConfig)
+}
+
+func TestValidateInvalidURLs(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  OptionFunc
+	}{
+		{"base URL without scheme", WithBaseURL("api.flagkit.dev")},
+		{"base URL with unsupported scheme", WithBaseURL("ftp://api.flagkit.dev")},
+		{"streaming base URL without host", WithStreamingBaseURL("https://")},
+		{"malformed events base URL", WithEventsBaseURL("http://[::1")},
+		{"proxy URL with unsupported scheme", WithProxy("ftp://proxy.corp.example")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions("sdk_test_key")
+			tt.opt(opts)
+
+			err := opts.Validate()
+			require.Error(t, err)
+			assert.Equal(t, ErrConfigInvalidURL, err.(*errors.FlagKitError).Code)
+		})
+	}
+}
+
 func TestWithPollingInterval(t *testing.T) {
 	opts := DefaultOptions("sdk_test_key")
 	WithPollingInterval(60 * time.Second)(opts)
@@ -95,6 +175,77 @@ func TestWithPollingDisabled(t *testing.T) {
 	assert.False(t, opts.EnablePolling)
 }
 
+func TestWithStreaming(t *testing.T) {
+	opts := DefaultOptions("sdk_test_key")
+	WithStreaming()(opts)
+
+	assert.True(t, opts.Streaming)
+}
+
+func TestWithEvaluationEvents(t *testing.T) {
+	opts := DefaultOptions("sdk_test_key")
+	assert.False(t, opts.EvaluationEvents)
+
+	WithEvaluationEvents()(opts)
+	assert.True(t, opts.EvaluationEvents)
+}
+
+func TestWithServerEvaluation(t *testing.T) {
+	opts := DefaultOptions("sdk_test_key")
+	assert.False(t, opts.ServerEvaluation)
+	assert.Equal(t, DefaultContextCacheSize, opts.ContextCacheSize)
+
+	WithServerEvaluation()(opts)
+	WithContextCacheSize(50)(opts)
+
+	assert.True(t, opts.ServerEvaluation)
+	assert.Equal(t, 50, opts.ContextCacheSize)
+}
+
+func TestWithSnapshot(t *testing.T) {
+	opts := DefaultOptions("sdk_test_key")
+	assert.Empty(t, opts.SnapshotPath)
+	assert.Equal(t, DefaultSnapshotMaxAge, opts.SnapshotMaxAge)
+
+	WithSnapshot("/var/lib/app/flags.json")(opts)
+	WithSnapshotMaxAge(time.Hour)(opts)
+
+	assert.Equal(t, "/var/lib/app/flags.json", opts.SnapshotPath)
+	assert.Equal(t, time.Hour, opts.SnapshotMaxAge)
+}
+
+func TestWithFlagStore(t *testing.T) {
+	store := &testFlagStore{}
+
+	opts := DefaultOptions("sdk_test_key")
+	WithFlagStore(store)(opts)
+	assert.Equal(t, store, opts.FlagStore)
+	assert.False(t, opts.DaemonMode)
+
+	opts = DefaultOptions("sdk_test_key")
+	WithDaemonMode(store)(opts)
+	assert.Equal(t, store, opts.FlagStore)
+	assert.True(t, opts.DaemonMode)
+}
+
+func TestValidateDaemonModeRequiresStore(t *testing.T) {
+	opts := DefaultOptions("sdk_test_key_12345")
+	opts.DaemonMode = true
+
+	assert.Error(t, opts.Validate())
+}
+
+// testFlagStore is a FlagStore that stores nothing.
+type testFlagStore struct{}
+
+func (s *testFlagStore) Get(string) (*FlagState, error) { return nil, nil }
+func (s *testFlagStore) GetAll() ([]FlagState, error)   { return nil, nil }
+func (s *testFlagStore) Init([]FlagState) error         { return nil }
+func (s *testFlagStore) Upsert(FlagState) error         { return nil }
+func (s *testFlagStore) UpsertAll([]FlagState) error    { return nil }
+func (s *testFlagStore) Delete(string) error            { return nil }
+func (s *testFlagStore) Initialized() (bool, error)     { return false, nil }
+
 func TestWithCacheTTL(t *testing.T) {
 	opts := DefaultOptions("sdk_test_key")
 	WithCacheTTL(10 * time.Minute)(opts)
@@ -109,6 +260,13 @@ func TestWithCacheDisabled(t *testing.T) {
 	assert.False(t, opts.CacheEnabled)
 }
 
+func TestWithLocalEvaluation(t *testing.T) {
+	opts := DefaultOptions("sdk_test_key")
+	WithLocalEvaluation()(opts)
+
+	assert.True(t, opts.LocalEvaluation)
+}
+
 func TestWithOffline(t *testing.T) {
 	opts := DefaultOptions("sdk_test_key")
 	WithOffline()(opts)
@@ -237,3 +395,38 @@ func TestOptionsValidateSecondaryKey(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestWithStartWaitTimeout(t *testing.T) {
+	opts := DefaultOptions("sdk_test_key")
+	assert.False(t, opts.BackgroundInit)
+
+	WithStartWaitTimeout(2 * time.Second)(opts)
+
+	assert.True(t, opts.BackgroundInit)
+	assert.Equal(t, 2*time.Second, opts.StartWaitTimeout)
+
+	WithStartWaitTimeout(-time.Second)(opts)
+	require.NoError(t, opts.Validate())
+	assert.Equal(t, time.Duration(0), opts.StartWaitTimeout)
+}
+
+func TestWithRetryAndCircuitBreakerPolicies(t *testing.T) {
+	opts := DefaultOptions("sdk_test_key")
+	retry := &RetryConfig{MaxAttempts: 5}
+	initRetry := &RetryConfig{MaxAttempts: 10}
+	eventsRetry := &RetryConfig{MaxAttempts: 2}
+	circuit := &CircuitBreakerConfig{FailureThreshold: 3}
+	eventsCircuit := &CircuitBreakerConfig{FailureThreshold: 20}
+
+	WithRetryPolicy(retry)(opts)
+	WithInitRetryPolicy(initRetry)(opts)
+	WithEventsRetryPolicy(eventsRetry)(opts)
+	WithCircuitBreaker(circuit)(opts)
+	WithEventsCircuitBreaker(eventsCircuit)(opts)
+
+	assert.Same(t, retry, opts.Retry)
+	assert.Same(t, initRetry, opts.InitRetry)
+	assert.Same(t, eventsRetry, opts.EventsRetry)
+	assert.Same(t, circuit, opts.CircuitBreaker)
+	assert.Same(t, eventsCircuit, opts.EventsCircuitBreaker)
+}
@@ -29,6 +29,7 @@ const (
 	ErrNetworkTimeout            ErrorCode = "NETWORK_TIMEOUT"
 	ErrNetworkRetryLimit         ErrorCode = "NETWORK_RETRY_LIMIT"
 	ErrNetworkServiceUnavailable ErrorCode = "NETWORK_SERVICE_UNAVAILABLE" // 1308
+	ErrNetworkRetryAfter         ErrorCode = "NETWORK_RETRY_AFTER"
 
 	// Evaluation errors
 	ErrEvalFlagNotFound  ErrorCode = "EVAL_FLAG_NOT_FOUND"
@@ -166,7 +167,7 @@ func (e *FlagKitError) WithDetails(details map[string]any) *FlagKitError {
 func isRecoverable(code ErrorCode) bool {
 	switch code {
 	case ErrNetworkError, ErrNetworkTimeout, ErrNetworkRetryLimit,
-		ErrNetworkServiceUnavailable,
+		ErrNetworkServiceUnavailable, ErrNetworkRetryAfter,
 		ErrCircuitOpen, ErrCacheExpired, ErrEvalStaleValue,
 		ErrEvalCacheMiss, ErrEvalNetworkError, ErrEventSendFailed,
 		ErrStreamingTokenInvalid, ErrStreamingTokenExpired,
@@ -0,0 +1,152 @@
+// Package events provides event processors that sample, filter, redact and
+// enrich analytics events before they are queued.
+//
+// Pass them to a client with flagkit.WithEventProcessors; they run in the
+// order given. Filtering and sampling first avoids redacting and enriching
+// events that are then dropped:
+//
+//	client, err := flagkit.NewClient("sdk_...",
+//		flagkit.WithEventProcessors(
+//			events.Deny("debug.*"),
+//			events.Sample(1, map[string]float64{"page.viewed": 0.1}),
+//			events.RedactPII(),
+//			events.Deployment("checkout", "eu-west-1", "1.4.2"),
+//		),
+//	)
+package events
+
+import (
+	"math/rand"
+	"strings"
+
+	"github.com/teracrafts/flagkit-go/security"
+	"github.com/teracrafts/flagkit-go/types"
+)
+
+// Redacted replaces the values of redacted attributes.
+const Redacted = "[REDACTED]"
+
+// SampleRateKey is the metadata key of the sample rate of sampled events, so
+// that counts can be scaled up on the server.
+const SampleRateKey = "sampleRate"
+
+// Sample keeps each event with the sample rate of its type, between 0 and 1.
+// Event types not in rates use defaultRate. Kept events with a rate below 1
+// carry the rate in their metadata under SampleRateKey.
+func Sample(defaultRate float64, rates map[string]float64) types.EventProcessor {
+	return types.EventProcessorFunc(func(event *types.AnalyticsEvent) bool {
+		rate, ok := rates[event.Type]
+		if !ok {
+			rate = defaultRate
+		}
+		if rate >= 1 {
+			return true
+		}
+		if rate <= 0 || rand.Float64() >= rate {
+			return false
+		}
+		event.Metadata = withMetadata(event.Metadata, map[string]any{SampleRateKey: rate})
+		return true
+	})
+}
+
+// Allow keeps only events whose type matches one of the patterns. A pattern
+// ending in "*" matches event types with the preceding prefix.
+func Allow(patterns ...string) types.EventProcessor {
+	return types.EventProcessorFunc(func(event *types.AnalyticsEvent) bool {
+		return matchesAny(event.Type, patterns)
+	})
+}
+
+// Deny drops events whose type matches one of the patterns. A pattern ending
+// in "*" matches event types with the preceding prefix.
+func Deny(patterns ...string) types.EventProcessor {
+	return types.EventProcessorFunc(func(event *types.AnalyticsEvent) bool {
+		return !matchesAny(event.Type, patterns)
+	})
+}
+
+// RedactPII replaces the values of data and context attributes whose names
+// match the security package's PII patterns, or one of the given attribute
+// names, with Redacted. Nested maps are redacted too.
+func RedactPII(attributes ...string) types.EventProcessor {
+	names := make(map[string]bool, len(attributes))
+	for _, attribute := range attributes {
+		names[strings.ToLower(attribute)] = true
+	}
+	redact := func(name string) bool {
+		return names[strings.ToLower(name)] || security.IsPotentialPIIField(name)
+	}
+
+	return types.EventProcessorFunc(func(event *types.AnalyticsEvent) bool {
+		event.Data = redactMap(event.Data, redact)
+		event.Context = redactMap(event.Context, redact)
+		return true
+	})
+}
+
+// Enrich adds metadata to every event. Existing metadata keys are kept.
+func Enrich(metadata map[string]any) types.EventProcessor {
+	return types.EventProcessorFunc(func(event *types.AnalyticsEvent) bool {
+		event.Metadata = withMetadata(metadata, event.Metadata)
+		return true
+	})
+}
+
+// Deployment adds the service name, region and version of the deployment to
+// the metadata of every event. Empty values are omitted.
+func Deployment(service, region, version string) types.EventProcessor {
+	metadata := make(map[string]any, 3)
+	for key, value := range map[string]string{"service": service, "region": region, "version": version} {
+		if value != "" {
+			metadata[key] = value
+		}
+	}
+	return Enrich(metadata)
+}
+
+// matchesAny reports whether an event type matches one of the patterns.
+func matchesAny(eventType string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(eventType, prefix) {
+				return true
+			}
+		} else if eventType == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// redactMap returns a copy of m with the values of redacted attributes
+// replaced.
+func redactMap(m map[string]any, redact func(name string) bool) map[string]any {
+	if m == nil {
+		return nil
+	}
+	result := make(map[string]any, len(m))
+	for key, value := range m {
+		if redact(key) {
+			result[key] = Redacted
+		} else if nested, ok := value.(map[string]any); ok {
+			result[key] = redactMap(nested, redact)
+		} else {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// withMetadata returns a new map with the entries of base and overrides,
+// preferring overrides.
+func withMetadata(base, overrides map[string]any) map[string]any {
+	result := make(map[string]any, len(base)+len(overrides))
+	for key, value := range base {
+		result[key] = value
+	}
+	for key, value := range overrides {
+		result[key] = value
+	}
+	return result
+}
@@ -0,0 +1,76 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/teracrafts/flagkit-go/types"
+)
+
+func TestSample(t *testing.T) {
+	sample := Sample(1, map[string]float64{"page.viewed": 0, "search": 0.5})
+
+	assert.True(t, sample.Process(&types.AnalyticsEvent{Type: "purchase"}))
+	assert.False(t, sample.Process(&types.AnalyticsEvent{Type: "page.viewed"}))
+
+	kept := 0
+	for i := 0; i < 1000; i++ {
+		event := &types.AnalyticsEvent{Type: "search"}
+		if sample.Process(event) {
+			kept++
+			assert.Equal(t, 0.5, event.Metadata[SampleRateKey])
+		}
+	}
+	assert.InDelta(t, 500, kept, 100)
+}
+
+func TestAllowDeny(t *testing.T) {
+	allow := Allow("purchase", "checkout.*")
+	assert.True(t, allow.Process(&types.AnalyticsEvent{Type: "purchase"}))
+	assert.True(t, allow.Process(&types.AnalyticsEvent{Type: "checkout.completed"}))
+	assert.False(t, allow.Process(&types.AnalyticsEvent{Type: "page.viewed"}))
+
+	deny := Deny("debug.*", "flag.evaluated")
+	assert.False(t, deny.Process(&types.AnalyticsEvent{Type: "debug.trace"}))
+	assert.False(t, deny.Process(&types.AnalyticsEvent{Type: "flag.evaluated"}))
+	assert.True(t, deny.Process(&types.AnalyticsEvent{Type: "purchase"}))
+}
+
+func TestRedactPII(t *testing.T) {
+	data := map[string]any{
+		"amount":  10,
+		"email":   "user@example.com",
+		"address": map[string]any{"city": "Berlin"},
+		"billing": map[string]any{"card_number": "4111", "plan": "pro"},
+		"Coupon":  "SAVE10",
+	}
+	event := &types.AnalyticsEvent{
+		Type:    "purchase",
+		Data:    data,
+		Context: map[string]any{"userId": "user-1", "custom": map[string]any{"phone": "555"}},
+	}
+
+	assert.True(t, RedactPII("coupon").Process(event))
+	assert.Equal(t, map[string]any{
+		"amount":  10,
+		"email":   Redacted,
+		"address": Redacted,
+		"billing": map[string]any{"card_number": Redacted, "plan": "pro"},
+		"Coupon":  Redacted,
+	}, event.Data)
+	assert.Equal(t, map[string]any{"userId": "user-1", "custom": map[string]any{"phone": Redacted}}, event.Context)
+
+	// The caller's data is not modified
+	assert.Equal(t, "user@example.com", data["email"])
+}
+
+func TestEnrich(t *testing.T) {
+	event := &types.AnalyticsEvent{Type: "purchase", Metadata: map[string]any{"region": "us-east-1"}}
+
+	assert.True(t, Deployment("checkout", "eu-west-1", "").Process(event))
+	assert.Equal(t, map[string]any{"service": "checkout", "region": "us-east-1"}, event.Metadata)
+
+	assert.True(t, Enrich(map[string]any{"team": "payments"}).Process(event))
+	assert.Equal(t, map[string]any{"service": "checkout", "region": "us-east-1", "team": "payments"}, event.Metadata)
+}
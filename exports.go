@@ -6,6 +6,7 @@ package flagkit
 import (
 	"time"
 
+	"github.com/teracrafts/flagkit-go/internal/convert"
 	"github.com/teracrafts/flagkit-go/internal/core"
 	"github.com/teracrafts/flagkit-go/internal/http"
 	"github.com/teracrafts/flagkit-go/internal/persistence"
@@ -133,6 +134,7 @@ type EventQueueOptions struct {
 	SDKVersion    string
 	Logger        Logger
 	Config        *EventQueueConfig
+	OnHighWater   func(queued int)
 }
 
 // NewEventQueue creates a new event queue.
@@ -144,13 +146,19 @@ func NewEventQueue(opts *EventQueueOptions) *EventQueue {
 			SDKVersion:    opts.SDKVersion,
 			Logger:        opts.Logger,
 			Config:        opts.Config,
+			OnHighWater:   opts.OnHighWater,
 		}),
 	}
 }
 
 // TrackWithContext adds an event with context to the queue.
 func (eq *EventQueue) TrackWithContext(eventType string, data map[string]any, ctx *EvaluationContext) {
-	eq.EventQueue.TrackWithContext(eventType, data, publicToInternalContext(ctx))
+	eq.EventQueue.TrackWithContext(eventType, data, convert.Context(ctx))
+}
+
+// TrackMetric adds an event with a numeric metric value and context to the queue.
+func (eq *EventQueue) TrackMetric(eventType string, value float64, data map[string]any, ctx *EvaluationContext) {
+	eq.EventQueue.TrackMetric(eventType, value, data, convert.Context(ctx))
 }
 
 // DefaultEventQueueConfig returns the default event queue configuration.
@@ -158,6 +166,42 @@ func DefaultEventQueueConfig() *EventQueueConfig {
 	return core.DefaultEventQueueConfig()
 }
 
+// Impression tracking type aliases for testing
+type ImpressionTracker = core.ImpressionTracker
+type ImpressionTrackerConfig = core.ImpressionTrackerConfig
+type Impression = core.Impression
+
+// Evaluation event types
+const (
+	EventTypeFlagEvaluated = core.EventTypeFlagEvaluated
+	EventTypeFlagSummary   = core.EventTypeFlagSummary
+)
+
+// NewImpressionTracker creates a new impression tracker that emits events to queue.
+func NewImpressionTracker(queue *EventQueue, config *ImpressionTrackerConfig, logger Logger) *ImpressionTracker {
+	return core.NewImpressionTracker(queue.EventQueue, config, logger)
+}
+
+// DefaultImpressionTrackerConfig returns the default impression tracking configuration.
+func DefaultImpressionTrackerConfig() *ImpressionTrackerConfig {
+	return core.DefaultImpressionTrackerConfig()
+}
+
+// Context cache type aliases for testing
+type ContextCache = core.ContextCache
+type ContextCacheConfig = core.ContextCacheConfig
+type EvaluatedFlag = inttypes.EvaluatedFlag
+
+// NewContextCache creates a new per-context evaluation cache.
+func NewContextCache(config *ContextCacheConfig) *ContextCache {
+	return core.NewContextCache(config)
+}
+
+// ContextFingerprint returns a stable fingerprint of a context map.
+func ContextFingerprint(contextMap map[string]any) (string, error) {
+	return core.ContextFingerprint(contextMap)
+}
+
 // Polling type aliases for testing
 type PollingManager = core.PollingManager
 type PollingConfig = core.PollingConfig
@@ -172,6 +216,58 @@ func DefaultPollingConfig() *PollingConfig {
 	return core.DefaultPollingConfig()
 }
 
+// Streaming type aliases for testing
+type StreamingManager = core.StreamingManager
+type StreamingConfig = core.StreamingConfig
+type StreamingState = core.StreamingState
+
+// Streaming state constants
+const (
+	StreamingStateDisconnected = core.StreamingStateDisconnected
+	StreamingStateConnecting   = core.StreamingStateConnecting
+	StreamingStateConnected    = core.StreamingStateConnected
+	StreamingStateReconnecting = core.StreamingStateReconnecting
+	StreamingStateFailed       = core.StreamingStateFailed
+)
+
+// NewStreamingManager creates a new streaming manager.
+func NewStreamingManager(
+	baseURL string,
+	getAPIKey func() string,
+	config *StreamingConfig,
+	onFlagUpdate func(flag *FlagState),
+	onFlagDelete func(key string),
+	onFlagsReset func(flags []*FlagState),
+	onFallbackToPolling func(),
+	logger Logger,
+) *StreamingManager {
+	return core.NewStreamingManager(
+		baseURL,
+		getAPIKey,
+		config,
+		func(flag *inttypes.FlagState) {
+			onFlagUpdate(internalToPublicFlagState(flag))
+		},
+		onFlagDelete,
+		func(flags []*inttypes.FlagState) {
+			publicFlags := make([]*FlagState, len(flags))
+			for i, f := range flags {
+				publicFlags[i] = internalToPublicFlagState(f)
+			}
+			onFlagsReset(publicFlags)
+		},
+		onFallbackToPolling,
+		nil,
+		nil,
+		logger,
+	)
+}
+
+// DefaultStreamingConfig returns the default streaming configuration.
+func DefaultStreamingConfig() *StreamingConfig {
+	return core.DefaultStreamingConfig()
+}
+
 // HTTP client type aliases for advanced usage
 type HTTPClient = http.HTTPClient
 type HTTPClientConfig = http.HTTPClientConfig
@@ -191,10 +287,11 @@ type EventPersistenceConfig = persistence.EventPersistenceConfig
 
 // Event status constants
 const (
-	EventStatusPending = persistence.EventStatusPending
-	EventStatusSending = persistence.EventStatusSending
-	EventStatusSent    = persistence.EventStatusSent
-	EventStatusFailed  = persistence.EventStatusFailed
+	EventStatusPending    = persistence.EventStatusPending
+	EventStatusSending    = persistence.EventStatusSending
+	EventStatusSent       = persistence.EventStatusSent
+	EventStatusFailed     = persistence.EventStatusFailed
+	EventStatusDeadLetter = persistence.EventStatusDeadLetter
 )
 
 // NewEventPersistence creates a new event persistence instance.
@@ -202,6 +299,11 @@ func NewEventPersistence(storagePath string, maxEvents int, flushInterval time.D
 	return persistence.NewEventPersistence(storagePath, maxEvents, flushInterval, logger)
 }
 
+// NewEventPersistenceWithConfig creates a new event persistence instance from a configuration.
+func NewEventPersistenceWithConfig(config *EventPersistenceConfig) (*EventPersistence, error) {
+	return persistence.NewEventPersistenceWithConfig(config)
+}
+
 // NewEventPersisterAdapter creates an adapter that implements the EventPersister interface.
 func NewEventPersisterAdapter(ep *EventPersistence) *EventPersisterAdapter {
 	return persistence.NewEventPersisterAdapter(ep)
@@ -241,20 +343,3 @@ func publicToInternalFlagState(f FlagState) inttypes.FlagState {
 	}
 }
 
-func publicToInternalContext(ctx *EvaluationContext) *inttypes.EvaluationContext {
-	if ctx == nil {
-		return nil
-	}
-	return &inttypes.EvaluationContext{
-		UserID:            ctx.UserID,
-		Email:             ctx.Email,
-		Name:              ctx.Name,
-		Anonymous:         ctx.Anonymous,
-		Country:           ctx.Country,
-		DeviceType:        ctx.DeviceType,
-		OS:                ctx.OS,
-		Browser:           ctx.Browser,
-		Custom:            ctx.Custom,
-		PrivateAttributes: ctx.PrivateAttributes,
-	}
-}
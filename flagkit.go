@@ -21,12 +21,14 @@
 package flagkit
 
 import (
+	"context"
 	"sync"
 
 	"github.com/teracrafts/flagkit-go/client"
 	"github.com/teracrafts/flagkit-go/config"
 	"github.com/teracrafts/flagkit-go/errors"
 	"github.com/teracrafts/flagkit-go/security"
+	"github.com/teracrafts/flagkit-go/store"
 	"github.com/teracrafts/flagkit-go/types"
 )
 
@@ -50,18 +52,73 @@ type (
 	// EvaluationContext contains user and environment information for flag evaluation.
 	EvaluationContext = types.EvaluationContext
 
+	// ContextKind describes an additional entity of a multi-kind context.
+	ContextKind = types.ContextKind
+
 	// EvaluationResult represents the result of evaluating a flag.
 	EvaluationResult = types.EvaluationResult
 
+	// EvaluationReason represents the reason for an evaluation result.
+	EvaluationReason = types.EvaluationReason
+
 	// FlagState represents the state of a feature flag.
 	FlagState = types.FlagState
 
 	// FlagType represents the type of a flag value.
 	FlagType = types.FlagType
 
+	// Variant is the result of assigning a context to an experiment.
+	Variant = types.Variant
+
+	// InitializationStatus describes the progress of client initialization.
+	InitializationStatus = types.InitializationStatus
+
+	// Diagnostics is a snapshot of the state of a client.
+	Diagnostics = types.Diagnostics
+
+	// PollingDiagnostics is the state of polling.
+	PollingDiagnostics = types.PollingDiagnostics
+
+	// SnapshotStore persists the last known good flag set for warm starts.
+	SnapshotStore = types.SnapshotStore
+
+	// FlagStore is a shared store of flag states.
+	FlagStore = types.FlagStore
+
+	// FlagChangeListener is called when a flag changes.
+	FlagChangeListener = client.FlagChangeListener
+
+	// CircuitStateListener is called when a circuit breaker changes state.
+	CircuitStateListener = client.CircuitStateListener
+
 	// Logger defines the interface for logging.
 	Logger = types.Logger
 
+	// Metrics receives measurements from clients.
+	Metrics = types.Metrics
+
+	// CacheResult is the result of a flag cache lookup.
+	CacheResult = types.CacheResult
+
+	// EventStats holds the event delivery gauges of a client.
+	EventStats = types.EventStats
+
+	// EventCompression is the compression of rotated event log segments.
+	EventCompression = config.EventCompression
+
+	// EventOverflowPolicy is what the event queue does with new events while
+	// it is full.
+	EventOverflowPolicy = config.EventOverflowPolicy
+
+	// AnalyticsEvent is an analytics event as seen by event processors.
+	AnalyticsEvent = types.AnalyticsEvent
+
+	// EventProcessor processes analytics events before they are queued.
+	EventProcessor = types.EventProcessor
+
+	// EventProcessorFunc adapts a function to an EventProcessor.
+	EventProcessorFunc = types.EventProcessorFunc
+
 	// NullLogger is a logger that discards all output.
 	NullLogger = types.NullLogger
 
@@ -98,8 +155,26 @@ var (
 	// NewAnonymousContext creates a new anonymous EvaluationContext.
 	NewAnonymousContext = types.NewAnonymousContext
 
+	// NewMultiContext creates a context describing a user together with other kinds.
+	NewMultiContext = types.NewMultiContext
+
+	// NewKindContext creates a new ContextKind of the given kind with the given key.
+	NewKindContext = types.NewKindContext
+
+	// NewOrgContext creates a new organization ContextKind with the given key.
+	NewOrgContext = types.NewOrgContext
+
+	// NewDeviceContext creates a new device ContextKind with the given key.
+	NewDeviceContext = types.NewDeviceContext
+
 	// NewDefaultLogger creates a new default logger.
 	NewDefaultLogger = types.NewDefaultLogger
+
+	// NewMemoryStore creates an in-memory FlagStore.
+	NewMemoryStore = store.NewMemoryStore
+
+	// NewFileStore creates a FlagStore kept in a JSON file.
+	NewFileStore = store.NewFileStore
 )
 
 // Re-export error types and functions
@@ -118,6 +193,7 @@ const (
 	ErrSecuritySignatureInvalid      = errors.ErrSecuritySignatureInvalid
 	ErrNetworkError                  = errors.ErrNetworkError
 	ErrAuthInvalidKey                = errors.ErrAuthInvalidKey
+	ErrConfigInvalidURL              = errors.ErrConfigInvalidURL
 )
 
 // Re-export flag types
@@ -128,6 +204,49 @@ const (
 	FlagTypeJSON    = types.FlagTypeJSON
 )
 
+// Re-export initialization statuses
+const (
+	InitStatusInitializing     = types.InitStatusInitializing
+	InitStatusReadyFromNetwork = types.InitStatusReadyFromNetwork
+	InitStatusReadyFromCache   = types.InitStatusReadyFromCache
+	InitStatusFailed           = types.InitStatusFailed
+)
+
+// Re-export circuit breaker names
+const (
+	CircuitAPI    = client.CircuitAPI
+	CircuitEvents = client.CircuitEvents
+)
+
+// Re-export cache lookup results
+const (
+	CacheHit   = types.CacheHit
+	CacheStale = types.CacheStale
+	CacheMiss  = types.CacheMiss
+)
+
+// Re-export event log compressions
+const (
+	EventCompressionNone = config.EventCompressionNone
+	EventCompressionGzip = config.EventCompressionGzip
+	EventCompressionZstd = config.EventCompressionZstd
+)
+
+// Re-export event queue overflow policies
+const (
+	EventOverflowDropNewest = config.EventOverflowDropNewest
+	EventOverflowDropOldest = config.EventOverflowDropOldest
+	EventOverflowSpill      = config.EventOverflowSpill
+	EventOverflowBlock      = config.EventOverflowBlock
+)
+
+// Re-export context kinds
+const (
+	KindUser         = types.KindUser
+	KindOrganization = types.KindOrganization
+	KindDevice       = types.KindDevice
+)
+
 // Re-export evaluation jitter defaults
 const (
 	DefaultEvaluationJitterMinMs = config.DefaultEvaluationJitterMinMs
@@ -137,16 +256,39 @@ const (
 // Re-export option functions
 var (
 	WithBaseURL               = config.WithBaseURL
+	WithStreamingBaseURL      = config.WithStreamingBaseURL
+	WithEventsBaseURL         = config.WithEventsBaseURL
+	WithHTTPClient            = config.WithHTTPClient
+	WithTransport             = config.WithTransport
+	WithProxy                 = config.WithProxy
+	WithTLSConfig             = config.WithTLSConfig
 	WithPollingInterval       = config.WithPollingInterval
 	WithPollingDisabled       = config.WithPollingDisabled
+	WithStreaming             = config.WithStreaming
 	WithCacheTTL              = config.WithCacheTTL
 	WithCacheDisabled         = config.WithCacheDisabled
 	WithOffline               = config.WithOffline
+	WithLocalEvaluation       = config.WithLocalEvaluation
+	WithServerEvaluation      = config.WithServerEvaluation
+	WithContextCacheSize      = config.WithContextCacheSize
+	WithSnapshot              = config.WithSnapshot
+	WithSnapshotStore         = config.WithSnapshotStore
+	WithSnapshotMaxAge        = config.WithSnapshotMaxAge
+	WithFlagStore             = config.WithFlagStore
+	WithDaemonMode            = config.WithDaemonMode
 	WithTimeout               = config.WithTimeout
 	WithRetries               = config.WithRetries
+	WithRetryPolicy           = config.WithRetryPolicy
+	WithInitRetryPolicy       = config.WithInitRetryPolicy
+	WithEventsRetryPolicy     = config.WithEventsRetryPolicy
+	WithEventRedeliveryPolicy = config.WithEventRedeliveryPolicy
+	WithCircuitBreaker        = config.WithCircuitBreaker
+	WithEventsCircuitBreaker  = config.WithEventsCircuitBreaker
+	WithStartWaitTimeout      = config.WithStartWaitTimeout
 	WithBootstrap             = config.WithBootstrap
 	WithDebug                 = config.WithDebug
 	WithLogger                = config.WithLogger
+	WithMetrics               = config.WithMetrics
 	WithOnReady               = config.WithOnReady
 	WithOnError               = config.WithOnError
 	WithOnUpdate              = config.WithOnUpdate
@@ -154,10 +296,22 @@ var (
 	WithStrictPIIMode         = config.WithStrictPIIMode
 	WithRequestSigning        = config.WithRequestSigning
 	WithCacheEncryption          = config.WithCacheEncryption
+	WithEvaluationEvents         = config.WithEvaluationEvents
+	WithEventQueueSize           = config.WithEventQueueSize
+	WithEventBatchSize           = config.WithEventBatchSize
+	WithEventFlushInterval       = config.WithEventFlushInterval
+	WithEventOverflowPolicy      = config.WithEventOverflowPolicy
+	WithEventBlockTimeout        = config.WithEventBlockTimeout
+	WithEventHighWaterMark       = config.WithEventHighWaterMark
 	WithPersistEvents            = config.WithPersistEvents
 	WithEventStoragePath         = config.WithEventStoragePath
 	WithMaxPersistedEvents       = config.WithMaxPersistedEvents
 	WithPersistenceFlushInterval = config.WithPersistenceFlushInterval
+	WithEventProcessors          = config.WithEventProcessors
+	WithEventEncryption          = config.WithEventEncryption
+	WithEventCompression         = config.WithEventCompression
+	WithEventSegmentSize         = config.WithEventSegmentSize
+	WithEventDiskQuota           = config.WithEventDiskQuota
 	WithEvaluationJitter         = config.WithEvaluationJitter
 	WithBootstrapVerification = config.WithBootstrapVerification
 	WithSignedBootstrap       = config.WithSignedBootstrap
@@ -243,6 +397,21 @@ func Shutdown() error {
 	return err
 }
 
+// ShutdownContext closes the singleton client, sending pending events until
+// the context is done, and resets the instance.
+func ShutdownContext(ctx context.Context) error {
+	instanceMu.Lock()
+	defer instanceMu.Unlock()
+
+	if instance == nil {
+		return nil
+	}
+
+	err := instance.CloseContext(ctx)
+	instance = nil
+	return err
+}
+
 // Convenience methods that operate on the singleton instance.
 // These will panic if the SDK is not initialized.
 
@@ -296,6 +465,16 @@ func Track(eventType string, data ...map[string]any) {
 	_ = mustGetClient().Track(eventType, data...)
 }
 
+// TrackWithContext tracks a custom event attributed to a context using the singleton client.
+func TrackWithContext(eventType string, ctx *EvaluationContext, data ...map[string]any) {
+	_ = mustGetClient().TrackWithContext(eventType, ctx, data...)
+}
+
+// TrackMetric tracks a custom event with a numeric value using the singleton client.
+func TrackMetric(eventType string, value float64, data map[string]any, ctx ...*EvaluationContext) {
+	_ = mustGetClient().TrackMetric(eventType, value, data, ctx...)
+}
+
 // Flush flushes pending events using the singleton client.
 func Flush() {
 	mustGetClient().Flush()
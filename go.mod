@@ -3,12 +3,16 @@ module github.com/teracrafts/flagkit-go
 go 1.21
 
 require (
+	github.com/klauspost/compress v1.17.9
 	github.com/stretchr/testify v1.11.1
 	golang.org/x/crypto v0.23.0
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/kr/pretty v0.3.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/rogpeppe/go-internal v1.10.0 // indirect
+	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,52 @@
+// Package convert converts public SDK types to their internal counterparts.
+//
+// It is shared by the client and the root package so that neither has to
+// export conversions that return internal types.
+package convert
+
+import (
+	inttypes "github.com/teracrafts/flagkit-go/internal/types"
+	"github.com/teracrafts/flagkit-go/types"
+)
+
+// Context converts a context to the internal type used for evaluation and
+// events. Nil kinds are skipped.
+func Context(ctx *types.EvaluationContext) *inttypes.EvaluationContext {
+	if ctx == nil {
+		return nil
+	}
+	return &inttypes.EvaluationContext{
+		UserID:            ctx.UserID,
+		Email:             ctx.Email,
+		Name:              ctx.Name,
+		Anonymous:         ctx.Anonymous,
+		Country:           ctx.Country,
+		DeviceType:        ctx.DeviceType,
+		OS:                ctx.OS,
+		Browser:           ctx.Browser,
+		Custom:            ctx.Custom,
+		PrivateAttributes: ctx.PrivateAttributes,
+		Kinds:             contextKinds(ctx.Kinds),
+	}
+}
+
+// contextKinds converts public context kinds to internal context kinds,
+// skipping nil kinds.
+func contextKinds(kinds map[string]*types.ContextKind) map[string]*inttypes.ContextKind {
+	if len(kinds) == 0 {
+		return nil
+	}
+	result := make(map[string]*inttypes.ContextKind, len(kinds))
+	for name, kind := range kinds {
+		if kind == nil {
+			continue
+		}
+		result[name] = &inttypes.ContextKind{
+			Kind:              kind.Kind,
+			Key:               kind.Key,
+			Attributes:        kind.Attributes,
+			PrivateAttributes: kind.PrivateAttributes,
+		}
+	}
+	return result
+}
@@ -0,0 +1,22 @@
+package convert
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/teracrafts/flagkit-go/types"
+)
+
+func TestContextSkipsNilKinds(t *testing.T) {
+	ctx := types.NewContext("user-1")
+	ctx.Kinds = map[string]*types.ContextKind{"org": nil, "device": {Kind: "device", Key: "ios-1"}}
+
+	internal := Context(ctx)
+
+	assert.Equal(t, "user-1", internal.UserID)
+	require.Len(t, internal.Kinds, 1)
+	assert.Equal(t, "ios-1", internal.Kinds["device"].Key)
+	assert.Nil(t, Context(nil))
+}
@@ -16,18 +16,30 @@ type CacheEntry struct {
 
 // Cache is an in-memory cache for flag states.
 type Cache struct {
-	entries map[string]*CacheEntry
-	mu      sync.RWMutex
-	ttl     time.Duration
-	maxSize int
-	logger  types.Logger
+	entries  map[string]*CacheEntry
+	mu       sync.RWMutex
+	ttl      time.Duration
+	maxSize  int
+	logger   types.Logger
+	onLookup func(result CacheResult)
 }
 
+// CacheResult is the result of a cache lookup.
+type CacheResult string
+
+const (
+	CacheHit   CacheResult = "hit"
+	CacheStale CacheResult = "stale"
+	CacheMiss  CacheResult = "miss"
+)
+
 // CacheConfig contains cache configuration.
 type CacheConfig struct {
 	TTL     time.Duration
 	MaxSize int
 	Logger  types.Logger
+	// OnLookup is called with the result of every Lookup.
+	OnLookup func(result CacheResult)
 }
 
 // DefaultCacheConfig returns default cache configuration.
@@ -44,10 +56,11 @@ func NewCache(config *CacheConfig) *Cache {
 		config = DefaultCacheConfig()
 	}
 	return &Cache{
-		entries: make(map[string]*CacheEntry),
-		ttl:     config.TTL,
-		maxSize: config.MaxSize,
-		logger:  config.Logger,
+		entries:  make(map[string]*CacheEntry),
+		ttl:      config.TTL,
+		maxSize:  config.MaxSize,
+		logger:   config.Logger,
+		onLookup: config.OnLookup,
 	}
 }
 
@@ -75,6 +88,27 @@ func (c *Cache) Get(key string) *types.FlagState {
 	return &entry.Flag
 }
 
+// Lookup retrieves a flag from the cache even if expired, and reports
+// whether it was a hit, stale or a miss.
+func (c *Cache) Lookup(key string) (*types.FlagState, CacheResult) {
+	c.mu.RLock()
+	var flag *types.FlagState
+	result := CacheMiss
+	if entry, ok := c.entries[key]; ok {
+		flag = &entry.Flag
+		result = CacheHit
+		if time.Now().After(entry.ExpiresAt) {
+			result = CacheStale
+		}
+	}
+	c.mu.RUnlock()
+
+	if c.onLookup != nil {
+		c.onLookup(result)
+	}
+	return flag, result
+}
+
 // GetStale retrieves a flag from the cache even if expired.
 func (c *Cache) GetStale(key string) *types.FlagState {
 	c.mu.RLock()
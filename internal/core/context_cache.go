@@ -0,0 +1,145 @@
+package core
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/teracrafts/flagkit-go/internal/types"
+)
+
+// contextCacheEntry holds the evaluated flags of one context.
+type contextCacheEntry struct {
+	fingerprint string
+	flags       map[string]types.EvaluatedFlag
+	expiresAt   time.Time
+}
+
+// ContextCache is a bounded LRU cache of server-evaluated flag sets keyed by
+// context fingerprint.
+type ContextCache struct {
+	entries map[string]*list.Element
+	order   *list.List
+	ttl     time.Duration
+	maxSize int
+	logger  types.Logger
+	mu      sync.Mutex
+}
+
+// ContextCacheConfig contains context cache configuration.
+type ContextCacheConfig struct {
+	TTL     time.Duration
+	MaxSize int
+	Logger  types.Logger
+}
+
+// DefaultContextCacheConfig returns the default context cache configuration.
+func DefaultContextCacheConfig() *ContextCacheConfig {
+	return &ContextCacheConfig{
+		TTL:     5 * time.Minute,
+		MaxSize: 1000,
+	}
+}
+
+// NewContextCache creates a new context cache with the given configuration.
+func NewContextCache(config *ContextCacheConfig) *ContextCache {
+	if config == nil {
+		config = DefaultContextCacheConfig()
+	}
+	maxSize := config.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultContextCacheConfig().MaxSize
+	}
+	return &ContextCache{
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		ttl:     config.TTL,
+		maxSize: maxSize,
+		logger:  config.Logger,
+	}
+}
+
+// ContextFingerprint returns a stable fingerprint of a context map. Returns an
+// error if the context cannot be serialized, such as when it holds NaN.
+func ContextFingerprint(contextMap map[string]any) (string, error) {
+	// json.Marshal sorts map keys, so equal contexts produce equal output
+	data, err := json.Marshal(contextMap)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Get returns the evaluated flags for a context fingerprint.
+// Returns false if not found or expired.
+func (c *ContextCache) Get(fingerprint string) (map[string]types.EvaluatedFlag, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[fingerprint]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*contextCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.entries, fingerprint)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.flags, true
+}
+
+// Set stores the evaluated flags for a context fingerprint.
+func (c *ContextCache) Set(fingerprint string, flags []types.EvaluatedFlag) {
+	byKey := make(map[string]types.EvaluatedFlag, len(flags))
+	for _, f := range flags {
+		byKey[f.Key] = f
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &contextCacheEntry{
+		fingerprint: fingerprint,
+		flags:       byKey,
+		expiresAt:   time.Now().Add(c.ttl),
+	}
+
+	if elem, ok := c.entries[fingerprint]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[fingerprint] = c.order.PushFront(entry)
+	if c.order.Len() > c.maxSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*contextCacheEntry).fingerprint)
+		if c.logger != nil {
+			c.logger.Debug("Context cache evicted oldest")
+		}
+	}
+}
+
+// Clear removes all entries from the cache.
+func (c *ContextCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// Size returns the number of cached contexts.
+func (c *ContextCache) Size() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
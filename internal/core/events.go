@@ -1,6 +1,8 @@
 package core
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -11,42 +13,93 @@ import (
 
 // Event represents an analytics event.
 type Event struct {
-	ID            string                 `json:"id,omitempty"`
-	Type          string                 `json:"type"`
-	Timestamp     string                 `json:"timestamp"`
-	SessionID     string                 `json:"sessionId"`
-	EnvironmentID string                 `json:"environmentId"`
-	SDKVersion    string                 `json:"sdkVersion"`
+	ID            string         `json:"id,omitempty"`
+	Type          string         `json:"type"`
+	Timestamp     string         `json:"timestamp"`
+	SessionID     string         `json:"sessionId"`
+	EnvironmentID string         `json:"environmentId"`
+	SDKVersion    string         `json:"sdkVersion"`
 	Data          map[string]any `json:"data,omitempty"`
 	Context       map[string]any `json:"context,omitempty"`
+	// MetricValue is a numeric value such as revenue or latency.
+	MetricValue *float64 `json:"metricValue,omitempty"`
+	// Metadata is added by event processors, such as deployment metadata.
+	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
+// EventProcessor processes an event before it is queued. Returning false
+// drops the event.
+type EventProcessor func(event *Event) bool
+
 // EventPersister is the interface for event persistence.
 type EventPersister interface {
 	Persist(event PersistedEvent) error
 	MarkSending(eventIDs []string) error
 	MarkSent(eventIDs []string) error
 	MarkFailed(eventIDs []string) error
+	MarkDeadLetter(eventIDs []string) error
 	Recover() ([]PersistedEvent, error)
 	Flush() error
+	// Spill stores an event that does not fit in the queue.
+	Spill(event PersistedEvent) error
+	// Unspill reads back up to max spilled events, oldest first. Fewer
+	// events are returned only when no more spilled events can be read.
+	Unspill(max int) ([]PersistedEvent, error)
 }
 
 // PersistedEvent represents an event stored on disk.
 type PersistedEvent struct {
-	ID        string                 `json:"id"`
-	Type      string                 `json:"type"`
-	Data      map[string]any `json:"data,omitempty"`
-	Context   map[string]any `json:"context,omitempty"`
-	Timestamp int64                  `json:"timestamp"`
-	Status    string                 `json:"status"`
-	SentAt    int64                  `json:"sentAt,omitempty"`
+	ID          string         `json:"id"`
+	Type        string         `json:"type"`
+	Data        map[string]any `json:"data,omitempty"`
+	Context     map[string]any `json:"context,omitempty"`
+	Timestamp   int64          `json:"timestamp"`
+	Status      string         `json:"status"`
+	SentAt      int64          `json:"sentAt,omitempty"`
+	Attempts    int            `json:"attempts,omitempty"`
+	MetricValue *float64       `json:"metricValue,omitempty"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
 }
 
+// OverflowPolicy is what the event queue does with new events while it is
+// full.
+type OverflowPolicy string
+
+const (
+	// OverflowDropNewest drops new events while the queue is full.
+	OverflowDropNewest OverflowPolicy = "drop_newest"
+	// OverflowDropOldest drops the oldest queued event to make room.
+	OverflowDropOldest OverflowPolicy = "drop_oldest"
+	// OverflowSpill writes new events only to the persister and queues them
+	// again once there is room. New events are dropped without a persister.
+	OverflowSpill OverflowPolicy = "spill"
+	// OverflowBlock makes Track wait for queued events to be flushed, and
+	// drops the new event if there is no room within the block timeout or
+	// the queue is not running.
+	OverflowBlock OverflowPolicy = "block"
+)
+
+// DefaultBlockTimeout is the default time Track waits for room with
+// OverflowBlock.
+const DefaultBlockTimeout = time.Second
+
 // EventQueueConfig contains event queue configuration.
 type EventQueueConfig struct {
 	MaxSize       int
 	FlushInterval time.Duration
 	BatchSize     int
+	// Overflow is what happens to new events while MaxSize events are
+	// queued. Default: OverflowDropNewest.
+	Overflow OverflowPolicy
+	// BlockTimeout is the longest time Track waits for room with
+	// OverflowBlock. Default: DefaultBlockTimeout.
+	BlockTimeout time.Duration
+	// HighWaterMark is the number of queued events at which
+	// EventQueueOptions.OnHighWater is called. Zero disables the callback.
+	HighWaterMark int
+	// Redelivery is the backoff policy for batches that failed to send.
+	// Batches are dead-lettered after Redelivery.MaxAttempts attempts.
+	Redelivery *http.RetryConfig
 }
 
 // DefaultEventQueueConfig returns the default event queue configuration.
@@ -55,9 +108,35 @@ func DefaultEventQueueConfig() *EventQueueConfig {
 		MaxSize:       1000,
 		FlushInterval: 30 * time.Second,
 		BatchSize:     10,
+		Overflow:      OverflowDropNewest,
+		BlockTimeout:  DefaultBlockTimeout,
+		Redelivery:    DefaultRedeliveryConfig(),
 	}
 }
 
+// DefaultRedeliveryConfig returns the default backoff policy for event
+// batches that failed to send.
+func DefaultRedeliveryConfig() *http.RetryConfig {
+	return &http.RetryConfig{
+		MaxAttempts:       10,
+		BaseDelay:         5 * time.Second,
+		MaxDelay:          5 * time.Minute,
+		BackoffMultiplier: 2.0,
+		Jitter:            time.Second,
+	}
+}
+
+// maxRedeliveryCheckInterval is the longest interval between checks for
+// batches due for redelivery.
+const maxRedeliveryCheckInterval = time.Second
+
+// eventBatch is a batch of events and its send attempts.
+type eventBatch struct {
+	events      []Event
+	attempts    int
+	nextAttempt time.Time
+}
+
 // EventQueue manages analytics events with batching.
 type EventQueue struct {
 	config        *EventQueueConfig
@@ -75,8 +154,32 @@ type EventQueue struct {
 	// Persistence support
 	persister      EventPersister
 	persistEnabled bool
+
+	// Batches awaiting redelivery
+	redelivery *http.RetryConfig
+	retries    []*eventBatch
+
+	processors []EventProcessor
+
+	// Overflow handling
+	spilled        int
+	room           chan struct{}
+	aboveHighWater bool
+	onHighWater    func(queued int)
+
+	onDrop       func(reason string, count int)
+	pendingDrops map[string]int
+	drops        map[string]int64
+	dropsMu      sync.Mutex
 }
 
+// Reasons passed to EventQueueOptions.OnDrop.
+const (
+	DropQueueFull  = "queue_full"
+	DropSendFailed = "send_failed"
+	DropDiskQuota  = "disk_quota"
+)
+
 // EventQueueOptions contains options for creating an event queue.
 type EventQueueOptions struct {
 	HTTPClient     *http.HTTPClient
@@ -87,6 +190,14 @@ type EventQueueOptions struct {
 	Config         *EventQueueConfig
 	Persister      EventPersister
 	PersistEnabled bool
+	// OnDrop is called when events are dropped without being sent.
+	OnDrop func(reason string, count int)
+	// Processors run in order on each event before it is queued.
+	Processors []EventProcessor
+	// OnHighWater is called with the number of queued events when it
+	// reaches Config.HighWaterMark. It is called again only after the queue
+	// has been flushed below the mark.
+	OnHighWater func(queued int)
 }
 
 // NewEventQueue creates a new event queue.
@@ -95,6 +206,10 @@ func NewEventQueue(opts *EventQueueOptions) *EventQueue {
 	if config == nil {
 		config = DefaultEventQueueConfig()
 	}
+	redelivery := config.Redelivery
+	if redelivery == nil || redelivery.MaxAttempts <= 0 {
+		redelivery = DefaultRedeliveryConfig()
+	}
 
 	eq := &EventQueue{
 		config:         config,
@@ -108,6 +223,12 @@ func NewEventQueue(opts *EventQueueOptions) *EventQueue {
 		flushCh:        make(chan struct{}, 1),
 		persister:      opts.Persister,
 		persistEnabled: opts.PersistEnabled,
+		redelivery:     redelivery,
+		processors:     opts.Processors,
+		room:           make(chan struct{}),
+		onHighWater:    opts.OnHighWater,
+		onDrop:         opts.OnDrop,
+		drops:          make(map[string]int64),
 	}
 
 	return eq
@@ -129,17 +250,23 @@ func (eq *EventQueue) Start() {
 
 // Stop stops the event queue and flushes remaining events.
 func (eq *EventQueue) Stop() {
+	_ = eq.StopContext(context.Background())
+}
+
+// StopContext stops the event queue and flushes remaining events, giving up
+// when the context is done. Returns the error of the final flush.
+func (eq *EventQueue) StopContext(ctx context.Context) error {
 	eq.mu.Lock()
 	if !eq.running {
 		eq.mu.Unlock()
-		return
+		return nil
 	}
 	eq.running = false
 	close(eq.stopCh)
 	eq.mu.Unlock()
 
 	// Final flush
-	eq.Flush()
+	return eq.FlushContext(ctx)
 }
 
 // SetEnvironmentID sets the environment ID.
@@ -151,147 +278,404 @@ func (eq *EventQueue) SetEnvironmentID(id string) {
 
 // Track adds an event to the queue.
 func (eq *EventQueue) Track(eventType string, data map[string]any) {
-	eq.mu.Lock()
-	defer eq.mu.Unlock()
+	eq.track(eventType, data, nil, nil)
+}
 
-	if len(eq.events) >= eq.config.MaxSize {
-		if eq.logger != nil {
-			eq.logger.Warn("Event queue full, dropping event", "type", eventType)
-		}
-		return
-	}
+// generateEventID generates a unique event ID.
+func (eq *EventQueue) generateEventID() string {
+	return fmt.Sprintf("evt_%d_%s", time.Now().UnixNano(), eq.sessionID[:8])
+}
 
-	eventID := eq.generateEventID()
-	now := time.Now().UTC()
+// TrackWithContext adds an event with context to the queue.
+func (eq *EventQueue) TrackWithContext(eventType string, data map[string]any, ctx *types.EvaluationContext) {
+	eq.track(eventType, data, ctx, nil)
+}
+
+// TrackMetric adds an event with a numeric metric value and context to the queue.
+func (eq *EventQueue) TrackMetric(eventType string, value float64, data map[string]any, ctx *types.EvaluationContext) {
+	eq.track(eventType, data, ctx, &value)
+}
+
+// track processes an event and adds it to the queue. Private attributes are
+// stripped from the context.
+func (eq *EventQueue) track(eventType string, data map[string]any, ctx *types.EvaluationContext, metricValue *float64) {
+	var contextMap map[string]any
+	if ctx != nil {
+		contextMap = ctx.StripPrivateAttributes().ToMap()
+	}
 
 	event := Event{
-		ID:            eventID,
-		Type:          eventType,
-		Timestamp:     now.Format(time.RFC3339),
-		SessionID:     eq.sessionID,
-		EnvironmentID: eq.environmentID,
-		SDKVersion:    eq.sdkVersion,
-		Data:          data,
+		Type:        eventType,
+		Data:        data,
+		Context:     contextMap,
+		MetricValue: metricValue,
 	}
 
-	// Persist event before adding to queue (crash-safe)
-	if eq.persistEnabled && eq.persister != nil {
-		persistedEvent := PersistedEvent{
-			ID:        eventID,
-			Type:      eventType,
-			Data:      data,
-			Timestamp: now.UnixMilli(),
-			Status:    "pending",
-		}
-		if err := eq.persister.Persist(persistedEvent); err != nil {
+	// Processors run before taking the lock, so they may track events
+	for _, process := range eq.processors {
+		if !process(&event) {
 			if eq.logger != nil {
-				eq.logger.Warn("Failed to persist event", "error", err.Error(), "eventId", eventID)
+				eq.logger.Debug("Event dropped by processor", "type", eventType)
 			}
-			// Continue anyway - event will still be in memory
+			return
 		}
 	}
 
+	if queued, crossed := eq.enqueue(event); crossed && eq.onHighWater != nil {
+		eq.onHighWater(queued)
+	}
+}
+
+// enqueue adds a processed event to the queue, applying the overflow policy
+// while the queue is full. Returns the number of queued events and whether it
+// reached the high-water mark.
+func (eq *EventQueue) enqueue(event Event) (int, bool) {
+	defer eq.reportDrops()
+	eq.mu.Lock()
+	defer eq.mu.Unlock()
+
+	now := time.Now().UTC()
+	event.ID = eq.generateEventID()
+	event.Timestamp = now.Format(time.RFC3339)
+	event.SessionID = eq.sessionID
+	event.EnvironmentID = eq.environmentID
+	event.SDKVersion = eq.sdkVersion
+
+	if len(eq.events) >= eq.config.MaxSize && !eq.overflowLocked(&event, now) {
+		return 0, false
+	}
+
+	// Persist event before adding to queue (crash-safe). The event is still
+	// queued in memory if this fails.
+	_ = eq.persistLocked(event, now)
+
 	eq.events = append(eq.events, event)
 
 	if eq.logger != nil {
-		eq.logger.Debug("Event tracked", "type", eventType, "queue_size", len(eq.events))
+		eq.logger.Debug("Event tracked", "type", event.Type, "queue_size", len(eq.events))
 	}
 
 	// Trigger flush if batch size reached
 	if len(eq.events) >= eq.config.BatchSize {
-		select {
-		case eq.flushCh <- struct{}{}:
-		default:
-		}
+		eq.requestFlush()
 	}
+	return len(eq.events), eq.crossedHighWaterLocked()
 }
 
-// generateEventID generates a unique event ID.
-func (eq *EventQueue) generateEventID() string {
-	return fmt.Sprintf("evt_%d_%s", time.Now().UnixNano(), eq.sessionID[:8])
-}
+// overflowLocked applies the overflow policy to an event tracked while the
+// queue is full. Returns whether the event can be queued (must be called with
+// lock held; OverflowBlock releases it while waiting).
+func (eq *EventQueue) overflowLocked(event *Event, now time.Time) bool {
+	switch eq.config.Overflow {
+	case OverflowDropOldest:
+		oldest := eq.events[0]
+		eq.events = append(eq.events[:0], eq.events[1:]...)
+		if eq.logger != nil {
+			eq.logger.Warn("Event queue full, dropping oldest event", "type", oldest.Type)
+		}
+		eq.droppedLocked(DropQueueFull, 1)
+		if eq.persistEnabled && eq.persister != nil {
+			if err := eq.persister.MarkDeadLetter([]string{oldest.ID}); err != nil {
+				if eq.logger != nil {
+					eq.logger.Warn("Failed to mark events as dead-lettered", "error", err.Error())
+				}
+			}
+		}
+		return true
+	case OverflowSpill:
+		if eq.persistEnabled && eq.persister != nil {
+			err := eq.persister.Spill(eq.persistedEvent(*event, now))
+			if err == nil {
+				eq.spilled++
+				if eq.logger != nil {
+					eq.logger.Debug("Event queue full, spilled event to disk", "type", event.Type, "spilled", eq.spilled)
+				}
+				return false
+			}
+			if eq.logger != nil {
+				eq.logger.Warn("Failed to spill event", "error", err.Error(), "eventId", event.ID)
+			}
+		}
+	case OverflowBlock:
+		if eq.waitForRoomLocked() {
+			return true
+		}
+	}
 
-// TrackWithContext adds an event with context to the queue.
-func (eq *EventQueue) TrackWithContext(eventType string, data map[string]any, ctx *types.EvaluationContext) {
-	eq.mu.Lock()
-	defer eq.mu.Unlock()
+	if eq.logger != nil {
+		eq.logger.Warn("Event queue full, dropping event", "type", event.Type)
+	}
+	eq.droppedLocked(DropQueueFull, 1)
+	return false
+}
 
-	if len(eq.events) >= eq.config.MaxSize {
-		return
+// waitForRoomLocked waits up to the block timeout for queued events to be
+// flushed. Returns whether there is room in the queue, without waiting when
+// the queue is not running (must be called with lock held, which is released
+// while waiting).
+func (eq *EventQueue) waitForRoomLocked() bool {
+	if !eq.running {
+		return false
+	}
+	timeout := eq.config.BlockTimeout
+	if timeout <= 0 {
+		timeout = DefaultBlockTimeout
 	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
 
-	var contextMap map[string]any
-	if ctx != nil {
-		contextMap = ctx.StripPrivateAttributes().ToMap()
+	for len(eq.events) >= eq.config.MaxSize {
+		if !eq.running {
+			return false
+		}
+		room := eq.room
+		eq.requestFlush()
+		eq.mu.Unlock()
+		select {
+		case <-room:
+			eq.mu.Lock()
+		case <-timer.C:
+			eq.mu.Lock()
+			return len(eq.events) < eq.config.MaxSize
+		}
 	}
+	return true
+}
 
-	eventID := eq.generateEventID()
-	now := time.Now().UTC()
+// persistLocked persists a pending event (must be called with lock held).
+func (eq *EventQueue) persistLocked(event Event, now time.Time) error {
+	if !eq.persistEnabled || eq.persister == nil {
+		return nil
+	}
+	err := eq.persister.Persist(eq.persistedEvent(event, now))
+	if err != nil && eq.logger != nil {
+		eq.logger.Warn("Failed to persist event", "error", err.Error(), "eventId", event.ID)
+	}
+	return err
+}
 
-	event := Event{
-		ID:            eventID,
-		Type:          eventType,
-		Timestamp:     now.Format(time.RFC3339),
-		SessionID:     eq.sessionID,
-		EnvironmentID: eq.environmentID,
-		SDKVersion:    eq.sdkVersion,
-		Data:          data,
-		Context:       contextMap,
+// persistedEvent returns the pending persisted event of a tracked event.
+func (eq *EventQueue) persistedEvent(event Event, now time.Time) PersistedEvent {
+	return PersistedEvent{
+		ID:          event.ID,
+		Type:        event.Type,
+		Data:        event.Data,
+		Context:     event.Context,
+		Timestamp:   now.UnixMilli(),
+		Status:      "pending",
+		MetricValue: event.MetricValue,
+		Metadata:    event.Metadata,
 	}
+}
 
-	// Persist event before adding to queue (crash-safe)
-	if eq.persistEnabled && eq.persister != nil {
-		persistedEvent := PersistedEvent{
-			ID:        eventID,
-			Type:      eventType,
-			Data:      data,
-			Context:   contextMap,
-			Timestamp: now.UnixMilli(),
-			Status:    "pending",
-		}
-		if err := eq.persister.Persist(persistedEvent); err != nil {
-			if eq.logger != nil {
-				eq.logger.Warn("Failed to persist event", "error", err.Error(), "eventId", eventID)
-			}
-		}
+// requestFlush asks the flush loop to flush the queue.
+func (eq *EventQueue) requestFlush() {
+	select {
+	case eq.flushCh <- struct{}{}:
+	default:
 	}
+}
 
-	eq.events = append(eq.events, event)
+// crossedHighWaterLocked reports whether the queue has just reached the
+// high-water mark (must be called with lock held).
+func (eq *EventQueue) crossedHighWaterLocked() bool {
+	mark := eq.config.HighWaterMark
+	if mark <= 0 || eq.aboveHighWater || len(eq.events) < mark {
+		return false
+	}
+	eq.aboveHighWater = true
+	return true
+}
 
-	if len(eq.events) >= eq.config.BatchSize {
-		select {
-		case eq.flushCh <- struct{}{}:
-		default:
-		}
+// drainedLocked wakes tracks waiting for room after queued events were
+// removed, and re-arms the high-water mark (must be called with lock held).
+func (eq *EventQueue) drainedLocked() {
+	close(eq.room)
+	eq.room = make(chan struct{})
+	if len(eq.events) < eq.config.HighWaterMark {
+		eq.aboveHighWater = false
 	}
 }
 
 // Flush sends all queued events to the server.
 func (eq *EventQueue) Flush() {
+	_ = eq.FlushContext(context.Background())
+}
+
+// FlushContext sends all queued events and all batches awaiting redelivery
+// to the server, giving up when the context is done. Returns the error of
+// the first failed send request.
+func (eq *EventQueue) FlushContext(ctx context.Context) error {
+	var err error
+	for {
+		// Spilled events are queued again and flushed until none are left
+		err = eq.flushQueued(ctx)
+		if err != nil || ctx.Err() != nil || eq.unspill() == 0 {
+			break
+		}
+	}
+	if retryErr := eq.redeliver(ctx, true); err == nil {
+		err = retryErr
+	}
+	return err
+}
+
+// flushQueued sends the queued events to the server.
+func (eq *EventQueue) flushQueued(ctx context.Context) error {
 	eq.mu.Lock()
 	if len(eq.events) == 0 {
 		eq.mu.Unlock()
-		return
+		return nil
 	}
 
 	// Copy events and clear queue
 	events := make([]Event, len(eq.events))
 	copy(events, eq.events)
 	eq.events = eq.events[:0]
+	eq.drainedLocked()
 	eq.mu.Unlock()
 
 	if eq.logger != nil {
 		eq.logger.Debug("Flushing events", "count", len(events))
 	}
 
-	eq.sendEvents(events)
+	return eq.deliver(ctx, &eventBatch{events: events})
+}
+
+// redeliver sends the batches that are due for redelivery, or all batches if
+// all is set. Batches are postponed while the circuit breaker is open.
+func (eq *EventQueue) redeliver(ctx context.Context, all bool) error {
+	now := time.Now()
+
+	eq.mu.Lock()
+	var due, waiting []*eventBatch
+	for _, batch := range eq.retries {
+		if all || !now.Before(batch.nextAttempt) {
+			due = append(due, batch)
+		} else {
+			waiting = append(waiting, batch)
+		}
+	}
+	if len(due) == 0 {
+		eq.mu.Unlock()
+		return nil
+	}
+
+	if eq.httpClient != nil {
+		if blockedFor := eq.blockedFor(); blockedFor > 0 {
+			// Wait for the circuit breaker or the Retry-After deadline
+			// instead of spending attempts
+			for _, batch := range due {
+				if next := now.Add(blockedFor); batch.nextAttempt.Before(next) {
+					batch.nextAttempt = next
+				}
+			}
+			eq.mu.Unlock()
+			return nil
+		}
+	}
+	eq.retries = waiting
+	eq.mu.Unlock()
+
+	var err error
+	for _, batch := range due {
+		if eq.logger != nil {
+			eq.logger.Debug("Redelivering events", "count", len(batch.events), "attempt", batch.attempts+1)
+		}
+		if sendErr := eq.deliver(ctx, batch); err == nil {
+			err = sendErr
+		}
+	}
+	return err
+}
+
+// unspill queues spilled events again while there is room, reading them back
+// from the persister in the order they were spilled. Returns the number of
+// events queued.
+func (eq *EventQueue) unspill() int {
+	eq.mu.Lock()
+	room := eq.config.MaxSize - len(eq.events)
+	if eq.spilled == 0 || room <= 0 || eq.persister == nil {
+		eq.mu.Unlock()
+		return 0
+	}
+
+	// The lock is held so that events are not spilled while reading
+	want := min(eq.spilled, room)
+	unspilled, err := eq.persister.Unspill(want)
+	if err != nil {
+		eq.mu.Unlock()
+		if eq.logger != nil {
+			eq.logger.Warn("Failed to read spilled events", "error", err.Error())
+		}
+		return 0
+	}
+
+	for _, pe := range unspilled {
+		eq.events = append(eq.events, eq.recoveredEvent(pe))
+	}
+	eq.spilled -= len(unspilled)
+
+	// Spilled events that could not be read back are lost
+	if len(unspilled) < want && eq.spilled > 0 {
+		lost := eq.spilled
+		eq.spilled = 0
+		if eq.logger != nil {
+			eq.logger.Warn("Spilled events could not be read back", "count", lost)
+		}
+		eq.droppedLocked(DropQueueFull, lost)
+	}
+
+	if eq.logger != nil && len(unspilled) > 0 {
+		eq.logger.Debug("Queued spilled events", "count", len(unspilled), "spilled", eq.spilled)
+	}
+	if len(eq.events) >= eq.config.BatchSize {
+		eq.requestFlush()
+	}
+	size, crossed := len(eq.events), eq.crossedHighWaterLocked()
+	eq.mu.Unlock()
+
+	eq.reportDrops()
+	if crossed && eq.onHighWater != nil {
+		eq.onHighWater(size)
+	}
+	return len(unspilled)
 }
 
-// QueueSize returns the number of queued events.
+// QueueSize returns the number of events that are queued, spilled to disk or
+// awaiting redelivery.
 func (eq *EventQueue) QueueSize() int {
 	eq.mu.Lock()
 	defer eq.mu.Unlock()
-	return len(eq.events)
+	return len(eq.events) + eq.spilled + eq.retryingLocked()
+}
+
+// DroppedEvents returns the number of events dropped without being sent
+// since the queue was created, by reason.
+func (eq *EventQueue) DroppedEvents() map[string]int64 {
+	eq.dropsMu.Lock()
+	defer eq.dropsMu.Unlock()
+	drops := make(map[string]int64, len(eq.drops))
+	for reason, count := range eq.drops {
+		drops[reason] = count
+	}
+	return drops
+}
+
+// RecordDropped counts events dropped without being sent outside the queue,
+// such as persisted events removed to stay within the disk quota.
+func (eq *EventQueue) RecordDropped(reason string, count int) {
+	eq.dropped(reason, count)
+}
+
+// retryingLocked returns the number of events awaiting redelivery (must be
+// called with lock held).
+func (eq *EventQueue) retryingLocked() int {
+	count := 0
+	for _, batch := range eq.retries {
+		count += len(batch.events)
+	}
+	return count
 }
 
 // run is the background flush loop.
@@ -299,24 +683,135 @@ func (eq *EventQueue) run() {
 	ticker := time.NewTicker(eq.config.FlushInterval)
 	defer ticker.Stop()
 
+	retryInterval := min(eq.redelivery.BaseDelay, maxRedeliveryCheckInterval)
+	if retryInterval <= 0 {
+		retryInterval = maxRedeliveryCheckInterval
+	}
+	retryTicker := time.NewTicker(retryInterval)
+	defer retryTicker.Stop()
+
 	for {
 		select {
 		case <-eq.stopCh:
 			return
 		case <-ticker.C:
-			eq.Flush()
+			_ = eq.flushQueued(context.Background())
+			eq.unspill()
 		case <-eq.flushCh:
-			eq.Flush()
+			_ = eq.flushQueued(context.Background())
+			eq.unspill()
+		case <-retryTicker.C:
+			_ = eq.redeliver(context.Background(), false)
 		}
 	}
 }
 
-// sendEvents sends events to the server.
-func (eq *EventQueue) sendEvents(events []Event) {
+// eventsBatchPath is the endpoint events are sent to.
+const eventsBatchPath = "/sdk/events/batch"
+
+// blockedFor returns how long event requests are rejected without being sent,
+// because the circuit breaker is open or the server asked to retry later.
+func (eq *EventQueue) blockedFor() time.Duration {
 	if eq.httpClient == nil {
+		return 0
+	}
+	return max(eq.httpClient.CircuitOpenFor(), eq.httpClient.RetryAfterFor(eventsBatchPath))
+}
+
+// deliver sends a batch of events, scheduling it for redelivery on failure.
+func (eq *EventQueue) deliver(ctx context.Context, batch *eventBatch) error {
+	err := eq.sendEvents(ctx, batch.events)
+	if err != nil {
+		eq.sendFailed(batch, err)
+	}
+	return err
+}
+
+// sendFailed schedules a batch that failed to send for redelivery, or
+// dead-letters it once it has used all attempts. Requests rejected by an
+// open circuit breaker or before a Retry-After deadline do not count as
+// attempts.
+func (eq *EventQueue) sendFailed(batch *eventBatch, err error) {
+	var fkErr *types.FlagKitError
+	notSent := errors.As(err, &fkErr) &&
+		(fkErr.Code == types.ErrCircuitOpen || fkErr.Code == types.ErrNetworkRetryAfter)
+	if !notSent {
+		batch.attempts++
+	}
+
+	persist := eq.persistEnabled && eq.persister != nil
+	var eventIDs []string
+	if persist {
+		eventIDs = make([]string, len(batch.events))
+		for i, e := range batch.events {
+			eventIDs[i] = e.ID
+		}
+	}
+
+	if batch.attempts >= eq.redelivery.MaxAttempts {
+		if eq.logger != nil {
+			eq.logger.Warn("Events not sent after max attempts, dead-lettering",
+				"count", len(batch.events), "attempts", batch.attempts)
+		}
+		eq.dropped(DropSendFailed, len(batch.events))
+		if persist {
+			if markErr := eq.persister.MarkDeadLetter(eventIDs); markErr != nil {
+				if eq.logger != nil {
+					eq.logger.Warn("Failed to mark events as dead-lettered", "error", markErr.Error())
+				}
+			}
+		}
 		return
 	}
 
+	// Mark events as failed if persistence is enabled. Events that were not
+	// sent stay marked as sending and recover as pending.
+	if persist && !notSent {
+		if markErr := eq.persister.MarkFailed(eventIDs); markErr != nil {
+			if eq.logger != nil {
+				eq.logger.Warn("Failed to mark events as failed", "error", markErr.Error())
+			}
+		}
+	}
+
+	delay := http.CalculateBackoff(max(batch.attempts, 1), eq.redelivery)
+	delay = max(delay, eq.blockedFor())
+	batch.nextAttempt = time.Now().Add(delay)
+
+	eq.mu.Lock()
+	eq.scheduleLocked(batch)
+	eq.mu.Unlock()
+	eq.reportDrops()
+
+	if eq.logger != nil {
+		eq.logger.Debug("Scheduled events for redelivery",
+			"count", len(batch.events), "attempts", batch.attempts, "delay", delay)
+	}
+}
+
+// scheduleLocked adds a batch awaiting redelivery, dropping the oldest
+// batches beyond the queue size (must be called with lock held).
+func (eq *EventQueue) scheduleLocked(batch *eventBatch) {
+	eq.retries = append(eq.retries, batch)
+
+	retrying := eq.retryingLocked()
+	for retrying > eq.config.MaxSize && len(eq.retries) > 1 {
+		oldest := eq.retries[0]
+		eq.retries = eq.retries[1:]
+		retrying -= len(oldest.events)
+		if eq.logger != nil {
+			eq.logger.Warn("Redelivery queue full, dropping events", "count", len(oldest.events))
+		}
+		eq.droppedLocked(DropQueueFull, len(oldest.events))
+	}
+}
+
+// sendEvents sends events to the server.
+func (eq *EventQueue) sendEvents(ctx context.Context, events []Event) error {
+	if eq.httpClient == nil {
+		return nil
+	}
+
 	// Collect event IDs for persistence tracking
 	var eventIDs []string
 	if eq.persistEnabled && eq.persister != nil {
@@ -336,20 +831,12 @@ func (eq *EventQueue) sendEvents(events []Event) {
 		"events": events,
 	}
 
-	_, err := eq.httpClient.Post("/sdk/events/batch", payload)
+	_, err := eq.httpClient.PostWithContext(ctx, eventsBatchPath, payload)
 	if err != nil {
 		if eq.logger != nil {
 			eq.logger.Warn("Failed to send events", "error", err.Error(), "count", len(events))
 		}
-		// Mark events as failed if persistence is enabled
-		if eq.persistEnabled && eq.persister != nil && len(eventIDs) > 0 {
-			if markErr := eq.persister.MarkFailed(eventIDs); markErr != nil {
-				if eq.logger != nil {
-					eq.logger.Warn("Failed to mark events as failed", "error", markErr.Error())
-				}
-			}
-		}
-		return
+		return err
 	}
 
 	// Mark events as sent on success
@@ -360,9 +847,12 @@ func (eq *EventQueue) sendEvents(events []Event) {
 			}
 		}
 	}
+	return nil
 }
 
-// RecoverEvents recovers pending events from persistence on startup.
+// RecoverEvents recovers pending events from persistence on startup. Events
+// that previously failed to send are scheduled for redelivery with their
+// attempt counts.
 func (eq *EventQueue) RecoverEvents() error {
 	if !eq.persistEnabled || eq.persister == nil {
 		return nil
@@ -377,38 +867,108 @@ func (eq *EventQueue) RecoverEvents() error {
 		return nil
 	}
 
+	defer eq.reportDrops()
 	eq.mu.Lock()
 	defer eq.mu.Unlock()
 
+	// Failed events are grouped into batches by attempt count
+	failed := make(map[int]*eventBatch)
+	now := time.Now()
+
 	// Add recovered events to the queue with priority
-	for _, pe := range recovered {
-		if len(eq.events) >= eq.config.MaxSize {
+	for i, pe := range recovered {
+		if len(eq.events)+eq.retryingLocked() >= eq.config.MaxSize {
 			if eq.logger != nil {
 				eq.logger.Warn("Event queue full during recovery, some events dropped")
 			}
+			eq.droppedLocked(DropQueueFull, len(recovered)-i)
 			break
 		}
 
-		event := Event{
-			ID:        pe.ID,
-			Type:      pe.Type,
-			Timestamp: time.UnixMilli(pe.Timestamp).UTC().Format(time.RFC3339),
-			SessionID: eq.sessionID,
-			SDKVersion: eq.sdkVersion,
-			Data:      pe.Data,
-			Context:   pe.Context,
+		event := eq.recoveredEvent(pe)
+
+		// Events that were being sent keep the attempts of earlier failures
+		if pe.Status == "failed" || pe.Attempts > 0 {
+			batch, ok := failed[pe.Attempts]
+			if !ok {
+				batch = &eventBatch{attempts: pe.Attempts, nextAttempt: now}
+				failed[pe.Attempts] = batch
+				eq.retries = append(eq.retries, batch)
+			}
+			batch.events = append(batch.events, event)
+			continue
 		}
+
 		// Insert at the beginning (priority)
 		eq.events = append([]Event{event}, eq.events...)
 	}
 
 	if eq.logger != nil {
-		eq.logger.Info("Recovered persisted events", "count", len(recovered))
+		eq.logger.Info("Recovered persisted events", "count", len(recovered), "failedBatches", len(failed))
 	}
 
 	return nil
 }
 
+// recoveredEvent returns the queue event of a persisted event.
+func (eq *EventQueue) recoveredEvent(pe PersistedEvent) Event {
+	return Event{
+		ID:          pe.ID,
+		Type:        pe.Type,
+		Timestamp:   time.UnixMilli(pe.Timestamp).UTC().Format(time.RFC3339),
+		SessionID:   eq.sessionID,
+		SDKVersion:  eq.sdkVersion,
+		Data:        pe.Data,
+		Context:     pe.Context,
+		MetricValue: pe.MetricValue,
+		Metadata:    pe.Metadata,
+	}
+}
+
+// dropped counts and reports events dropped without being sent.
+func (eq *EventQueue) dropped(reason string, count int) {
+	eq.countDropped(reason, count)
+	if eq.onDrop != nil {
+		eq.onDrop(reason, count)
+	}
+}
+
+// droppedLocked counts events dropped without being sent and holds them
+// back to be reported by reportDrops once the lock is released (must be
+// called with lock held).
+func (eq *EventQueue) droppedLocked(reason string, count int) {
+	eq.countDropped(reason, count)
+	if eq.onDrop != nil {
+		if eq.pendingDrops == nil {
+			eq.pendingDrops = make(map[string]int)
+		}
+		eq.pendingDrops[reason] += count
+	}
+}
+
+// countDropped adds dropped events to the counts by reason.
+func (eq *EventQueue) countDropped(reason string, count int) {
+	eq.dropsMu.Lock()
+	eq.drops[reason] += int64(count)
+	eq.dropsMu.Unlock()
+}
+
+// reportDrops calls the drop hook for the drops held back while the lock
+// was held (must be called without lock held).
+func (eq *EventQueue) reportDrops() {
+	if eq.onDrop == nil {
+		return
+	}
+	eq.mu.Lock()
+	pending := eq.pendingDrops
+	eq.pendingDrops = nil
+	eq.mu.Unlock()
+
+	for reason, count := range pending {
+		eq.onDrop(reason, count)
+	}
+}
+
 // SetPersister sets the event persister.
 func (eq *EventQueue) SetPersister(persister EventPersister, enabled bool) {
 	eq.mu.Lock()
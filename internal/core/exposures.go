@@ -0,0 +1,150 @@
+package core
+
+import (
+	"container/list"
+	"sort"
+	"sync"
+
+	"github.com/teracrafts/flagkit-go/internal/types"
+)
+
+// Experiment event types.
+const (
+	EventTypeExperimentExposure   = "experiment.exposure"
+	EventTypeExperimentConversion = "experiment.conversion"
+)
+
+// DefaultMaxExposureContexts is the default number of contexts whose
+// experiment exposures are remembered for conversion attribution.
+const DefaultMaxExposureContexts = 10000
+
+// Exposure records that a context was shown an experiment variant.
+type Exposure struct {
+	ExperimentKey string
+	Variant       string
+	Version       int
+}
+
+// contextExposures holds the exposures of a single context.
+type contextExposures struct {
+	contextKey string
+	exposures  map[string]Exposure
+}
+
+// ExposureTracker emits experiment exposure and conversion events.
+//
+// Exposures are remembered per context in a bounded LRU so that an exposure
+// event is only sent when a context first sees a variant, and so that
+// conversions can be attributed to the variants the context was exposed to.
+type ExposureTracker struct {
+	queue       *EventQueue
+	logger      types.Logger
+	maxContexts int
+	contexts    map[string]*list.Element
+	order       *list.List
+	mu          sync.Mutex
+}
+
+// NewExposureTracker creates a new exposure tracker that emits events to queue.
+func NewExposureTracker(queue *EventQueue, maxContexts int, logger types.Logger) *ExposureTracker {
+	if maxContexts <= 0 {
+		maxContexts = DefaultMaxExposureContexts
+	}
+	return &ExposureTracker{
+		queue:       queue,
+		logger:      logger,
+		maxContexts: maxContexts,
+		contexts:    make(map[string]*list.Element),
+		order:       list.New(),
+	}
+}
+
+// Expose records an exposure and emits an exposure event the first time the
+// context sees this variant of the experiment.
+func (et *ExposureTracker) Expose(contextKey string, exposure Exposure, ctx *types.EvaluationContext) {
+	et.mu.Lock()
+	entry := et.entry(contextKey)
+	if previous, ok := entry.exposures[exposure.ExperimentKey]; ok && previous == exposure {
+		et.mu.Unlock()
+		return
+	}
+	entry.exposures[exposure.ExperimentKey] = exposure
+	et.mu.Unlock()
+
+	if et.logger != nil {
+		et.logger.Debug("Experiment exposure", "experiment", exposure.ExperimentKey, "variant", exposure.Variant)
+	}
+
+	et.queue.TrackWithContext(EventTypeExperimentExposure, map[string]any{
+		"experimentKey": exposure.ExperimentKey,
+		"variant":       exposure.Variant,
+		"version":       exposure.Version,
+	}, ctx)
+}
+
+// Exposures returns the exposures recorded for a context, ordered by experiment key.
+func (et *ExposureTracker) Exposures(contextKey string) []Exposure {
+	et.mu.Lock()
+	defer et.mu.Unlock()
+
+	elem, ok := et.contexts[contextKey]
+	if !ok {
+		return nil
+	}
+
+	exposures := make([]Exposure, 0, len(elem.Value.(*contextExposures).exposures))
+	for _, exposure := range elem.Value.(*contextExposures).exposures {
+		exposures = append(exposures, exposure)
+	}
+	sort.Slice(exposures, func(i, j int) bool {
+		return exposures[i].ExperimentKey < exposures[j].ExperimentKey
+	})
+	return exposures
+}
+
+// Convert emits a conversion event attributed to the variants the context
+// has been exposed to under any of its context keys.
+func (et *ExposureTracker) Convert(metricKey string, value float64, contextKeys []string, ctx *types.EvaluationContext) {
+	seen := make(map[string]bool)
+	var experiments []map[string]any
+	for _, contextKey := range contextKeys {
+		for _, exposure := range et.Exposures(contextKey) {
+			if seen[exposure.ExperimentKey] {
+				continue
+			}
+			seen[exposure.ExperimentKey] = true
+			experiments = append(experiments, map[string]any{
+				"experimentKey": exposure.ExperimentKey,
+				"variant":       exposure.Variant,
+				"version":       exposure.Version,
+			})
+		}
+	}
+	if experiments == nil {
+		experiments = []map[string]any{}
+	}
+
+	et.queue.TrackWithContext(EventTypeExperimentConversion, map[string]any{
+		"metricKey":   metricKey,
+		"value":       value,
+		"experiments": experiments,
+	}, ctx)
+}
+
+// entry returns the exposures of a context, creating and evicting as needed.
+// Must be called with et.mu held.
+func (et *ExposureTracker) entry(contextKey string) *contextExposures {
+	if elem, ok := et.contexts[contextKey]; ok {
+		et.order.MoveToFront(elem)
+		return elem.Value.(*contextExposures)
+	}
+
+	entry := &contextExposures{contextKey: contextKey, exposures: make(map[string]Exposure)}
+	et.contexts[contextKey] = et.order.PushFront(entry)
+	if et.order.Len() > et.maxContexts {
+		oldest := et.order.Back()
+		et.order.Remove(oldest)
+		delete(et.contexts, oldest.Value.(*contextExposures).contextKey)
+	}
+	return entry
+}
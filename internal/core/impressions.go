@@ -0,0 +1,240 @@
+package core
+
+import (
+	"container/list"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/teracrafts/flagkit-go/internal/types"
+)
+
+// Evaluation event types.
+const (
+	EventTypeFlagEvaluated = "flag.evaluated"
+	EventTypeFlagSummary   = "flag.summary"
+)
+
+// Impression describes a single flag evaluation.
+type Impression struct {
+	FlagKey        string
+	Value          any
+	Version        int
+	Reason         string
+	VariationIndex *int
+	ContextKey     string
+}
+
+// ImpressionTrackerConfig contains impression tracking configuration.
+type ImpressionTrackerConfig struct {
+	// MaxContexts bounds the number of context+flag+version combinations
+	// remembered for deduplication.
+	MaxContexts   int
+	FlushInterval time.Duration
+}
+
+// DefaultImpressionTrackerConfig returns the default impression tracking configuration.
+func DefaultImpressionTrackerConfig() *ImpressionTrackerConfig {
+	return &ImpressionTrackerConfig{
+		MaxContexts:   10000,
+		FlushInterval: 30 * time.Second,
+	}
+}
+
+// summaryKey identifies an evaluation summary counter.
+type summaryKey struct {
+	flagKey   string
+	version   int
+	reason    string
+	variation string
+}
+
+// summaryCounter counts evaluations that produced the same result.
+type summaryCounter struct {
+	value          any
+	variationIndex *int
+	count          int
+}
+
+// ImpressionTracker records flag evaluations as analytics events.
+//
+// The first evaluation of a flag version for a context emits a flag.evaluated
+// event; repeat evaluations are suppressed using a bounded LRU. Every
+// evaluation is also counted and the counters are periodically emitted as a
+// single flag.summary event.
+type ImpressionTracker struct {
+	config    *ImpressionTrackerConfig
+	queue     *EventQueue
+	logger    types.Logger
+	seen      map[string]*list.Element
+	order     *list.List
+	counters  map[summaryKey]*summaryCounter
+	startTime time.Time
+	running   bool
+	stopCh    chan struct{}
+	mu        sync.Mutex
+}
+
+// NewImpressionTracker creates a new impression tracker that emits events to queue.
+func NewImpressionTracker(queue *EventQueue, config *ImpressionTrackerConfig, logger types.Logger) *ImpressionTracker {
+	if config == nil {
+		config = DefaultImpressionTrackerConfig()
+	}
+	if config.MaxContexts <= 0 {
+		config.MaxContexts = DefaultImpressionTrackerConfig().MaxContexts
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = DefaultImpressionTrackerConfig().FlushInterval
+	}
+
+	return &ImpressionTracker{
+		config:    config,
+		queue:     queue,
+		logger:    logger,
+		seen:      make(map[string]*list.Element),
+		order:     list.New(),
+		counters:  make(map[summaryKey]*summaryCounter),
+		startTime: time.Now(),
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start starts the background summary flush loop.
+func (it *ImpressionTracker) Start() {
+	it.mu.Lock()
+	if it.running {
+		it.mu.Unlock()
+		return
+	}
+	it.running = true
+	it.stopCh = make(chan struct{})
+	it.mu.Unlock()
+
+	go it.run()
+}
+
+// Stop stops the flush loop and emits any pending summary.
+func (it *ImpressionTracker) Stop() {
+	it.mu.Lock()
+	if !it.running {
+		it.mu.Unlock()
+		it.Flush()
+		return
+	}
+	it.running = false
+	close(it.stopCh)
+	it.mu.Unlock()
+
+	it.Flush()
+}
+
+// Record records a flag evaluation.
+func (it *ImpressionTracker) Record(imp Impression) {
+	it.mu.Lock()
+
+	variation := ""
+	if imp.VariationIndex != nil {
+		variation = strconv.Itoa(*imp.VariationIndex)
+	} else {
+		variation = fmt.Sprintf("%v", imp.Value)
+	}
+	key := summaryKey{flagKey: imp.FlagKey, version: imp.Version, reason: imp.Reason, variation: variation}
+	if counter, ok := it.counters[key]; ok {
+		counter.count++
+	} else {
+		it.counters[key] = &summaryCounter{value: imp.Value, variationIndex: imp.VariationIndex, count: 1}
+	}
+
+	isNew := it.markSeen(imp.ContextKey + "|" + imp.FlagKey + "|" + strconv.Itoa(imp.Version))
+	it.mu.Unlock()
+
+	if !isNew {
+		return
+	}
+
+	data := map[string]any{
+		"flagKey": imp.FlagKey,
+		"value":   imp.Value,
+		"version": imp.Version,
+		"reason":  imp.Reason,
+	}
+	if imp.VariationIndex != nil {
+		data["variationIndex"] = *imp.VariationIndex
+	}
+	if imp.ContextKey != "" {
+		data["contextKey"] = imp.ContextKey
+	}
+	it.queue.Track(EventTypeFlagEvaluated, data)
+}
+
+// Flush emits the evaluation counters collected since the last flush.
+func (it *ImpressionTracker) Flush() {
+	it.mu.Lock()
+	if len(it.counters) == 0 {
+		it.mu.Unlock()
+		return
+	}
+
+	counters := make([]map[string]any, 0, len(it.counters))
+	for key, counter := range it.counters {
+		entry := map[string]any{
+			"flagKey": key.flagKey,
+			"value":   counter.value,
+			"version": key.version,
+			"reason":  key.reason,
+			"count":   counter.count,
+		}
+		if counter.variationIndex != nil {
+			entry["variationIndex"] = *counter.variationIndex
+		}
+		counters = append(counters, entry)
+	}
+
+	now := time.Now()
+	data := map[string]any{
+		"startDate": it.startTime.UTC().Format(time.RFC3339),
+		"endDate":   now.UTC().Format(time.RFC3339),
+		"counters":  counters,
+	}
+	it.counters = make(map[summaryKey]*summaryCounter)
+	it.startTime = now
+	it.mu.Unlock()
+
+	if it.logger != nil {
+		it.logger.Debug("Flushing evaluation summary", "counters", len(counters))
+	}
+	it.queue.Track(EventTypeFlagSummary, data)
+}
+
+// markSeen records a deduplication key and returns whether it was new.
+// Must be called with it.mu held.
+func (it *ImpressionTracker) markSeen(key string) bool {
+	if elem, ok := it.seen[key]; ok {
+		it.order.MoveToFront(elem)
+		return false
+	}
+
+	it.seen[key] = it.order.PushFront(key)
+	if it.order.Len() > it.config.MaxContexts {
+		oldest := it.order.Back()
+		it.order.Remove(oldest)
+		delete(it.seen, oldest.Value.(string))
+	}
+	return true
+}
+
+// run is the background summary flush loop.
+func (it *ImpressionTracker) run() {
+	ticker := time.NewTicker(it.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-it.stopCh:
+			return
+		case <-ticker.C:
+			it.Flush()
+		}
+	}
+}
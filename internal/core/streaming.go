@@ -59,6 +59,12 @@ type StreamingConfig struct {
 	ReconnectInterval    time.Duration
 	MaxReconnectAttempts int
 	HeartbeatInterval    time.Duration
+	// HTTPClient is the client used for the token request and the stream.
+	// It should have no timeout. If nil, a client without a timeout is created.
+	HTTPClient *http.Client
+	// OnConnected is called each time the stream connects, including
+	// reconnects after falling back to polling.
+	OnConnected func()
 }
 
 // DefaultStreamingConfig returns the default streaming configuration.
@@ -114,6 +120,10 @@ func NewStreamingManager(
 	if config == nil {
 		config = DefaultStreamingConfig()
 	}
+	client := config.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 0} // No timeout for SSE
+	}
 	return &StreamingManager{
 		baseURL:                baseURL,
 		getAPIKey:              getAPIKey,
@@ -126,7 +136,7 @@ func NewStreamingManager(
 		onConnectionLimitError: onConnectionLimitError,
 		logger:                 logger,
 		state:                  StreamingStateDisconnected,
-		client:                 &http.Client{Timeout: 0}, // No timeout for SSE
+		client:                 client,
 	}
 }
 
@@ -314,6 +324,9 @@ func (sm *StreamingManager) handleOpen() {
 	if sm.logger != nil {
 		sm.logger.Info("Streaming connected")
 	}
+	if sm.config.OnConnected != nil {
+		sm.config.OnConnected()
+	}
 }
 
 // readEvents reads and processes SSE events.
@@ -0,0 +1,100 @@
+package evaluation
+
+import (
+	"testing"
+
+	"github.com/teracrafts/flagkit-go/internal/types"
+)
+
+func multiContext() *types.EvaluationContext {
+	return &types.EvaluationContext{
+		UserID: "user-1",
+		Email:  "jane@corp.io",
+		Custom: map[string]any{"plan": "free"},
+		Kinds: map[string]*types.ContextKind{
+			"org": {
+				Kind:       "org",
+				Key:        "acme",
+				Attributes: map[string]any{"plan": "enterprise", "seats": float64(250)},
+			},
+			"device": {
+				Kind:       "device",
+				Key:        "device-9",
+				Attributes: map[string]any{"os": "iOS"},
+			},
+		},
+	}
+}
+
+func TestKindClauses(t *testing.T) {
+	ctx := multiContext()
+
+	tests := []struct {
+		name   string
+		clause types.Clause
+		want   bool
+	}{
+		{"org attribute", types.Clause{Attribute: "org.plan", Operator: types.OpIn, Values: []any{"enterprise"}}, true},
+		{"org key", types.Clause{Attribute: "org.key", Operator: types.OpIn, Values: []any{"acme"}}, true},
+		{"org numeric attribute", types.Clause{Attribute: "org.seats", Operator: types.OpGreaterThan, Values: []any{float64(100)}}, true},
+		{"device attribute", types.Clause{Attribute: "device.os", Operator: types.OpIn, Values: []any{"iOS"}}, true},
+		{"user prefix", types.Clause{Attribute: "user.email", Operator: types.OpEndsWith, Values: []any{"@corp.io"}}, true},
+		{"unprefixed refers to user", types.Clause{Attribute: "plan", Operator: types.OpIn, Values: []any{"free"}}, true},
+		{"missing kind attribute never matches", types.Clause{Attribute: "org.region", Operator: types.OpIn, Values: []any{"eu"}, Negate: true}, false},
+		{"missing kind never matches", types.Clause{Attribute: "team.key", Operator: types.OpIn, Values: []any{"acme"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clauseMatches(&tt.clause, ctx); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEvaluatorTargetsOrganization(t *testing.T) {
+	def := types.FlagDefinition{
+		Key:         "sso",
+		FlagType:    types.FlagTypeBoolean,
+		Enabled:     true,
+		Variations:  []any{false, true},
+		Fallthrough: types.VariationOrRollout{Variation: intPtr(0)},
+		Rules: []types.TargetingRule{
+			{
+				ID: "enterprise-orgs",
+				Clauses: []types.Clause{
+					{Attribute: "org.plan", Operator: types.OpIn, Values: []any{"enterprise"}},
+					{Attribute: "device.os", Operator: types.OpIn, Values: []any{"iOS"}},
+				},
+				VariationOrRollout: types.VariationOrRollout{Variation: intPtr(1)},
+			},
+		},
+	}
+
+	e := NewEvaluator(nil)
+	e.SetFlags([]types.FlagDefinition{def})
+
+	detail, ok := e.Evaluate("sso", multiContext())
+	if !ok {
+		t.Fatal("expected flag to be evaluated")
+	}
+	if detail.Value != true || detail.Reason != types.ReasonTargeted {
+		t.Errorf("expected true/TARGETED, got %v/%s", detail.Value, detail.Reason)
+	}
+
+	detail, _ = e.Evaluate("sso", &types.EvaluationContext{UserID: "user-1"})
+	if detail.Value != false {
+		t.Errorf("expected fallthrough for a user-only context, got %v", detail.Value)
+	}
+}
+
+func TestBucketByKind(t *testing.T) {
+	a := multiContext()
+	b := multiContext()
+	b.UserID = "user-2"
+
+	if Bucket(a, "flag", "salt", "org.key") != Bucket(b, "flag", "salt", "org.key") {
+		t.Error("expected users of the same organization to share a bucket")
+	}
+}
@@ -0,0 +1,338 @@
+// Package evaluation provides the local rule-based flag evaluation engine.
+//
+// Flag rule definitions are downloaded from the server and evaluated
+// in-process against an EvaluationContext, so per-user targeting does not
+// require a network call per evaluation.
+package evaluation
+
+import (
+	"sync"
+
+	"github.com/teracrafts/flagkit-go/internal/types"
+)
+
+// Logger is an alias for the types.Logger interface.
+type Logger = types.Logger
+
+// Detail is the outcome of evaluating a flag definition against a context.
+type Detail struct {
+	FlagKey  string
+	Value    any
+	Enabled  bool
+	Reason   types.EvaluationReason
+	Version  int
+	FlagType types.FlagType
+
+	// VariationIndex is the index of the served variation, or -1 when no
+	// variation was served and the caller's default should be used.
+	VariationIndex int
+
+	// RuleIndex is the index of the matched targeting rule, or -1.
+	RuleIndex int
+
+	// RuleID is the ID of the matched targeting rule, if any.
+	RuleID string
+
+	// InRollout reports whether the variation was selected by a percentage rollout.
+	InRollout bool
+
+	// PrerequisiteKey is the key of the prerequisite that was not met, if any.
+	PrerequisiteKey string
+}
+
+// Evaluator evaluates flag definitions against evaluation contexts.
+type Evaluator struct {
+	flags    map[string]*types.FlagDefinition
+	segments *Segments
+	logger   Logger
+	mu       sync.RWMutex
+}
+
+// NewEvaluator creates a new evaluator with no flag definitions.
+func NewEvaluator(logger Logger) *Evaluator {
+	return &Evaluator{
+		flags:  make(map[string]*types.FlagDefinition),
+		logger: logger,
+	}
+}
+
+// SetFlags replaces all flag definitions.
+func (e *Evaluator) SetFlags(defs []types.FlagDefinition) {
+	flags := make(map[string]*types.FlagDefinition, len(defs))
+	for i := range defs {
+		def := defs[i]
+		flags[def.Key] = &def
+	}
+
+	e.mu.Lock()
+	e.flags = flags
+	e.mu.Unlock()
+
+	if e.logger != nil {
+		e.logger.Debug("Flag definitions loaded", "count", len(flags))
+	}
+}
+
+// SetSegments sets the segment store used by segmentMatch clauses.
+func (e *Evaluator) SetSegments(segments *Segments) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.segments = segments
+}
+
+// Upsert adds or replaces a single flag definition.
+// Definitions older than the one already stored are ignored.
+func (e *Evaluator) Upsert(def types.FlagDefinition) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if existing, ok := e.flags[def.Key]; ok && existing.Version > def.Version {
+		return
+	}
+	e.flags[def.Key] = &def
+}
+
+// Delete removes a flag definition.
+func (e *Evaluator) Delete(key string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.flags, key)
+}
+
+// Get returns a copy of a flag definition.
+func (e *Evaluator) Get(key string) (types.FlagDefinition, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	def, ok := e.flags[key]
+	if !ok {
+		return types.FlagDefinition{}, false
+	}
+	return *def, true
+}
+
+// Has checks if a flag definition exists.
+func (e *Evaluator) Has(key string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	_, ok := e.flags[key]
+	return ok
+}
+
+// Keys returns all flag definition keys.
+func (e *Evaluator) Keys() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	keys := make([]string, 0, len(e.flags))
+	for key := range e.flags {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Size returns the number of flag definitions.
+func (e *Evaluator) Size() int {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return len(e.flags)
+}
+
+// Dependencies returns the keys of all flags that a flag depends on through
+// prerequisites, direct prerequisites first. Keys of prerequisites without a
+// definition are included. Returns false if no definition exists for the key.
+func (e *Evaluator) Dependencies(key string) ([]string, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	def, ok := e.flags[key]
+	if !ok {
+		return nil, false
+	}
+
+	deps := make([]string, 0, len(def.Prerequisites))
+	seen := map[string]bool{key: true}
+	queue := []*types.FlagDefinition{def}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, prereq := range current.Prerequisites {
+			if seen[prereq.Key] {
+				continue
+			}
+			seen[prereq.Key] = true
+			deps = append(deps, prereq.Key)
+			if next, ok := e.flags[prereq.Key]; ok {
+				queue = append(queue, next)
+			}
+		}
+	}
+	return deps, true
+}
+
+// Evaluate evaluates a flag against the given context.
+// Returns false if no definition exists for the key.
+func (e *Evaluator) Evaluate(key string, ctx *types.EvaluationContext) (*Detail, bool) {
+	def, ok := e.getFlag(key)
+	if !ok {
+		return nil, false
+	}
+
+	return e.evaluateFlag(def, ctx, nil), true
+}
+
+// getFlag returns a flag definition.
+func (e *Evaluator) getFlag(key string) (*types.FlagDefinition, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	def, ok := e.flags[key]
+	return def, ok
+}
+
+// evaluateFlag evaluates a single flag definition.
+// visited holds the flags whose prerequisites are being evaluated, for cycle detection.
+func (e *Evaluator) evaluateFlag(def *types.FlagDefinition, ctx *types.EvaluationContext, visited map[string]bool) *Detail {
+	if !def.Enabled {
+		return e.offDetail(def, types.ReasonDisabled)
+	}
+
+	if key, failed := e.failedPrerequisite(def, ctx, visited); failed {
+		detail := e.offDetail(def, types.ReasonPrerequisiteFailed)
+		detail.PrerequisiteKey = key
+		return detail
+	}
+
+	for i, rule := range def.Rules {
+		if !e.ruleMatches(&rule, ctx) {
+			continue
+		}
+		detail := e.variationDetail(def, rule.VariationOrRollout, ctx, types.ReasonTargeted)
+		detail.RuleIndex = i
+		detail.RuleID = rule.ID
+		return detail
+	}
+
+	return e.variationDetail(def, def.Fallthrough, ctx, types.ReasonFallthrough)
+}
+
+// ruleMatches checks if all clauses of a rule match the context.
+func (e *Evaluator) ruleMatches(rule *types.TargetingRule, ctx *types.EvaluationContext) bool {
+	for i := range rule.Clauses {
+		clause := &rule.Clauses[i]
+		if clause.Operator == types.OpSegmentMatch {
+			if !segmentClauseMatches(clause, ctx, e.getSegments()) {
+				return false
+			}
+			continue
+		}
+		if !clauseMatches(clause, ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+// failedPrerequisite returns the key of the first prerequisite of a flag that
+// is not met. Prerequisites are evaluated recursively; a prerequisite is not
+// met if it has no definition, is part of a cycle, is off, or serves a
+// different variation.
+func (e *Evaluator) failedPrerequisite(def *types.FlagDefinition, ctx *types.EvaluationContext, visited map[string]bool) (string, bool) {
+	if len(def.Prerequisites) == 0 {
+		return "", false
+	}
+
+	if visited == nil {
+		visited = make(map[string]bool)
+	}
+	visited[def.Key] = true
+	defer delete(visited, def.Key)
+
+	for _, prereq := range def.Prerequisites {
+		if visited[prereq.Key] {
+			if e.logger != nil {
+				e.logger.Warn("Flag prerequisite cycle detected", "key", def.Key, "prerequisite", prereq.Key)
+			}
+			return prereq.Key, true
+		}
+
+		prereqDef, ok := e.getFlag(prereq.Key)
+		if !ok {
+			return prereq.Key, true
+		}
+
+		detail := e.evaluateFlag(prereqDef, ctx, visited)
+		if !detail.Enabled || detail.VariationIndex != prereq.Variation {
+			return prereq.Key, true
+		}
+	}
+	return "", false
+}
+
+// getSegments returns the segment store, if any.
+func (e *Evaluator) getSegments() *Segments {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.segments
+}
+
+// offDetail returns the detail for a flag that serves its off variation.
+func (e *Evaluator) offDetail(def *types.FlagDefinition, reason types.EvaluationReason) *Detail {
+	detail := newDetail(def, reason)
+	if def.OffVariation != nil {
+		e.applyVariation(def, detail, *def.OffVariation)
+	}
+	return detail
+}
+
+// variationDetail returns the detail for a served variation or rollout.
+func (e *Evaluator) variationDetail(def *types.FlagDefinition, vr types.VariationOrRollout, ctx *types.EvaluationContext, reason types.EvaluationReason) *Detail {
+	detail := newDetail(def, reason)
+	detail.Enabled = true
+
+	if vr.Variation != nil {
+		e.applyVariation(def, detail, *vr.Variation)
+		return detail
+	}
+
+	if vr.Rollout != nil {
+		bucket := Bucket(ctx, def.Key, def.Salt, vr.Rollout.BucketBy)
+		if index, ok := rolloutVariation(vr.Rollout, bucket); ok {
+			detail.InRollout = true
+			e.applyVariation(def, detail, index)
+			return detail
+		}
+	}
+
+	if e.logger != nil {
+		e.logger.Warn("Flag rule has no variation", "key", def.Key)
+	}
+	detail.Reason = types.ReasonError
+	detail.Enabled = false
+	return detail
+}
+
+// applyVariation sets the detail value from a variation index.
+func (e *Evaluator) applyVariation(def *types.FlagDefinition, detail *Detail, index int) {
+	if index < 0 || index >= len(def.Variations) {
+		if e.logger != nil {
+			e.logger.Warn("Flag variation index out of range", "key", def.Key, "index", index)
+		}
+		detail.Reason = types.ReasonError
+		detail.Enabled = false
+		return
+	}
+	detail.Value = def.Variations[index]
+	detail.VariationIndex = index
+}
+
+// newDetail creates a detail with no served variation.
+func newDetail(def *types.FlagDefinition, reason types.EvaluationReason) *Detail {
+	return &Detail{
+		FlagKey:        def.Key,
+		Reason:         reason,
+		Version:        def.Version,
+		FlagType:       def.FlagType,
+		VariationIndex: -1,
+		RuleIndex:      -1,
+	}
+}
@@ -0,0 +1,208 @@
+package evaluation
+
+import (
+	"testing"
+
+	"github.com/teracrafts/flagkit-go/internal/types"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func testFlag() types.FlagDefinition {
+	return types.FlagDefinition{
+		Key:          "new-checkout",
+		FlagType:     types.FlagTypeString,
+		Version:      3,
+		Enabled:      true,
+		Variations:   []any{"control", "treatment", "beta"},
+		OffVariation: intPtr(0),
+		Fallthrough:  types.VariationOrRollout{Variation: intPtr(0)},
+		Rules: []types.TargetingRule{
+			{
+				ID: "internal-users",
+				Clauses: []types.Clause{
+					{Attribute: "email", Operator: types.OpEndsWith, Values: []any{"@example.com"}},
+				},
+				VariationOrRollout: types.VariationOrRollout{Variation: intPtr(1)},
+			},
+			{
+				ID: "beta-app",
+				Clauses: []types.Clause{
+					{Attribute: "appVersion", Operator: types.OpSemVerGreaterThan, Values: []any{"2.0.0"}},
+					{Attribute: "country", Operator: types.OpIn, Values: []any{"US", "CA"}},
+				},
+				VariationOrRollout: types.VariationOrRollout{Variation: intPtr(2)},
+			},
+		},
+	}
+}
+
+func TestEvaluatorEvaluate(t *testing.T) {
+	e := NewEvaluator(nil)
+	e.SetFlags([]types.FlagDefinition{testFlag()})
+
+	t.Run("returns false for unknown flag", func(t *testing.T) {
+		if _, ok := e.Evaluate("unknown", nil); ok {
+			t.Error("expected unknown flag to not be evaluated")
+		}
+	})
+
+	t.Run("matches first targeting rule", func(t *testing.T) {
+		ctx := &types.EvaluationContext{UserID: "user-1", Email: "dev@example.com"}
+		detail, ok := e.Evaluate("new-checkout", ctx)
+		if !ok {
+			t.Fatal("expected flag to be evaluated")
+		}
+		if detail.Value != "treatment" || detail.Reason != types.ReasonTargeted {
+			t.Errorf("expected treatment/TARGETED, got %v/%s", detail.Value, detail.Reason)
+		}
+		if detail.RuleIndex != 0 || detail.RuleID != "internal-users" {
+			t.Errorf("expected rule 0 'internal-users', got %d '%s'", detail.RuleIndex, detail.RuleID)
+		}
+		if detail.Version != 3 || !detail.Enabled {
+			t.Errorf("expected version 3 and enabled, got %d and %v", detail.Version, detail.Enabled)
+		}
+	})
+
+	t.Run("requires all clauses to match", func(t *testing.T) {
+		ctx := &types.EvaluationContext{
+			UserID:  "user-2",
+			Country: "DE",
+			Custom:  map[string]any{"appVersion": "2.1.0"},
+		}
+		detail, _ := e.Evaluate("new-checkout", ctx)
+		if detail.Value != "control" || detail.Reason != types.ReasonFallthrough {
+			t.Errorf("expected control/FALLTHROUGH, got %v/%s", detail.Value, detail.Reason)
+		}
+
+		ctx.Country = "CA"
+		detail, _ = e.Evaluate("new-checkout", ctx)
+		if detail.Value != "beta" || detail.Reason != types.ReasonTargeted {
+			t.Errorf("expected beta/TARGETED, got %v/%s", detail.Value, detail.Reason)
+		}
+	})
+
+	t.Run("falls through with nil context", func(t *testing.T) {
+		detail, _ := e.Evaluate("new-checkout", nil)
+		if detail.Value != "control" || detail.Reason != types.ReasonFallthrough {
+			t.Errorf("expected control/FALLTHROUGH, got %v/%s", detail.Value, detail.Reason)
+		}
+	})
+}
+
+func TestEvaluatorDisabledFlag(t *testing.T) {
+	e := NewEvaluator(nil)
+
+	flag := testFlag()
+	flag.Enabled = false
+	e.SetFlags([]types.FlagDefinition{flag})
+
+	ctx := &types.EvaluationContext{Email: "dev@example.com"}
+	detail, _ := e.Evaluate("new-checkout", ctx)
+	if detail.Value != "control" || detail.Reason != types.ReasonDisabled || detail.Enabled {
+		t.Errorf("expected control/DISABLED/disabled, got %v/%s/%v", detail.Value, detail.Reason, detail.Enabled)
+	}
+
+	flag.OffVariation = nil
+	e.SetFlags([]types.FlagDefinition{flag})
+
+	detail, _ = e.Evaluate("new-checkout", ctx)
+	if detail.VariationIndex != -1 || detail.Value != nil {
+		t.Errorf("expected no variation without off variation, got %d/%v", detail.VariationIndex, detail.Value)
+	}
+}
+
+func TestEvaluatorInvalidVariation(t *testing.T) {
+	e := NewEvaluator(nil)
+
+	flag := testFlag()
+	flag.Fallthrough = types.VariationOrRollout{Variation: intPtr(7)}
+	e.SetFlags([]types.FlagDefinition{flag})
+
+	detail, _ := e.Evaluate("new-checkout", nil)
+	if detail.Reason != types.ReasonError || detail.VariationIndex != -1 {
+		t.Errorf("expected ERROR with no variation, got %s/%d", detail.Reason, detail.VariationIndex)
+	}
+}
+
+func TestEvaluatorUpsertAndDelete(t *testing.T) {
+	e := NewEvaluator(nil)
+	e.SetFlags([]types.FlagDefinition{testFlag()})
+
+	older := testFlag()
+	older.Version = 1
+	older.Enabled = false
+	e.Upsert(older)
+
+	if def, _ := e.Get("new-checkout"); def.Version != 3 {
+		t.Errorf("expected older definition to be ignored, got version %d", def.Version)
+	}
+
+	newer := testFlag()
+	newer.Version = 4
+	e.Upsert(newer)
+
+	if def, _ := e.Get("new-checkout"); def.Version != 4 {
+		t.Errorf("expected newer definition to be stored, got version %d", def.Version)
+	}
+
+	e.Delete("new-checkout")
+	if e.Has("new-checkout") || e.Size() != 0 {
+		t.Error("expected flag definition to be deleted")
+	}
+}
+
+func TestClauseOperators(t *testing.T) {
+	ctx := &types.EvaluationContext{
+		UserID:  "user-123",
+		Email:   "jane@corp.io",
+		Country: "US",
+		Custom: map[string]any{
+			"age":     float64(34),
+			"plan":    "enterprise",
+			"version": "1.4.2",
+			"groups":  []any{"admins", "beta"},
+			"seats":   25,
+		},
+	}
+
+	tests := []struct {
+		name   string
+		clause types.Clause
+		want   bool
+	}{
+		{"in matches string", types.Clause{Attribute: "country", Operator: types.OpIn, Values: []any{"CA", "US"}}, true},
+		{"in does not match", types.Clause{Attribute: "country", Operator: types.OpIn, Values: []any{"DE"}}, false},
+		{"in matches user key", types.Clause{Attribute: "key", Operator: types.OpIn, Values: []any{"user-123"}}, true},
+		{"in matches number across types", types.Clause{Attribute: "seats", Operator: types.OpIn, Values: []any{float64(25)}}, true},
+		{"in does not match numeric string", types.Clause{Attribute: "seats", Operator: types.OpIn, Values: []any{"25"}}, false},
+		{"in matches any slice element", types.Clause{Attribute: "groups", Operator: types.OpIn, Values: []any{"beta"}}, true},
+		{"startsWith", types.Clause{Attribute: "plan", Operator: types.OpStartsWith, Values: []any{"enter"}}, true},
+		{"endsWith", types.Clause{Attribute: "email", Operator: types.OpEndsWith, Values: []any{"@corp.io"}}, true},
+		{"contains", types.Clause{Attribute: "email", Operator: types.OpContains, Values: []any{"@corp"}}, true},
+		{"matches regex", types.Clause{Attribute: "email", Operator: types.OpMatches, Values: []any{`^[a-z]+@corp\.io$`}}, true},
+		{"invalid regex never matches", types.Clause{Attribute: "email", Operator: types.OpMatches, Values: []any{`([`}}, false},
+		{"lessThan", types.Clause{Attribute: "age", Operator: types.OpLessThan, Values: []any{float64(40)}}, true},
+		{"lessThanOrEqual", types.Clause{Attribute: "age", Operator: types.OpLessThanOrEqual, Values: []any{float64(34)}}, true},
+		{"greaterThan", types.Clause{Attribute: "age", Operator: types.OpGreaterThan, Values: []any{float64(34)}}, false},
+		{"greaterThanOrEqual", types.Clause{Attribute: "seats", Operator: types.OpGreaterThanOrEqual, Values: []any{float64(10)}}, true},
+		{"semVerEqual", types.Clause{Attribute: "version", Operator: types.OpSemVerEqual, Values: []any{"v1.4.2"}}, true},
+		{"semVerLessThan", types.Clause{Attribute: "version", Operator: types.OpSemVerLessThan, Values: []any{"1.10.0"}}, true},
+		{"semVerGreaterThan", types.Clause{Attribute: "version", Operator: types.OpSemVerGreaterThan, Values: []any{"1.5.0"}}, false},
+		{"semver invalid never matches", types.Clause{Attribute: "plan", Operator: types.OpSemVerEqual, Values: []any{"1.0.0"}}, false},
+		{"negate inverts match", types.Clause{Attribute: "country", Operator: types.OpIn, Values: []any{"DE"}, Negate: true}, true},
+		{"missing attribute never matches", types.Clause{Attribute: "missing", Operator: types.OpIn, Values: []any{"x"}}, false},
+		{"missing attribute ignores negate", types.Clause{Attribute: "missing", Operator: types.OpIn, Values: []any{"x"}, Negate: true}, false},
+		{"unknown operator never matches", types.Clause{Attribute: "country", Operator: "unknown", Values: []any{"US"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clauseMatches(&tt.clause, ctx); got != tt.want {
+				t.Errorf("clauseMatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
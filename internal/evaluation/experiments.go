@@ -0,0 +1,206 @@
+package evaluation
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/teracrafts/flagkit-go/internal/types"
+)
+
+// Assignment is the outcome of assigning a context to an experiment.
+type Assignment struct {
+	ExperimentKey string
+	Version       int
+	Variant       string
+	Payload       any
+
+	// VariantIndex is the index of the assigned variant, or -1 when the
+	// experiment has no variants.
+	VariantIndex int
+
+	// InExperiment reports whether the context is enrolled in the experiment.
+	// Contexts that are not enrolled are assigned the control variant.
+	InExperiment bool
+
+	// ContextKey identifies the context by its bucketing value, so that
+	// exposures are remembered per bucketed context. Empty when the context
+	// has no bucketing value.
+	ContextKey string
+}
+
+// Experiments assigns contexts to experiment variants.
+type Experiments struct {
+	experiments map[string]*types.ExperimentDefinition
+	logger      Logger
+	mu          sync.RWMutex
+}
+
+// NewExperiments creates a new experiment store with no definitions.
+func NewExperiments(logger Logger) *Experiments {
+	return &Experiments{
+		experiments: make(map[string]*types.ExperimentDefinition),
+		logger:      logger,
+	}
+}
+
+// SetExperiments replaces all experiment definitions.
+func (x *Experiments) SetExperiments(defs []types.ExperimentDefinition) {
+	experiments := make(map[string]*types.ExperimentDefinition, len(defs))
+	for i := range defs {
+		def := defs[i]
+		experiments[def.Key] = &def
+	}
+
+	x.mu.Lock()
+	x.experiments = experiments
+	x.mu.Unlock()
+
+	if x.logger != nil {
+		x.logger.Debug("Experiment definitions loaded", "count", len(experiments))
+	}
+}
+
+// Has checks if an experiment definition exists.
+func (x *Experiments) Has(key string) bool {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	_, ok := x.experiments[key]
+	return ok
+}
+
+// Keys returns all experiment keys.
+func (x *Experiments) Keys() []string {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	keys := make([]string, 0, len(x.experiments))
+	for key := range x.experiments {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Assign assigns a context to a variant of an experiment.
+// Returns false if no definition exists for the key.
+func (x *Experiments) Assign(key string, ctx *types.EvaluationContext) (*Assignment, bool) {
+	x.mu.RLock()
+	def, ok := x.experiments[key]
+	x.mu.RUnlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	return assignExperiment(def, ctx), true
+}
+
+// ContextKeys returns the distinct context keys of a context for the
+// bucketing attributes of all experiments, in sorted order.
+func (x *Experiments) ContextKeys(ctx *types.EvaluationContext) []string {
+	x.mu.RLock()
+	seen := make(map[string]bool)
+	for _, def := range x.experiments {
+		if key, ok := contextKey(ctx, def.BucketBy); ok {
+			seen[key] = true
+		}
+	}
+	x.mu.RUnlock()
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// contextKey returns the bucketing attribute and value of a context joined
+// by a colon, so that equal values of different attributes do not collide.
+func contextKey(ctx *types.EvaluationContext, bucketBy string) (string, bool) {
+	value, ok := bucketValue(ctx, bucketBy)
+	if !ok {
+		return "", false
+	}
+	if bucketBy == "" {
+		bucketBy = defaultBucketBy
+	}
+	return bucketBy + ":" + value, true
+}
+
+// assignExperiment assigns a context to a variant of a single experiment.
+//
+// Enrollment and variant selection use independent buckets, so growing the
+// traffic allocation only enrolls new contexts and never moves enrolled ones
+// to another variant.
+func assignExperiment(def *types.ExperimentDefinition, ctx *types.EvaluationContext) *Assignment {
+	control := &Assignment{
+		ExperimentKey: def.Key,
+		Version:       def.Version,
+		VariantIndex:  -1,
+	}
+	if len(def.Variants) == 0 {
+		return control
+	}
+	control.Variant = def.Variants[0].Key
+	control.Payload = def.Variants[0].Payload
+	control.VariantIndex = 0
+
+	if def.Status != types.ExperimentStatusRunning {
+		return control
+	}
+	key, ok := contextKey(ctx, def.BucketBy)
+	if !ok {
+		return control
+	}
+	for i := range def.Audience {
+		if !clauseMatches(&def.Audience[i], ctx) {
+			return control
+		}
+	}
+
+	allocation := types.RolloutWeightScale
+	if def.TrafficAllocation != nil {
+		allocation = min(max(*def.TrafficAllocation, 0), types.RolloutWeightScale)
+	}
+	traffic := float64(allocation) / types.RolloutWeightScale
+
+	if Bucket(ctx, def.Key+".traffic", def.Salt, def.BucketBy) >= traffic {
+		return control
+	}
+
+	index := variantForBucket(def.Variants, Bucket(ctx, def.Key, def.Salt, def.BucketBy))
+	return &Assignment{
+		ExperimentKey: def.Key,
+		Version:       def.Version,
+		Variant:       def.Variants[index].Key,
+		Payload:       def.Variants[index].Payload,
+		VariantIndex:  index,
+		InExperiment:  true,
+		ContextKey:    key,
+	}
+}
+
+// variantForBucket selects a variant for a bucket in [0, 1).
+// Weights are relative; if all weights are zero, variants are split evenly.
+func variantForBucket(variants []types.ExperimentVariant, bucket float64) int {
+	total := 0
+	for _, v := range variants {
+		if v.Weight > 0 {
+			total += v.Weight
+		}
+	}
+	if total == 0 {
+		return int(bucket * float64(len(variants)))
+	}
+
+	sum := 0.0
+	for i, v := range variants {
+		if v.Weight > 0 {
+			sum += float64(v.Weight) / float64(total)
+		}
+		if bucket < sum {
+			return i
+		}
+	}
+	return len(variants) - 1
+}
@@ -0,0 +1,139 @@
+package evaluation
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/teracrafts/flagkit-go/internal/types"
+)
+
+func testExperiment() types.ExperimentDefinition {
+	return types.ExperimentDefinition{
+		Key:     "pricing-page",
+		Version: 2,
+		Status:  types.ExperimentStatusRunning,
+		Salt:    "exp-salt",
+		Variants: []types.ExperimentVariant{
+			{Key: "control", Payload: map[string]any{"price": 10}, Weight: 50000},
+			{Key: "discount", Payload: map[string]any{"price": 8}, Weight: 50000},
+		},
+	}
+}
+
+func TestExperimentsAssign(t *testing.T) {
+	x := NewExperiments(nil)
+	x.SetExperiments([]types.ExperimentDefinition{testExperiment()})
+
+	if _, ok := x.Assign("unknown", nil); ok {
+		t.Error("expected unknown experiment to not be assigned")
+	}
+
+	counts := map[string]int{}
+	for i := 0; i < 2000; i++ {
+		ctx := &types.EvaluationContext{UserID: fmt.Sprintf("user-%d", i)}
+		a, ok := x.Assign("pricing-page", ctx)
+		if !ok || !a.InExperiment {
+			t.Fatalf("expected user-%d to be enrolled", i)
+		}
+		if again, _ := x.Assign("pricing-page", ctx); again.Variant != a.Variant {
+			t.Fatalf("expected stable assignment for user-%d", i)
+		}
+		counts[a.Variant]++
+	}
+
+	for _, variant := range []string{"control", "discount"} {
+		if counts[variant] < 900 || counts[variant] > 1100 {
+			t.Errorf("expected roughly even split, got %v", counts)
+		}
+	}
+}
+
+func TestExperimentsServeControlWhenNotEnrolled(t *testing.T) {
+	ctx := &types.EvaluationContext{UserID: "user-1", Country: "US"}
+
+	tests := []struct {
+		name   string
+		modify func(*types.ExperimentDefinition)
+		ctx    *types.EvaluationContext
+	}{
+		{"not running", func(d *types.ExperimentDefinition) { d.Status = "draft" }, ctx},
+		{"no bucketing value", func(d *types.ExperimentDefinition) {}, &types.EvaluationContext{Anonymous: true}},
+		{"nil context", func(d *types.ExperimentDefinition) {}, nil},
+		{"audience mismatch", func(d *types.ExperimentDefinition) {
+			d.Audience = []types.Clause{{Attribute: "country", Operator: types.OpIn, Values: []any{"DE"}}}
+		}, ctx},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def := testExperiment()
+			tt.modify(&def)
+
+			a := assignExperiment(&def, tt.ctx)
+			if a.InExperiment || a.Variant != "control" || a.VariantIndex != 0 {
+				t.Errorf("expected control and not enrolled, got %s/%v", a.Variant, a.InExperiment)
+			}
+		})
+	}
+}
+
+func TestExperimentsTrafficAllocation(t *testing.T) {
+	def := testExperiment()
+	def.TrafficAllocation = intPtr(10000) // 10%
+
+	enrolled := map[string]string{}
+	for i := 0; i < 2000; i++ {
+		key := fmt.Sprintf("user-%d", i)
+		if a := assignExperiment(&def, &types.EvaluationContext{UserID: key}); a.InExperiment {
+			enrolled[key] = a.Variant
+		}
+	}
+	if len(enrolled) < 150 || len(enrolled) > 250 {
+		t.Errorf("expected about 10%% enrolled, got %d", len(enrolled))
+	}
+
+	// Growing the allocation keeps enrolled contexts on their variant
+	def.TrafficAllocation = intPtr(50000)
+	for key, variant := range enrolled {
+		a := assignExperiment(&def, &types.EvaluationContext{UserID: key})
+		if !a.InExperiment || a.Variant != variant {
+			t.Errorf("expected %s to stay on %s, got %s/%v", key, variant, a.Variant, a.InExperiment)
+		}
+	}
+}
+
+func TestExperimentsZeroTrafficAllocation(t *testing.T) {
+	def := testExperiment()
+	def.TrafficAllocation = intPtr(0)
+
+	for i := 0; i < 100; i++ {
+		a := assignExperiment(&def, &types.EvaluationContext{UserID: fmt.Sprintf("user-%d", i)})
+		if a.InExperiment || a.Variant != "control" {
+			t.Fatalf("expected user-%d to not be enrolled, got %s/%v", i, a.Variant, a.InExperiment)
+		}
+	}
+}
+
+func TestExperimentsTrafficAllocationJSON(t *testing.T) {
+	var defs []types.ExperimentDefinition
+	if err := json.Unmarshal([]byte(`[{"key":"a"},{"key":"b","trafficAllocation":0}]`), &defs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if defs[0].TrafficAllocation != nil {
+		t.Errorf("expected no allocation when omitted, got %d", *defs[0].TrafficAllocation)
+	}
+	if defs[1].TrafficAllocation == nil || *defs[1].TrafficAllocation != 0 {
+		t.Errorf("expected zero allocation, got %v", defs[1].TrafficAllocation)
+	}
+}
+
+func TestExperimentsNoVariants(t *testing.T) {
+	def := testExperiment()
+	def.Variants = nil
+
+	a := assignExperiment(&def, &types.EvaluationContext{UserID: "user-1"})
+	if a.InExperiment || a.VariantIndex != -1 || a.Variant != "" {
+		t.Errorf("expected no assignment, got %+v", a)
+	}
+}
@@ -0,0 +1,234 @@
+package evaluation
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/teracrafts/flagkit-go/internal/types"
+	"github.com/teracrafts/flagkit-go/internal/version"
+)
+
+// regexCache caches compiled clause regular expressions.
+var regexCache sync.Map
+
+// clauseMatches checks if a clause matches the context.
+// A clause never matches when the attribute is missing, regardless of Negate.
+func clauseMatches(clause *types.Clause, ctx *types.EvaluationContext) bool {
+	value, ok := GetAttribute(ctx, clause.Attribute)
+	if !ok {
+		return false
+	}
+
+	matched := false
+	if values, isSlice := value.([]any); isSlice {
+		for _, v := range values {
+			if matchAny(clause.Operator, v, clause.Values) {
+				matched = true
+				break
+			}
+		}
+	} else {
+		matched = matchAny(clause.Operator, value, clause.Values)
+	}
+
+	if clause.Negate {
+		return !matched
+	}
+	return matched
+}
+
+// GetAttribute returns the value of a context attribute.
+// Attributes of other context kinds are referenced as "<kind>.<attribute>",
+// for example "org.plan"; "user.<attribute>" refers to the user.
+// Built-in attributes are looked up first, then custom attributes.
+func GetAttribute(ctx *types.EvaluationContext, attribute string) (any, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+
+	if name, attr, ok := strings.Cut(attribute, "."); ok {
+		if name == "user" {
+			return GetAttribute(ctx, attr)
+		}
+		if kind, ok := ctx.Kinds[name]; ok {
+			return kindAttribute(kind, attr)
+		}
+	}
+
+	switch attribute {
+	case "userId", "key":
+		return ctx.UserID, ctx.UserID != ""
+	case "email":
+		return ctx.Email, ctx.Email != ""
+	case "name":
+		return ctx.Name, ctx.Name != ""
+	case "anonymous":
+		return ctx.Anonymous, true
+	case "country":
+		return ctx.Country, ctx.Country != ""
+	case "deviceType":
+		return ctx.DeviceType, ctx.DeviceType != ""
+	case "os":
+		return ctx.OS, ctx.OS != ""
+	case "browser":
+		return ctx.Browser, ctx.Browser != ""
+	}
+
+	value, ok := ctx.Custom[attribute]
+	if !ok || value == nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// kindAttribute returns the value of an attribute of a context kind.
+func kindAttribute(kind *types.ContextKind, attribute string) (any, bool) {
+	if kind == nil {
+		return nil, false
+	}
+	if attribute == "key" {
+		return kind.Key, kind.Key != ""
+	}
+
+	value, ok := kind.Attributes[attribute]
+	if !ok || value == nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// matchAny checks if the operator matches the value against any clause value.
+func matchAny(op types.Operator, value any, clauseValues []any) bool {
+	for _, cv := range clauseValues {
+		if matchOne(op, value, cv) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchOne applies the operator to a single pair of values.
+func matchOne(op types.Operator, value, clauseValue any) bool {
+	switch op {
+	case types.OpIn:
+		return valuesEqual(value, clauseValue)
+
+	case types.OpStartsWith, types.OpEndsWith, types.OpContains:
+		s, ok1 := value.(string)
+		cs, ok2 := clauseValue.(string)
+		if !ok1 || !ok2 {
+			return false
+		}
+		switch op {
+		case types.OpStartsWith:
+			return strings.HasPrefix(s, cs)
+		case types.OpEndsWith:
+			return strings.HasSuffix(s, cs)
+		default:
+			return strings.Contains(s, cs)
+		}
+
+	case types.OpMatches:
+		s, ok1 := value.(string)
+		pattern, ok2 := clauseValue.(string)
+		if !ok1 || !ok2 {
+			return false
+		}
+		re := compileRegex(pattern)
+		return re != nil && re.MatchString(s)
+
+	case types.OpLessThan, types.OpLessThanOrEqual, types.OpGreaterThan, types.OpGreaterThanOrEqual:
+		a, ok1 := toFloat64(value)
+		b, ok2 := toFloat64(clauseValue)
+		if !ok1 || !ok2 {
+			return false
+		}
+		switch op {
+		case types.OpLessThan:
+			return a < b
+		case types.OpLessThanOrEqual:
+			return a <= b
+		case types.OpGreaterThan:
+			return a > b
+		default:
+			return a >= b
+		}
+
+	case types.OpSemVerEqual, types.OpSemVerLessThan, types.OpSemVerGreaterThan:
+		a, ok1 := value.(string)
+		b, ok2 := clauseValue.(string)
+		if !ok1 || !ok2 || version.Parse(a) == nil || version.Parse(b) == nil {
+			return false
+		}
+		cmp := version.Compare(a, b)
+		switch op {
+		case types.OpSemVerEqual:
+			return cmp == 0
+		case types.OpSemVerLessThan:
+			return cmp < 0
+		default:
+			return cmp > 0
+		}
+	}
+
+	return false
+}
+
+// valuesEqual compares two values, treating all numeric types as float64.
+func valuesEqual(a, b any) bool {
+	switch av := a.(type) {
+	case string:
+		bv, ok := b.(string)
+		return ok && av == bv
+	case bool:
+		bv, ok := b.(bool)
+		return ok && av == bv
+	case float64, float32, int, int32, int64:
+		fa, _ := toFloat64(av)
+		if _, isString := b.(string); isString {
+			return false
+		}
+		fb, ok := toFloat64(b)
+		return ok && fa == fb
+	}
+	return false
+}
+
+// toFloat64 converts numeric values (and numeric strings) to float64.
+func toFloat64(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// compileRegex compiles and caches a regular expression.
+// Returns nil if the pattern is invalid.
+func compileRegex(pattern string) *regexp.Regexp {
+	if cached, ok := regexCache.Load(pattern); ok {
+		re, _ := cached.(*regexp.Regexp)
+		return re
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		re = nil
+	}
+	regexCache.Store(pattern, re)
+	return re
+}
@@ -0,0 +1,142 @@
+package evaluation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/teracrafts/flagkit-go/internal/types"
+)
+
+func boolFlag(key string, enabled bool, prereqs ...types.Prerequisite) types.FlagDefinition {
+	return types.FlagDefinition{
+		Key:           key,
+		FlagType:      types.FlagTypeBoolean,
+		Enabled:       enabled,
+		Variations:    []any{false, true},
+		OffVariation:  intPtr(0),
+		Prerequisites: prereqs,
+		Fallthrough:   types.VariationOrRollout{Variation: intPtr(1)},
+	}
+}
+
+func TestEvaluatorPrerequisites(t *testing.T) {
+	ctx := &types.EvaluationContext{UserID: "user-1"}
+
+	t.Run("serves targeted value when prerequisites are met", func(t *testing.T) {
+		e := NewEvaluator(nil)
+		e.SetFlags([]types.FlagDefinition{
+			boolFlag("launch", true),
+			boolFlag("feature", true, types.Prerequisite{Key: "launch", Variation: 1}),
+		})
+
+		detail, _ := e.Evaluate("feature", ctx)
+		if detail.Value != true || detail.Reason != types.ReasonFallthrough {
+			t.Errorf("expected true/FALLTHROUGH, got %v/%s", detail.Value, detail.Reason)
+		}
+	})
+
+	t.Run("fails when prerequisite is off", func(t *testing.T) {
+		e := NewEvaluator(nil)
+		e.SetFlags([]types.FlagDefinition{
+			boolFlag("launch", false),
+			boolFlag("feature", true, types.Prerequisite{Key: "launch", Variation: 1}),
+		})
+
+		detail, _ := e.Evaluate("feature", ctx)
+		if detail.Value != false || detail.Reason != types.ReasonPrerequisiteFailed {
+			t.Errorf("expected false/PREREQUISITE_FAILED, got %v/%s", detail.Value, detail.Reason)
+		}
+		if detail.PrerequisiteKey != "launch" {
+			t.Errorf("expected failing key launch, got %q", detail.PrerequisiteKey)
+		}
+		if detail.Enabled {
+			t.Error("expected flag to not be enabled")
+		}
+	})
+
+	t.Run("fails when prerequisite serves another variation", func(t *testing.T) {
+		e := NewEvaluator(nil)
+		e.SetFlags([]types.FlagDefinition{
+			boolFlag("launch", true),
+			boolFlag("feature", true, types.Prerequisite{Key: "launch", Variation: 0}),
+		})
+
+		detail, _ := e.Evaluate("feature", ctx)
+		if detail.Reason != types.ReasonPrerequisiteFailed || detail.PrerequisiteKey != "launch" {
+			t.Errorf("expected PREREQUISITE_FAILED on launch, got %s on %q", detail.Reason, detail.PrerequisiteKey)
+		}
+	})
+
+	t.Run("fails when prerequisite is missing", func(t *testing.T) {
+		e := NewEvaluator(nil)
+		e.SetFlags([]types.FlagDefinition{
+			boolFlag("feature", true, types.Prerequisite{Key: "launch", Variation: 1}),
+		})
+
+		detail, _ := e.Evaluate("feature", ctx)
+		if detail.Reason != types.ReasonPrerequisiteFailed || detail.PrerequisiteKey != "launch" {
+			t.Errorf("expected PREREQUISITE_FAILED on launch, got %s on %q", detail.Reason, detail.PrerequisiteKey)
+		}
+	})
+
+	t.Run("evaluates prerequisites recursively", func(t *testing.T) {
+		e := NewEvaluator(nil)
+		e.SetFlags([]types.FlagDefinition{
+			boolFlag("train", false),
+			boolFlag("launch", true, types.Prerequisite{Key: "train", Variation: 1}),
+			boolFlag("feature", true, types.Prerequisite{Key: "launch", Variation: 1}),
+		})
+
+		detail, _ := e.Evaluate("feature", ctx)
+		if detail.Reason != types.ReasonPrerequisiteFailed || detail.PrerequisiteKey != "launch" {
+			t.Errorf("expected PREREQUISITE_FAILED on launch, got %s on %q", detail.Reason, detail.PrerequisiteKey)
+		}
+
+		detail, _ = e.Evaluate("launch", ctx)
+		if detail.PrerequisiteKey != "train" {
+			t.Errorf("expected failing key train, got %q", detail.PrerequisiteKey)
+		}
+	})
+
+	t.Run("detects cycles", func(t *testing.T) {
+		e := NewEvaluator(nil)
+		e.SetFlags([]types.FlagDefinition{
+			boolFlag("a", true, types.Prerequisite{Key: "b", Variation: 1}),
+			boolFlag("b", true, types.Prerequisite{Key: "c", Variation: 1}),
+			boolFlag("c", true, types.Prerequisite{Key: "a", Variation: 1}),
+		})
+
+		detail, _ := e.Evaluate("a", ctx)
+		if detail.Reason != types.ReasonPrerequisiteFailed {
+			t.Errorf("expected PREREQUISITE_FAILED, got %s", detail.Reason)
+		}
+	})
+}
+
+func TestEvaluatorDependencies(t *testing.T) {
+	e := NewEvaluator(nil)
+	e.SetFlags([]types.FlagDefinition{
+		boolFlag("train", true),
+		boolFlag("launch", true, types.Prerequisite{Key: "train", Variation: 1}),
+		boolFlag("feature", true,
+			types.Prerequisite{Key: "launch", Variation: 1},
+			types.Prerequisite{Key: "billing", Variation: 1}),
+		boolFlag("loop", true, types.Prerequisite{Key: "loop", Variation: 1}),
+	})
+
+	deps, ok := e.Dependencies("feature")
+	if !ok {
+		t.Fatal("expected dependencies for a known flag")
+	}
+	if want := []string{"launch", "billing", "train"}; !reflect.DeepEqual(deps, want) {
+		t.Errorf("expected %v, got %v", want, deps)
+	}
+
+	if deps, _ := e.Dependencies("loop"); len(deps) != 0 {
+		t.Errorf("expected a self-cycle to have no dependencies, got %v", deps)
+	}
+
+	if _, ok := e.Dependencies("unknown"); ok {
+		t.Error("expected unknown flag to have no dependencies")
+	}
+}
@@ -0,0 +1,93 @@
+package evaluation
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"math"
+	"strconv"
+
+	"github.com/teracrafts/flagkit-go/internal/types"
+)
+
+// defaultBucketBy is the context attribute used for bucketing when a rollout
+// does not specify one.
+const defaultBucketBy = "userId"
+
+// bucketHexLength is the number of hash hex characters used for bucketing.
+const bucketHexLength = 15
+
+// bucketScale is the largest value representable by bucketHexLength hex characters.
+var bucketScale = float64(0xFFFFFFFFFFFFFFF)
+
+// Bucket returns the context's bucket for a flag in the range [0, 1).
+//
+// The bucket is derived from the SHA-1 hash of "flagKey.salt.bucketValue",
+// so the same context always lands in the same bucket for a given flag.
+// Contexts without a usable bucketing value are placed in bucket 0.
+func Bucket(ctx *types.EvaluationContext, flagKey, salt, bucketBy string) float64 {
+	bucketValue, ok := bucketValue(ctx, bucketBy)
+	if !ok {
+		return 0
+	}
+
+	hash := sha1.Sum([]byte(flagKey + "." + salt + "." + bucketValue))
+	hexHash := hex.EncodeToString(hash[:])[:bucketHexLength]
+
+	intValue, err := strconv.ParseUint(hexHash, 16, 64)
+	if err != nil {
+		return 0
+	}
+
+	return float64(intValue) / bucketScale
+}
+
+// bucketValue returns the context's bucketing value for an attribute.
+func bucketValue(ctx *types.EvaluationContext, bucketBy string) (string, bool) {
+	if bucketBy == "" {
+		bucketBy = defaultBucketBy
+	}
+
+	value, ok := GetAttribute(ctx, bucketBy)
+	if !ok {
+		return "", false
+	}
+	return bucketableString(value)
+}
+
+// bucketableString converts a bucketing attribute value to a string.
+// Only strings and integral numbers can be used for bucketing.
+func bucketableString(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, v != ""
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case float64:
+		if v != math.Trunc(v) {
+			return "", false
+		}
+		return strconv.FormatInt(int64(v), 10), true
+	default:
+		return "", false
+	}
+}
+
+// rolloutVariation selects the rollout variation for a bucket.
+// If the weights do not cover the bucket, the last variation is used.
+func rolloutVariation(rollout *types.Rollout, bucket float64) (int, bool) {
+	if len(rollout.Variations) == 0 {
+		return 0, false
+	}
+
+	sum := 0.0
+	for _, wv := range rollout.Variations {
+		sum += float64(wv.Weight) / types.RolloutWeightScale
+		if bucket < sum {
+			return wv.Variation, true
+		}
+	}
+
+	return rollout.Variations[len(rollout.Variations)-1].Variation, true
+}
@@ -0,0 +1,151 @@
+package evaluation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/teracrafts/flagkit-go/internal/types"
+)
+
+func rolloutFlag(weights ...int) types.FlagDefinition {
+	rollout := &types.Rollout{}
+	for i, w := range weights {
+		rollout.Variations = append(rollout.Variations, types.WeightedVariation{Variation: i, Weight: w})
+	}
+	return types.FlagDefinition{
+		Key:         "gradual-launch",
+		FlagType:    types.FlagTypeBoolean,
+		Version:     1,
+		Enabled:     true,
+		Variations:  []any{false, true},
+		Salt:        "a1b2c3",
+		Fallthrough: types.VariationOrRollout{Rollout: rollout},
+	}
+}
+
+func TestBucketIsDeterministic(t *testing.T) {
+	ctx := &types.EvaluationContext{UserID: "user-42"}
+
+	first := Bucket(ctx, "flag", "salt", "")
+	for i := 0; i < 10; i++ {
+		if got := Bucket(ctx, "flag", "salt", ""); got != first {
+			t.Fatalf("expected bucket %v, got %v", first, got)
+		}
+	}
+
+	if first < 0 || first >= 1 {
+		t.Errorf("expected bucket in [0, 1), got %v", first)
+	}
+
+	if Bucket(ctx, "other-flag", "salt", "") == first {
+		t.Error("expected different flags to bucket independently")
+	}
+	if Bucket(ctx, "flag", "other-salt", "") == first {
+		t.Error("expected different salts to bucket independently")
+	}
+}
+
+func TestBucketByCustomAttribute(t *testing.T) {
+	a := &types.EvaluationContext{UserID: "user-1", Custom: map[string]any{"orgId": "org-7"}}
+	b := &types.EvaluationContext{UserID: "user-2", Custom: map[string]any{"orgId": "org-7"}}
+
+	if Bucket(a, "flag", "salt", "orgId") != Bucket(b, "flag", "salt", "orgId") {
+		t.Error("expected contexts with the same bucketing attribute to share a bucket")
+	}
+
+	c := &types.EvaluationContext{Custom: map[string]any{"orgId": float64(7)}}
+	d := &types.EvaluationContext{Custom: map[string]any{"orgId": "7"}}
+	if Bucket(c, "flag", "salt", "orgId") != Bucket(d, "flag", "salt", "orgId") {
+		t.Error("expected integral numbers to bucket like their string form")
+	}
+}
+
+func TestBucketWithoutBucketingValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      *types.EvaluationContext
+		bucketBy string
+	}{
+		{"nil context", nil, ""},
+		{"anonymous context", &types.EvaluationContext{Anonymous: true}, ""},
+		{"non-integral number", &types.EvaluationContext{Custom: map[string]any{"score": 1.5}}, "score"},
+		{"unsupported type", &types.EvaluationContext{Custom: map[string]any{"tags": []any{"a"}}}, "tags"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Bucket(tt.ctx, "flag", "salt", tt.bucketBy); got != 0 {
+				t.Errorf("expected bucket 0, got %v", got)
+			}
+		})
+	}
+}
+
+func TestRolloutDistribution(t *testing.T) {
+	e := NewEvaluator(nil)
+	e.SetFlags([]types.FlagDefinition{rolloutFlag(90000, 10000)})
+
+	enabled := 0
+	total := 10000
+	for i := 0; i < total; i++ {
+		ctx := &types.EvaluationContext{UserID: fmt.Sprintf("user-%d", i)}
+		detail, _ := e.Evaluate("gradual-launch", ctx)
+		if !detail.InRollout {
+			t.Fatal("expected variation to be selected by rollout")
+		}
+		if detail.Value == true {
+			enabled++
+		}
+	}
+
+	// 10% rollout should land within a reasonable margin
+	if enabled < 800 || enabled > 1200 {
+		t.Errorf("expected roughly 1000 users in the 10%% bucket, got %d", enabled)
+	}
+}
+
+func TestRolloutIsStableAsPercentageGrows(t *testing.T) {
+	small := NewEvaluator(nil)
+	small.SetFlags([]types.FlagDefinition{rolloutFlag(99000, 1000)})
+
+	large := NewEvaluator(nil)
+	large.SetFlags([]types.FlagDefinition{rolloutFlag(50000, 50000)})
+
+	for i := 0; i < 2000; i++ {
+		ctx := &types.EvaluationContext{UserID: fmt.Sprintf("user-%d", i)}
+		before, _ := small.Evaluate("gradual-launch", ctx)
+		after, _ := large.Evaluate("gradual-launch", ctx)
+		if before.Value == true && after.Value != true {
+			t.Fatalf("user %d left the rollout when it grew from 1%% to 50%%", i)
+		}
+	}
+}
+
+func TestRolloutVariation(t *testing.T) {
+	rollout := &types.Rollout{Variations: []types.WeightedVariation{
+		{Variation: 2, Weight: 25000},
+		{Variation: 0, Weight: 25000},
+	}}
+
+	tests := []struct {
+		bucket float64
+		want   int
+	}{
+		{0, 2},
+		{0.2499, 2},
+		{0.25, 0},
+		{0.4999, 0},
+		{0.9, 0}, // weights short of 100% fall back to the last variation
+	}
+
+	for _, tt := range tests {
+		got, ok := rolloutVariation(rollout, tt.bucket)
+		if !ok || got != tt.want {
+			t.Errorf("rolloutVariation(%v) = %d, want %d", tt.bucket, got, tt.want)
+		}
+	}
+
+	if _, ok := rolloutVariation(&types.Rollout{}, 0.5); ok {
+		t.Error("expected empty rollout to select no variation")
+	}
+}
@@ -0,0 +1,173 @@
+package evaluation
+
+import (
+	"hash/fnv"
+	"sync"
+
+	"github.com/teracrafts/flagkit-go/internal/types"
+)
+
+// keySet is a set of hashed context keys.
+//
+// Keys are stored as 64-bit FNV-1a hashes so that large include and exclude
+// lists take a fixed 8 bytes per key. The false-positive probability for a
+// list of 100,000 keys is below one in a billion.
+type keySet map[uint64]struct{}
+
+// newKeySet builds a hashed set from context keys.
+func newKeySet(keys []string) keySet {
+	if len(keys) == 0 {
+		return nil
+	}
+	set := make(keySet, len(keys))
+	for _, key := range keys {
+		set[hashKey(key)] = struct{}{}
+	}
+	return set
+}
+
+// contains checks if a key is in the set.
+func (s keySet) contains(key string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	_, ok := s[hashKey(key)]
+	return ok
+}
+
+// hashKey hashes a context key for set membership.
+func hashKey(key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return h.Sum64()
+}
+
+// segment is a compiled segment definition.
+type segment struct {
+	key      string
+	version  int
+	included keySet
+	excluded keySet
+	rules    []types.SegmentRule
+}
+
+// Segments evaluates segment membership.
+type Segments struct {
+	segments map[string]*segment
+	logger   Logger
+	mu       sync.RWMutex
+}
+
+// NewSegments creates a new segment store with no definitions.
+func NewSegments(logger Logger) *Segments {
+	return &Segments{
+		segments: make(map[string]*segment),
+		logger:   logger,
+	}
+}
+
+// SetSegments replaces all segment definitions.
+func (s *Segments) SetSegments(defs []types.SegmentDefinition) {
+	segments := make(map[string]*segment, len(defs))
+	for i := range defs {
+		segments[defs[i].Key] = &segment{
+			key:      defs[i].Key,
+			version:  defs[i].Version,
+			included: newKeySet(defs[i].Included),
+			excluded: newKeySet(defs[i].Excluded),
+			rules:    defs[i].Rules,
+		}
+	}
+
+	s.mu.Lock()
+	s.segments = segments
+	s.mu.Unlock()
+
+	if s.logger != nil {
+		s.logger.Debug("Segment definitions loaded", "count", len(segments))
+	}
+}
+
+// Has checks if a segment definition exists.
+func (s *Segments) Has(key string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.segments[key]
+	return ok
+}
+
+// Keys returns all segment keys.
+func (s *Segments) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]string, 0, len(s.segments))
+	for key := range s.segments {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Contains checks if the context is a member of a segment.
+// Returns false for unknown segments.
+//
+// Explicitly included context keys are always members and explicitly
+// excluded keys never are; otherwise the context is a member if any
+// segment rule matches.
+func (s *Segments) Contains(key string, ctx *types.EvaluationContext) bool {
+	s.mu.RLock()
+	seg, ok := s.segments[key]
+	s.mu.RUnlock()
+
+	if !ok || ctx == nil {
+		return false
+	}
+
+	if ctx.UserID != "" {
+		if seg.included.contains(ctx.UserID) {
+			return true
+		}
+		if seg.excluded.contains(ctx.UserID) {
+			return false
+		}
+	}
+
+	for i := range seg.rules {
+		if segmentRuleMatches(&seg.rules[i], ctx) {
+			return true
+		}
+	}
+	return false
+}
+
+// segmentRuleMatches checks if all clauses of a segment rule match.
+// Segment rules cannot reference other segments.
+func segmentRuleMatches(rule *types.SegmentRule, ctx *types.EvaluationContext) bool {
+	for i := range rule.Clauses {
+		if rule.Clauses[i].Operator == types.OpSegmentMatch {
+			return false
+		}
+		if !clauseMatches(&rule.Clauses[i], ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+// segmentClauseMatches checks a segmentMatch clause against the segment store.
+func segmentClauseMatches(clause *types.Clause, ctx *types.EvaluationContext, segments *Segments) bool {
+	matched := false
+	if segments != nil {
+		for _, v := range clause.Values {
+			if key, ok := v.(string); ok && segments.Contains(key, ctx) {
+				matched = true
+				break
+			}
+		}
+	}
+
+	if clause.Negate {
+		return !matched
+	}
+	return matched
+}
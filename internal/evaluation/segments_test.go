@@ -0,0 +1,116 @@
+package evaluation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/teracrafts/flagkit-go/internal/types"
+)
+
+func testSegments() *Segments {
+	s := NewSegments(nil)
+	s.SetSegments([]types.SegmentDefinition{
+		{
+			Key:      "beta-testers",
+			Version:  1,
+			Included: []string{"user-1", "user-2"},
+			Excluded: []string{"user-3"},
+			Rules: []types.SegmentRule{
+				{Clauses: []types.Clause{{Attribute: "email", Operator: types.OpEndsWith, Values: []any{"@example.com"}}}},
+			},
+		},
+	})
+	return s
+}
+
+func TestSegmentsContains(t *testing.T) {
+	s := testSegments()
+
+	tests := []struct {
+		name string
+		ctx  *types.EvaluationContext
+		want bool
+	}{
+		{"included key", &types.EvaluationContext{UserID: "user-1"}, true},
+		{"excluded key", &types.EvaluationContext{UserID: "user-3", Email: "dev@example.com"}, false},
+		{"matching rule", &types.EvaluationContext{UserID: "user-9", Email: "dev@example.com"}, true},
+		{"no match", &types.EvaluationContext{UserID: "user-9", Email: "dev@other.com"}, false},
+		{"nil context", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.Contains("beta-testers", tt.ctx); got != tt.want {
+				t.Errorf("Contains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if s.Contains("unknown", &types.EvaluationContext{UserID: "user-1"}) {
+		t.Error("expected unknown segment to not contain context")
+	}
+}
+
+func TestSegmentsLargeIncludeList(t *testing.T) {
+	keys := make([]string, 100000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("user-%d", i)
+	}
+
+	s := NewSegments(nil)
+	s.SetSegments([]types.SegmentDefinition{{Key: "large", Included: keys}})
+
+	if !s.Contains("large", &types.EvaluationContext{UserID: "user-99999"}) {
+		t.Error("expected included key to be a member")
+	}
+	if s.Contains("large", &types.EvaluationContext{UserID: "user-100000"}) {
+		t.Error("expected key outside the list to not be a member")
+	}
+}
+
+func TestEvaluatorSegmentMatchClause(t *testing.T) {
+	e := NewEvaluator(nil)
+	e.SetSegments(testSegments())
+
+	flag := testFlag()
+	flag.Rules = []types.TargetingRule{{
+		ID:                 "beta",
+		Clauses:            []types.Clause{{Operator: types.OpSegmentMatch, Values: []any{"beta-testers"}}},
+		VariationOrRollout: types.VariationOrRollout{Variation: intPtr(2)},
+	}}
+	e.SetFlags([]types.FlagDefinition{flag})
+
+	detail, _ := e.Evaluate("new-checkout", &types.EvaluationContext{UserID: "user-2"})
+	if detail.Value != "beta" || detail.Reason != types.ReasonTargeted {
+		t.Errorf("expected beta/TARGETED, got %v/%s", detail.Value, detail.Reason)
+	}
+
+	detail, _ = e.Evaluate("new-checkout", &types.EvaluationContext{UserID: "user-3"})
+	if detail.Value != "control" || detail.Reason != types.ReasonFallthrough {
+		t.Errorf("expected control/FALLTHROUGH, got %v/%s", detail.Value, detail.Reason)
+	}
+
+	flag.Rules[0].Clauses[0].Negate = true
+	e.SetFlags([]types.FlagDefinition{flag})
+
+	detail, _ = e.Evaluate("new-checkout", &types.EvaluationContext{UserID: "user-3"})
+	if detail.Value != "beta" {
+		t.Errorf("expected negated segment match to serve beta, got %v", detail.Value)
+	}
+}
+
+func TestEvaluatorSegmentMatchWithoutSegments(t *testing.T) {
+	e := NewEvaluator(nil)
+
+	flag := testFlag()
+	flag.Rules = []types.TargetingRule{{
+		Clauses:            []types.Clause{{Operator: types.OpSegmentMatch, Values: []any{"beta-testers"}}},
+		VariationOrRollout: types.VariationOrRollout{Variation: intPtr(2)},
+	}}
+	e.SetFlags([]types.FlagDefinition{flag})
+
+	detail, _ := e.Evaluate("new-checkout", &types.EvaluationContext{UserID: "user-1"})
+	if detail.Reason != types.ReasonFallthrough {
+		t.Errorf("expected FALLTHROUGH without segments, got %s", detail.Reason)
+	}
+}
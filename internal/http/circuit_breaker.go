@@ -38,6 +38,8 @@ type CircuitBreakerConfig struct {
 	SuccessThreshold   int
 	ResetTimeout       time.Duration
 	HalfOpenMaxAllowed int
+	// OnStateChange is called after the circuit changes state.
+	OnStateChange func(from, to CircuitState)
 }
 
 // DefaultCircuitBreakerConfig returns the default circuit breaker configuration.
@@ -59,10 +61,17 @@ type CircuitBreaker struct {
 	lastFailureTime    time.Time
 	halfOpenAllowed    int
 	halfOpenInProgress int
+	changes            []stateChange
 	mu                 sync.Mutex
 	logger             circuitLogger
 }
 
+// stateChange is a state change to report once the lock is released.
+type stateChange struct {
+	from CircuitState
+	to   CircuitState
+}
+
 // NewCircuitBreaker creates a new circuit breaker.
 func NewCircuitBreaker(config *CircuitBreakerConfig) *CircuitBreaker {
 	if config == nil {
@@ -77,7 +86,7 @@ func NewCircuitBreaker(config *CircuitBreakerConfig) *CircuitBreaker {
 // Allow checks if a request should be allowed.
 func (cb *CircuitBreaker) Allow() bool {
 	cb.mu.Lock()
-	defer cb.mu.Unlock()
+	defer cb.unlock()
 
 	switch cb.state {
 	case CircuitClosed:
@@ -108,7 +117,7 @@ func (cb *CircuitBreaker) Allow() bool {
 // RecordSuccess records a successful request.
 func (cb *CircuitBreaker) RecordSuccess() {
 	cb.mu.Lock()
-	defer cb.mu.Unlock()
+	defer cb.unlock()
 
 	switch cb.state {
 	case CircuitHalfOpen:
@@ -127,7 +136,7 @@ func (cb *CircuitBreaker) RecordSuccess() {
 // RecordFailure records a failed request.
 func (cb *CircuitBreaker) RecordFailure() {
 	cb.mu.Lock()
-	defer cb.mu.Unlock()
+	defer cb.unlock()
 
 	cb.lastFailureTime = time.Now()
 
@@ -151,6 +160,21 @@ func (cb *CircuitBreaker) State() CircuitState {
 	return cb.state
 }
 
+// OpenFor returns how long the circuit stays open before a trial request is
+// allowed, or zero if requests are allowed now.
+func (cb *CircuitBreaker) OpenFor() time.Duration {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state != CircuitOpen {
+		return 0
+	}
+	if remaining := cb.config.ResetTimeout - time.Since(cb.lastFailureTime); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
 // Reset resets the circuit breaker to closed state.
 func (cb *CircuitBreaker) Reset() {
 	cb.mu.Lock()
@@ -163,10 +187,27 @@ func (cb *CircuitBreaker) Reset() {
 	cb.halfOpenInProgress = 0
 }
 
+// unlock releases the lock and reports the state changes made while it was held,
+// so that callbacks may use the circuit breaker.
+func (cb *CircuitBreaker) unlock() {
+	changes := cb.changes
+	cb.changes = nil
+	cb.mu.Unlock()
+
+	if cb.config.OnStateChange != nil {
+		for _, change := range changes {
+			cb.config.OnStateChange(change.from, change.to)
+		}
+	}
+}
+
 // transitionTo transitions to a new state.
 func (cb *CircuitBreaker) transitionTo(newState CircuitState) {
 	oldState := cb.state
 	cb.state = newState
+	if oldState != newState {
+		cb.changes = append(cb.changes, stateChange{from: oldState, to: newState})
+	}
 
 	// Reset counters on state change
 	cb.failures = 0
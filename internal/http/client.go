@@ -31,6 +31,7 @@ const (
 	ErrNetworkError      = types.ErrNetworkError
 	ErrNetworkTimeout    = types.ErrNetworkTimeout
 	ErrNetworkRetryLimit = types.ErrNetworkRetryLimit
+	ErrNetworkRetryAfter = types.ErrNetworkRetryAfter
 	ErrAuthUnauthorized  = types.ErrAuthUnauthorized
 	ErrAuthInvalidKey    = types.ErrAuthInvalidKey
 	ErrEvalFlagNotFound  = types.ErrEvalFlagNotFound
@@ -46,7 +47,7 @@ var NewErrorWithCause = types.NewErrorWithCause
 var NetworkError = types.NetworkError
 
 // SDKVersion should be set by the main package.
-var SDKVersion = "1.1.0"
+var SDKVersion = "1.2.0"
 
 // defaultBaseURL is the internal base URL for the FlagKit API.
 const defaultBaseURL = "https://api.flagkit.dev/api/v1"
@@ -65,14 +66,21 @@ type HTTPClient struct {
 	timeout                time.Duration
 	client                 *http.Client
 	retry                  *RetryConfig
+	pathRetry              map[string]*RetryConfig
 	circuitBreaker         *CircuitBreaker
 	logger                 Logger
 	onUsageUpdate          UsageUpdateCallback
+	onRequest              RequestCallback
+	onRetry                RetryCallback
+	retryAfter             map[string]time.Time
 	mu                     sync.RWMutex
 }
 
 // HTTPClientConfig contains HTTP client configuration.
 type HTTPClientConfig struct {
+	// BaseURL is the API base URL. If empty, or the production URL, the URL
+	// is selected by the FLAGKIT_MODE environment variable.
+	BaseURL                string
 	APIKey                 string
 	SecondaryAPIKey        string
 	KeyRotationGracePeriod time.Duration
@@ -83,6 +91,19 @@ type HTTPClientConfig struct {
 	Logger                 Logger
 	// OnUsageUpdate is called when usage metrics are received from API responses.
 	OnUsageUpdate UsageUpdateCallback
+	// Client is the client used to send requests. If nil, a client with
+	// Timeout and Transport is created.
+	Client *http.Client
+	// Transport is the transport of the created client.
+	// If nil, http.DefaultTransport is used.
+	Transport http.RoundTripper
+	// PathRetry overrides Retry for requests to the given paths, for example
+	// "/sdk/init". Query strings are ignored when matching.
+	PathRetry map[string]*RetryConfig
+	// OnRequest is called after each request attempt.
+	OnRequest RequestCallback
+	// OnRetry is called before a failed request is retried.
+	OnRetry RetryCallback
 }
 
 // UsageMetrics contains usage metrics extracted from response headers.
@@ -101,6 +122,14 @@ type UsageMetrics struct {
 // UsageUpdateCallback is the callback type for usage metrics updates.
 type UsageUpdateCallback func(metrics *UsageMetrics)
 
+// RequestCallback is called after a request attempt with the endpoint path
+// without query string, the response status code, or 0 if no response was
+// received, and the duration of the attempt.
+type RequestCallback func(endpoint string, statusCode int, duration time.Duration)
+
+// RetryCallback is called before a request to an endpoint path is retried.
+type RetryCallback func(endpoint string, attempt int)
+
 // HTTPResponse represents an HTTP response.
 type HTTPResponse struct {
 	StatusCode   int
@@ -112,12 +141,9 @@ type HTTPResponse struct {
 
 // NewHTTPClient creates a new HTTP client.
 func NewHTTPClient(config *HTTPClientConfig) *HTTPClient {
-	baseURL := defaultBaseURL
-	switch strings.ToLower(strings.TrimSpace(os.Getenv("FLAGKIT_MODE"))) {
-	case "local":
-		baseURL = localBaseURL
-	case "beta":
-		baseURL = betaBaseURL
+	baseURL := strings.TrimRight(config.BaseURL, "/")
+	if baseURL == "" || baseURL == defaultBaseURL {
+		baseURL = modeBaseURL()
 	}
 
 	gracePeriod := config.KeyRotationGracePeriod
@@ -133,11 +159,18 @@ func NewHTTPClient(config *HTTPClientConfig) *HTTPClient {
 		keyRotationGracePeriod: gracePeriod,
 		enableRequestSigning:   config.EnableRequestSigning,
 		timeout:                config.Timeout,
-		client: &http.Client{
-			Timeout: config.Timeout,
-		},
-		logger:        config.Logger,
-		onUsageUpdate: config.OnUsageUpdate,
+		client:                 config.Client,
+		logger:                 config.Logger,
+		onUsageUpdate:          config.OnUsageUpdate,
+		onRequest:              config.OnRequest,
+		onRetry:                config.OnRetry,
+	}
+
+	if client.client == nil {
+		client.client = &http.Client{
+			Timeout:   config.Timeout,
+			Transport: config.Transport,
+		}
 	}
 
 	if config.Retry != nil {
@@ -145,6 +178,7 @@ func NewHTTPClient(config *HTTPClientConfig) *HTTPClient {
 	} else {
 		client.retry = DefaultRetryConfig()
 	}
+	client.pathRetry = config.PathRetry
 
 	if config.CircuitBreaker != nil {
 		client.circuitBreaker = NewCircuitBreaker(config.CircuitBreaker)
@@ -155,6 +189,30 @@ func NewHTTPClient(config *HTTPClientConfig) *HTTPClient {
 	return client
 }
 
+// modeBaseURL returns the API base URL selected by the FLAGKIT_MODE environment variable.
+func modeBaseURL() string {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("FLAGKIT_MODE"))) {
+	case "local":
+		return localBaseURL
+	case "beta":
+		return betaBaseURL
+	}
+	return defaultBaseURL
+}
+
+// GetBaseURL returns the API base URL.
+func (c *HTTPClient) GetBaseURL() string {
+	return c.baseURL
+}
+
+// StreamingClient returns a client for long-lived streaming connections.
+// It shares the transport of the API client but has no timeout.
+func (c *HTTPClient) StreamingClient() *http.Client {
+	streaming := *c.client
+	streaming.Timeout = 0
+	return &streaming
+}
+
 // GetActiveAPIKey returns the currently active API key.
 func (c *HTTPClient) GetActiveAPIKey() string {
 	c.mu.RLock()
@@ -185,6 +243,17 @@ func (c *HTTPClient) IsInKeyRotation() bool {
 	return elapsed < c.keyRotationGracePeriod
 }
 
+// CircuitStats returns statistics of the circuit breaker.
+func (c *HTTPClient) CircuitStats() map[string]any {
+	return c.circuitBreaker.Stats()
+}
+
+// CircuitOpenFor returns how long the circuit breaker rejects requests, or
+// zero if requests are allowed.
+func (c *HTTPClient) CircuitOpenFor() time.Duration {
+	return c.circuitBreaker.OpenFor()
+}
+
 // rotateToSecondaryKey attempts to rotate to the secondary API key.
 // Returns true if rotation was successful.
 func (c *HTTPClient) rotateToSecondaryKey() bool {
@@ -279,15 +348,29 @@ func (c *HTTPClient) postWithKeyRotation(ctx context.Context, path string, body
 
 // request performs an HTTP request with retry and circuit breaker.
 func (c *HTTPClient) request(ctx context.Context, method, path string, body any) (*HTTPResponse, error) {
+	// Fail fast until the server accepts requests to the endpoint again
+	if wait := c.RetryAfterFor(path); wait > 0 {
+		return nil, NewError(ErrNetworkRetryAfter, "server asked to retry after "+time.Now().Add(wait).Format(time.RFC3339))
+	}
+
 	// Check circuit breaker
 	if !c.circuitBreaker.Allow() {
 		return nil, NewError(ErrCircuitOpen, "circuit breaker is open")
 	}
 
+	retry := c.retryFor(path)
 	var lastErr error
 
-	for attempt := 1; attempt <= c.retry.MaxAttempts; attempt++ {
+	for attempt := 1; attempt <= retry.MaxAttempts; attempt++ {
+		start := time.Now()
 		resp, err := c.doRequest(ctx, method, path, body)
+		if c.onRequest != nil {
+			statusCode := 0
+			if resp != nil {
+				statusCode = resp.StatusCode
+			}
+			c.onRequest(endpointPath(path), statusCode, time.Since(start))
+		}
 		if err == nil {
 			c.circuitBreaker.RecordSuccess()
 			return resp, nil
@@ -301,18 +384,36 @@ func (c *HTTPClient) request(ctx context.Context, method, path string, body any)
 			return nil, err
 		}
 
+		// Don't retry if the server asks to wait longer than the maximum
+		// delay, and fail fast until then
+		wait, hasWait := retryAfter(resp)
+		if hasWait && wait > retry.MaxDelay {
+			c.setRetryAfter(path, time.Now().Add(wait))
+			if c.logger != nil {
+				c.logger.Debug("Retry-After exceeds maximum retry delay, not retrying",
+					"retry_after", wait,
+					"max_delay", retry.MaxDelay,
+				)
+			}
+			c.circuitBreaker.RecordFailure()
+			return nil, err
+		}
+
 		// Don't retry if we've exhausted attempts
-		if attempt >= c.retry.MaxAttempts {
+		if attempt >= retry.MaxAttempts {
 			break
 		}
 
-		// Calculate backoff
-		delay := CalculateBackoff(attempt, c.retry)
+		// Calculate backoff, waiting as long as the server asks if it says so
+		delay := CalculateBackoff(attempt, retry)
+		if hasWait {
+			delay = wait
+		}
 
 		if c.logger != nil {
 			c.logger.Debug("Retrying request",
 				"attempt", attempt,
-				"max_attempts", c.retry.MaxAttempts,
+				"max_attempts", retry.MaxAttempts,
 				"delay", delay,
 				"error", err.Error(),
 			)
@@ -324,12 +425,86 @@ func (c *HTTPClient) request(ctx context.Context, method, path string, body any)
 			return nil, ctx.Err()
 		case <-time.After(delay):
 		}
+
+		if c.onRetry != nil {
+			c.onRetry(endpointPath(path), attempt+1)
+		}
 	}
 
 	c.circuitBreaker.RecordFailure()
 	return nil, NetworkError(ErrNetworkRetryLimit, "max retries exceeded", lastErr)
 }
 
+// RetryAfterFor returns how long requests to the endpoint of a path fail fast
+// because the server asked to retry later than the maximum retry delay, or
+// zero if requests are allowed.
+func (c *HTTPClient) RetryAfterFor(path string) time.Duration {
+	c.mu.RLock()
+	deadline := c.retryAfter[endpointPath(path)]
+	c.mu.RUnlock()
+	return max(time.Until(deadline), 0)
+}
+
+// setRetryAfter makes requests to the endpoint of a path fail fast until deadline.
+func (c *HTTPClient) setRetryAfter(path string, deadline time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.retryAfter == nil {
+		c.retryAfter = make(map[string]time.Time)
+	}
+	endpoint := endpointPath(path)
+	if deadline.After(c.retryAfter[endpoint]) {
+		c.retryAfter[endpoint] = deadline
+	}
+}
+
+// retryFor returns the retry configuration for a request path.
+func (c *HTTPClient) retryFor(path string) *RetryConfig {
+	if retry, ok := c.pathRetry[endpointPath(path)]; ok && retry != nil {
+		return retry
+	}
+	return c.retry
+}
+
+// endpointPath returns a request path without its query string.
+func endpointPath(path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
+
+// retryAfter returns the delay requested by a rate limited or unavailable
+// response in its Retry-After or RateLimit-Reset header.
+func retryAfter(resp *HTTPResponse) (time.Duration, bool) {
+	if resp == nil || (resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable) {
+		return 0, false
+	}
+
+	value := resp.Headers.Get("Retry-After")
+	if value == "" {
+		value = resp.Headers.Get("RateLimit-Reset")
+	}
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+		if seconds < 0 {
+			seconds = 0
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
 // doRequest performs a single HTTP request.
 func (c *HTTPClient) doRequest(ctx context.Context, method, path string, body any) (*HTTPResponse, error) {
 	url := c.baseURL + path
@@ -440,7 +615,7 @@ func (c *HTTPClient) handleErrorResponse(statusCode int, body []byte) error {
 func (c *HTTPClient) isRetryable(err error) bool {
 	if fkErr, ok := err.(*FlagKitError); ok {
 		switch fkErr.Code {
-		case ErrNetworkError, ErrNetworkTimeout:
+		case ErrNetworkError, ErrNetworkTimeout, ErrNetworkRetryLimit:
 			return true
 		}
 	}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"crypto/tls"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -123,6 +124,56 @@ func TestBaseURLFromFlagKitMode(t *testing.T) {
 	})
 }
 
+func TestBaseURLFromConfig(t *testing.T) {
+	t.Run("uses configured base URL", func(t *testing.T) {
+		t.Setenv("FLAGKIT_MODE", "beta")
+		client := NewHTTPClient(&HTTPClientConfig{
+			BaseURL: "http://localhost:8030/api/v1/",
+			APIKey:  "sdk_test_api_key_12345",
+			Timeout: 5 * time.Second,
+		})
+
+		if client.GetBaseURL() != "http://localhost:8030/api/v1" {
+			t.Errorf("expected configured URL, got %s", client.GetBaseURL())
+		}
+	})
+
+	t.Run("uses FLAGKIT_MODE for the production URL", func(t *testing.T) {
+		t.Setenv("FLAGKIT_MODE", "beta")
+		client := NewHTTPClient(&HTTPClientConfig{
+			BaseURL: defaultBaseURL,
+			APIKey:  "sdk_test_api_key_12345",
+			Timeout: 5 * time.Second,
+		})
+
+		if client.GetBaseURL() != betaBaseURL {
+			t.Errorf("expected %s, got %s", betaBaseURL, client.GetBaseURL())
+		}
+	})
+
+	t.Run("sends requests to configured base URL", func(t *testing.T) {
+		var path string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		client := NewHTTPClient(&HTTPClientConfig{
+			BaseURL: server.URL + "/api/v1",
+			APIKey:  "sdk_test_api_key_12345",
+			Timeout: 5 * time.Second,
+		})
+
+		if _, err := client.Get("/sdk/init"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if path != "/api/v1/sdk/init" {
+			t.Errorf("expected /api/v1/sdk/init, got %s", path)
+		}
+	})
+}
+
 func TestHTTPClientGetKeyID(t *testing.T) {
 	client := NewHTTPClient(&HTTPClientConfig{
 		APIKey:  "sdk_test_api_key_12345",
@@ -358,3 +409,222 @@ func TestHTTPClientGet(t *testing.T) {
 		t.Errorf("expected status 200, got %d", resp.StatusCode)
 	}
 }
+
+func TestHTTPClientTransport(t *testing.T) {
+	t.Run("uses configured client", func(t *testing.T) {
+		httpClient := &http.Client{Timeout: time.Second}
+		client := NewHTTPClient(&HTTPClientConfig{
+			APIKey: "sdk_test_api_key_12345",
+			Client: httpClient,
+		})
+
+		if client.client != httpClient {
+			t.Error("expected configured client to be used")
+		}
+	})
+
+	t.Run("uses configured transport", func(t *testing.T) {
+		transport := &http.Transport{}
+		client := NewHTTPClient(&HTTPClientConfig{
+			APIKey:    "sdk_test_api_key_12345",
+			Timeout:   5 * time.Second,
+			Transport: transport,
+		})
+
+		if client.client.Transport != transport {
+			t.Error("expected configured transport to be used")
+		}
+	})
+
+	t.Run("streaming client has no timeout", func(t *testing.T) {
+		transport := &http.Transport{}
+		client := NewHTTPClient(&HTTPClientConfig{
+			APIKey:    "sdk_test_api_key_12345",
+			Timeout:   5 * time.Second,
+			Transport: transport,
+		})
+
+		streaming := client.StreamingClient()
+		if streaming.Timeout != 0 {
+			t.Errorf("expected no timeout, got %v", streaming.Timeout)
+		}
+		if streaming.Transport != transport {
+			t.Error("expected streaming client to share the transport")
+		}
+		if client.client.Timeout != 5*time.Second {
+			t.Error("expected API client timeout to be unchanged")
+		}
+	})
+}
+
+func TestNewTransport(t *testing.T) {
+	tlsConfig := &tls.Config{ServerName: "api.flagkit.dev"}
+	transport, err := NewTransport("http://proxy.corp.example:3128", tlsConfig)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.flagkit.dev/api/v1/sdk/init", nil)
+	proxy, err := transport.Proxy(req)
+	if err != nil || proxy == nil || proxy.Host != "proxy.corp.example:3128" {
+		t.Errorf("expected proxy.corp.example:3128, got %v", proxy)
+	}
+	if transport.TLSClientConfig == nil || transport.TLSClientConfig.ServerName != "api.flagkit.dev" {
+		t.Error("expected TLS configuration to be used")
+	}
+}
+
+func TestHTTPClientRetryAfter(t *testing.T) {
+	t.Run("retries rate limited requests after the requested delay", func(t *testing.T) {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"success":true}`))
+		}))
+		defer server.Close()
+
+		client := NewHTTPClient(&HTTPClientConfig{
+			BaseURL: server.URL,
+			APIKey:  "sdk_test_api_key_12345",
+			Timeout: 5 * time.Second,
+			Retry:   &RetryConfig{MaxAttempts: 2, BaseDelay: 10 * time.Millisecond, MaxDelay: 5 * time.Second, BackoffMultiplier: 2},
+		})
+
+		start := time.Now()
+		if _, err := client.Get("/test"); err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		if requests != 2 {
+			t.Errorf("expected 2 requests, got %d", requests)
+		}
+		if elapsed := time.Since(start); elapsed < time.Second {
+			t.Errorf("expected retry after at least 1s, got %s", elapsed)
+		}
+	})
+
+	t.Run("does not retry when the requested delay exceeds the maximum delay", func(t *testing.T) {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		client := NewHTTPClient(&HTTPClientConfig{
+			BaseURL: server.URL,
+			APIKey:  "sdk_test_api_key_12345",
+			Timeout: 5 * time.Second,
+			Retry:   &RetryConfig{MaxAttempts: 3, BaseDelay: 10 * time.Millisecond, MaxDelay: time.Second, BackoffMultiplier: 2},
+		})
+
+		_, err := client.Get("/test")
+		fkErr, ok := err.(*FlagKitError)
+		if !ok || fkErr.Code != ErrNetworkRetryLimit {
+			t.Fatalf("expected rate limit error, got %v", err)
+		}
+		if requests != 1 {
+			t.Errorf("expected 1 request, got %d", requests)
+		}
+
+		// Requests to the endpoint fail fast until the requested time has passed
+		_, err = client.Get("/test?key=value")
+		fkErr, ok = err.(*FlagKitError)
+		if !ok || fkErr.Code != ErrNetworkRetryAfter {
+			t.Fatalf("expected retry after error, got %v", err)
+		}
+		if requests != 1 {
+			t.Errorf("expected no request before the Retry-After deadline, got %d", requests)
+		}
+		if wait := client.RetryAfterFor("/test"); wait <= 0 {
+			t.Errorf("expected a pending Retry-After deadline, got %v", wait)
+		}
+		if wait := client.RetryAfterFor("/other"); wait != 0 {
+			t.Errorf("expected no Retry-After deadline for other endpoints, got %v", wait)
+		}
+
+		client.retryAfter["/test"] = time.Now().Add(-time.Second)
+		if _, err := client.Get("/test"); err == nil {
+			t.Fatal("expected rate limit error")
+		}
+		if requests != 2 {
+			t.Errorf("expected a request after the Retry-After deadline, got %d", requests)
+		}
+	})
+}
+
+func TestRetryAfterHeader(t *testing.T) {
+	date := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+
+	tests := []struct {
+		name   string
+		status int
+		header string
+		value  string
+		ok     bool
+	}{
+		{"seconds", http.StatusTooManyRequests, "Retry-After", "3", true},
+		{"http date", http.StatusServiceUnavailable, "Retry-After", date, true},
+		{"rate limit reset", http.StatusTooManyRequests, "RateLimit-Reset", "3", true},
+		{"invalid", http.StatusTooManyRequests, "Retry-After", "soon", false},
+		{"other status", http.StatusInternalServerError, "Retry-After", "3", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &HTTPResponse{StatusCode: tt.status, Headers: http.Header{}}
+			resp.Headers.Set(tt.header, tt.value)
+
+			wait, ok := retryAfter(resp)
+			if ok != tt.ok {
+				t.Fatalf("expected ok=%v, got %v", tt.ok, ok)
+			}
+			if ok && wait <= 0 {
+				t.Errorf("expected positive delay, got %s", wait)
+			}
+		})
+	}
+
+	if _, ok := retryAfter(nil); ok {
+		t.Error("expected no delay without a response")
+	}
+}
+
+func TestHTTPClientPathRetry(t *testing.T) {
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(&HTTPClientConfig{
+		BaseURL: server.URL,
+		APIKey:  "sdk_test_api_key_12345",
+		Timeout: 5 * time.Second,
+		Retry:   &RetryConfig{MaxAttempts: 1},
+		CircuitBreaker: &CircuitBreakerConfig{
+			FailureThreshold: 10,
+			ResetTimeout:     time.Second,
+		},
+		PathRetry: map[string]*RetryConfig{
+			"/sdk/init": {MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, BackoffMultiplier: 1},
+		},
+	})
+
+	_, _ = client.Get("/sdk/init?since=now")
+	_, _ = client.Get("/sdk/updates")
+
+	if requests["/sdk/init"] != 3 {
+		t.Errorf("expected 3 init requests, got %d", requests["/sdk/init"])
+	}
+	if requests["/sdk/updates"] != 1 {
+		t.Errorf("expected 1 updates request, got %d", requests["/sdk/updates"])
+	}
+}
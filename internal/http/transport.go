@@ -0,0 +1,28 @@
+package http
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+)
+
+// NewTransport creates a transport based on http.DefaultTransport that uses
+// the given proxy URL and TLS configuration. An empty proxy URL keeps the
+// proxy from the environment and a nil TLS configuration keeps the default.
+func NewTransport(proxyURL string, tlsConfig *tls.Config) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if proxyURL != "" {
+		proxy, err := url.Parse(proxyURL)
+		if err != nil {
+			return nil, NewErrorWithCause(ErrNetworkError, "invalid proxy URL", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig.Clone()
+	}
+
+	return transport, nil
+}
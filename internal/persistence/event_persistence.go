@@ -5,15 +5,21 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/teracrafts/flagkit-go/internal/core"
+	"github.com/teracrafts/flagkit-go/internal/storage"
 	"github.com/teracrafts/flagkit-go/internal/types"
 )
 
@@ -33,21 +39,62 @@ const (
 	EventStatusSending EventStatus = "sending"
 	// EventStatusSent indicates the event was successfully sent.
 	EventStatusSent EventStatus = "sent"
-	// EventStatusFailed indicates the event failed to send after max retries.
+	// EventStatusFailed indicates sending the event failed and it will be retried.
 	EventStatusFailed EventStatus = "failed"
+	// EventStatusDeadLetter indicates the event was not sent after the maximum
+	// number of attempts and will not be retried.
+	EventStatusDeadLetter EventStatus = "dead_letter"
 )
 
 // PersistedEvent represents an event stored on disk.
 type PersistedEvent struct {
-	ID        string                 `json:"id"`
-	Type      string                 `json:"type"`
+	ID        string         `json:"id"`
+	Type      string         `json:"type"`
 	Data      map[string]any `json:"data,omitempty"`
 	Context   map[string]any `json:"context,omitempty"`
-	Timestamp int64                  `json:"timestamp"`
-	Status    EventStatus            `json:"status"`
-	SentAt    int64                  `json:"sentAt,omitempty"`
+	Timestamp int64          `json:"timestamp"`
+	Status    EventStatus    `json:"status"`
+	SentAt    int64          `json:"sentAt,omitempty"`
+	// Attempts is the number of failed send attempts.
+	Attempts int `json:"attempts,omitempty"`
+	// MetricValue is the numeric value of metric events.
+	MetricValue *float64 `json:"metricValue,omitempty"`
+	// Metadata is added by event processors.
+	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
+// Compression is the compression of rotated event log segments.
+type Compression string
+
+const (
+	// CompressionNone leaves rotated segments uncompressed.
+	CompressionNone Compression = ""
+	// CompressionGzip compresses rotated segments with gzip.
+	CompressionGzip Compression = "gzip"
+	// CompressionZstd compresses rotated segments with Zstandard.
+	CompressionZstd Compression = "zstd"
+)
+
+const (
+	// DefaultMaxSegmentSize is the default size at which the event log is
+	// rotated to a new segment.
+	DefaultMaxSegmentSize int64 = 1 << 20
+
+	// DefaultMaxDiskUsage is the default total size of event log segments.
+	DefaultMaxDiskUsage int64 = 50 << 20
+
+	// DefaultDeadLetterRetention is the default time dead-lettered events
+	// are kept in the event log.
+	DefaultDeadLetterRetention = 7 * 24 * time.Hour
+
+	// DefaultMaxDeadLetters is the default number of dead-lettered events
+	// kept in the event log.
+	DefaultMaxDeadLetters = 1000
+
+	// compactInterval is the interval at which the event log is compacted.
+	compactInterval = time.Hour
+)
+
 // EventPersistence handles crash-resilient event persistence using write-ahead logging.
 type EventPersistence struct {
 	storagePath   string
@@ -55,10 +102,34 @@ type EventPersistence struct {
 	flushInterval time.Duration
 	logger        Logger
 
-	buffer      []PersistedEvent
-	bufferSize  int
-	currentFile string
-	mu          sync.Mutex
+	// Event log segments
+	prefix         string
+	adoptDefault   bool
+	encryption     *storage.EncryptedStorage
+	compression    Compression
+	maxSegmentSize int64
+	maxDiskUsage   int64
+	onDrop         func(count int)
+
+	deadLetterRetention time.Duration
+	maxDeadLetters      int
+
+	// Maintenance state, guarded by maintainMu
+	rotated        []string
+	lastCompaction time.Time
+	maintainMu     sync.Mutex
+
+	buffer       []PersistedEvent
+	bufferSize   int
+	unsent       map[string]struct{}
+
+	// Spill file of this instance, read back from spillOffset
+	spillFile   *os.File
+	spillOffset int64
+	spillSize   int64
+	currentFile  string
+	lastFileTime int64
+	mu           sync.Mutex
 
 	stopCh  chan struct{}
 	running bool
@@ -71,20 +142,67 @@ type EventPersistenceConfig struct {
 	FlushInterval time.Duration
 	BufferSize    int
 	Logger        Logger
+
+	// Namespace separates the event logs of clients sharing a storage path,
+	// such as clients of different API keys. Segments of other namespaces
+	// are never read or removed. It may contain only letters, digits and
+	// underscores.
+	Namespace string
+
+	// AdoptDefaultNamespace moves the segments and spill files of the
+	// default namespace into Namespace on startup, so that events persisted
+	// before a namespace was configured are still sent.
+	AdoptDefaultNamespace bool
+
+	// Encryption encrypts each record with AES-GCM when set.
+	Encryption *storage.EncryptedStorage
+	// Compression compresses rotated segments.
+	Compression Compression
+	// MaxSegmentSize is the size in bytes at which the active segment is rotated.
+	MaxSegmentSize int64
+	// MaxDiskUsage is the total size in bytes of all segments. When it is
+	// exceeded, sent events are compacted away and then the oldest segments
+	// are removed.
+	MaxDiskUsage int64
+	// OnDrop is called with the number of unsent events removed to stay
+	// within MaxDiskUsage.
+	OnDrop func(count int)
+	// DeadLetterRetention is how long dead-lettered events are kept after
+	// they were tracked. They are removed when the event log is compacted.
+	DeadLetterRetention time.Duration
+	// MaxDeadLetters is the number of most recent dead-lettered events kept.
+	MaxDeadLetters int
 }
 
 // DefaultEventPersistenceConfig returns the default event persistence configuration.
 func DefaultEventPersistenceConfig() *EventPersistenceConfig {
 	return &EventPersistenceConfig{
-		StoragePath:   os.TempDir(),
-		MaxEvents:     10000,
-		FlushInterval: time.Second,
-		BufferSize:    100,
+		StoragePath:    os.TempDir(),
+		MaxEvents:      10000,
+		FlushInterval:  time.Second,
+		BufferSize:     100,
+		MaxSegmentSize: DefaultMaxSegmentSize,
+		MaxDiskUsage:   DefaultMaxDiskUsage,
+
+		DeadLetterRetention: DefaultDeadLetterRetention,
+		MaxDeadLetters:      DefaultMaxDeadLetters,
 	}
 }
 
 // NewEventPersistence creates a new event persistence handler.
 func NewEventPersistence(storagePath string, maxEvents int, flushInterval time.Duration, logger Logger) (*EventPersistence, error) {
+	return NewEventPersistenceWithConfig(&EventPersistenceConfig{
+		StoragePath:   storagePath,
+		MaxEvents:     maxEvents,
+		FlushInterval: flushInterval,
+		Logger:        logger,
+	})
+}
+
+// NewEventPersistenceWithConfig creates a new event persistence handler.
+// Unset fields use the defaults.
+func NewEventPersistenceWithConfig(config *EventPersistenceConfig) (*EventPersistence, error) {
+	storagePath := config.StoragePath
 	if storagePath == "" {
 		storagePath = os.TempDir()
 	}
@@ -94,27 +212,85 @@ func NewEventPersistence(storagePath string, maxEvents int, flushInterval time.D
 		return nil, fmt.Errorf("failed to create storage directory: %w", err)
 	}
 
+	switch config.Compression {
+	case CompressionNone, CompressionGzip, CompressionZstd:
+	default:
+		return nil, fmt.Errorf("unsupported compression: %q", config.Compression)
+	}
+
+	prefix := segmentPrefix
+	if config.Namespace != "" {
+		if !validNamespace(config.Namespace) {
+			return nil, fmt.Errorf("invalid namespace: %q", config.Namespace)
+		}
+		prefix += config.Namespace + "-"
+	}
+
+	maxEvents := config.MaxEvents
 	if maxEvents <= 0 {
 		maxEvents = 10000
 	}
 
+	flushInterval := config.FlushInterval
 	if flushInterval <= 0 {
 		flushInterval = time.Second
 	}
 
+	bufferSize := config.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = 100
+	}
+
+	maxSegmentSize := config.MaxSegmentSize
+	if maxSegmentSize <= 0 {
+		maxSegmentSize = DefaultMaxSegmentSize
+	}
+
+	maxDiskUsage := config.MaxDiskUsage
+	if maxDiskUsage <= 0 {
+		maxDiskUsage = DefaultMaxDiskUsage
+	}
+
+	deadLetterRetention := config.DeadLetterRetention
+	if deadLetterRetention <= 0 {
+		deadLetterRetention = DefaultDeadLetterRetention
+	}
+
+	maxDeadLetters := config.MaxDeadLetters
+	if maxDeadLetters <= 0 {
+		maxDeadLetters = DefaultMaxDeadLetters
+	}
+
 	ep := &EventPersistence{
-		storagePath:   storagePath,
-		maxEvents:     maxEvents,
-		flushInterval: flushInterval,
-		logger:        logger,
-		buffer:        make([]PersistedEvent, 0, 100),
-		bufferSize:    100,
-		stopCh:        make(chan struct{}),
+		storagePath:    storagePath,
+		maxEvents:      maxEvents,
+		flushInterval:  flushInterval,
+		logger:         config.Logger,
+		prefix:         prefix,
+		adoptDefault:   config.AdoptDefaultNamespace && prefix != segmentPrefix,
+		encryption:     config.Encryption,
+		compression:    config.Compression,
+		maxSegmentSize: maxSegmentSize,
+		maxDiskUsage:   maxDiskUsage,
+		onDrop:         config.OnDrop,
+		buffer:         make([]PersistedEvent, 0, bufferSize),
+		bufferSize:     bufferSize,
+		unsent:         make(map[string]struct{}),
+		lastCompaction: time.Now(),
+		stopCh:         make(chan struct{}),
+
+		deadLetterRetention: deadLetterRetention,
+		maxDeadLetters:      maxDeadLetters,
 	}
 
 	// Generate current file name
 	ep.currentFile = ep.generateFileName()
 
+	// Count the unsent events left by previous runs
+	if err := ep.load(); err != nil {
+		ep.logWarn("Failed to scan event log", "error", err)
+	}
+
 	return ep, nil
 }
 
@@ -149,7 +325,22 @@ func (ep *EventPersistence) Persist(event PersistedEvent) error {
 	ep.mu.Lock()
 	defer ep.mu.Unlock()
 
-	// Set defaults if not provided
+	ep.setDefaults(&event)
+	ep.buffer = append(ep.buffer, event)
+	if isUnsent(event.Status) {
+		ep.unsent[event.ID] = struct{}{}
+	}
+
+	// Flush if buffer is full
+	if len(ep.buffer) >= ep.bufferSize {
+		return ep.flushLocked()
+	}
+
+	return nil
+}
+
+// setDefaults sets the ID, timestamp and status of an event if not provided.
+func (ep *EventPersistence) setDefaults(event *PersistedEvent) {
 	if event.ID == "" {
 		event.ID = GenerateEventID()
 	}
@@ -159,15 +350,6 @@ func (ep *EventPersistence) Persist(event PersistedEvent) error {
 	if event.Status == "" {
 		event.Status = EventStatusPending
 	}
-
-	ep.buffer = append(ep.buffer, event)
-
-	// Flush if buffer is full
-	if len(ep.buffer) >= ep.bufferSize {
-		return ep.flushLocked()
-	}
-
-	return nil
 }
 
 // Flush writes buffered events to disk with file locking.
@@ -183,48 +365,13 @@ func (ep *EventPersistence) flushLocked() error {
 		return nil
 	}
 
-	lockPath := filepath.Join(ep.storagePath, "flagkit-events.lock")
-
-	// Acquire file lock
-	lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0600)
-	if err != nil {
-		ep.logWarn("Failed to open lock file", "error", err)
-		return fmt.Errorf("failed to open lock file: %w", err)
+	records := make([]any, len(ep.buffer))
+	for i, event := range ep.buffer {
+		records[i] = event
 	}
-	defer ep.closeFile(lockFile)
-
-	if err := syscall.Flock(int(lockFile.Fd()), syscall.LOCK_EX); err != nil {
-		ep.logWarn("Failed to acquire file lock", "error", err)
-		return fmt.Errorf("failed to acquire lock: %w", err)
-	}
-	defer ep.releaseLock(int(lockFile.Fd()))
-
-	// Open or create current log file
-	filePath := filepath.Join(ep.storagePath, ep.currentFile)
-	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
-	if err != nil {
-		ep.logWarn("Failed to open event file", "error", err)
-		return fmt.Errorf("failed to open event file: %w", err)
-	}
-	defer ep.closeFile(file)
-
-	// Write events in JSON Lines format
-	for _, event := range ep.buffer {
-		data, err := json.Marshal(event)
-		if err != nil {
-			ep.logWarn("Failed to marshal event", "error", err, "eventId", event.ID)
-			continue
-		}
-		if _, err := file.Write(append(data, '\n')); err != nil {
-			ep.logWarn("Failed to write event", "error", err, "eventId", event.ID)
-			return fmt.Errorf("failed to write event: %w", err)
-		}
-	}
-
-	// Sync to disk
-	if err := file.Sync(); err != nil {
-		ep.logWarn("Failed to sync file", "error", err)
-		return fmt.Errorf("failed to sync file: %w", err)
+	if err := ep.appendLocked(records); err != nil {
+		ep.logWarn("Failed to write events", "error", err)
+		return err
 	}
 
 	ep.logDebug("Flushed events to disk", "count", len(ep.buffer))
@@ -235,6 +382,33 @@ func (ep *EventPersistence) flushLocked() error {
 
 // MarkSent marks the specified events as sent.
 func (ep *EventPersistence) MarkSent(eventIDs []string) error {
+	if err := ep.markStatus(eventIDs, EventStatusSent); err != nil {
+		return err
+	}
+	ep.logDebug("Marked events as sent", "count", len(eventIDs))
+	return nil
+}
+
+// MarkSending marks the specified events as currently being sent.
+func (ep *EventPersistence) MarkSending(eventIDs []string) error {
+	return ep.markStatus(eventIDs, EventStatusSending)
+}
+
+// MarkFailed marks the specified events as failed to send. Each call counts
+// as a failed attempt.
+func (ep *EventPersistence) MarkFailed(eventIDs []string) error {
+	return ep.markStatus(eventIDs, EventStatusFailed)
+}
+
+// MarkDeadLetter marks the specified events as not sent after the maximum
+// number of attempts. Dead-lettered events are kept but not recovered.
+func (ep *EventPersistence) MarkDeadLetter(eventIDs []string) error {
+	return ep.markStatus(eventIDs, EventStatusDeadLetter)
+}
+
+// markStatus appends status updates for the specified events. Buffered
+// events are written first, so that the updates follow their events.
+func (ep *EventPersistence) markStatus(eventIDs []string, status EventStatus) error {
 	if len(eventIDs) == 0 {
 		return nil
 	}
@@ -242,7 +416,42 @@ func (ep *EventPersistence) MarkSent(eventIDs []string) error {
 	ep.mu.Lock()
 	defer ep.mu.Unlock()
 
-	lockPath := filepath.Join(ep.storagePath, "flagkit-events.lock")
+	if err := ep.flushLocked(); err != nil {
+		return err
+	}
+
+	var sentAt int64
+	if status == EventStatusSent {
+		sentAt = time.Now().UnixMilli()
+	}
+
+	records := make([]any, len(eventIDs))
+	for i, id := range eventIDs {
+		update := map[string]any{
+			"id":     id,
+			"status": status,
+		}
+		if sentAt != 0 {
+			update["sentAt"] = sentAt
+		}
+		records[i] = update
+	}
+
+	if err := ep.appendLocked(records); err != nil {
+		return err
+	}
+	if !isUnsent(status) {
+		for _, id := range eventIDs {
+			delete(ep.unsent, id)
+		}
+	}
+	return nil
+}
+
+// appendLocked appends records to the active segment with file locking
+// (must be called with lock held).
+func (ep *EventPersistence) appendLocked(records []any) error {
+	lockPath := ep.lockPath()
 
 	// Acquire file lock
 	lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0600)
@@ -256,7 +465,13 @@ func (ep *EventPersistence) MarkSent(eventIDs []string) error {
 	}
 	defer ep.releaseLock(int(lockFile.Fd()))
 
-	// Create status update entries
+	return ep.writeRecordsLocked(records)
+}
+
+// writeRecordsLocked appends records to the active segment (must be called
+// with lock and the file lock held).
+func (ep *EventPersistence) writeRecordsLocked(records []any) error {
+	// Open or create current log file
 	filePath := filepath.Join(ep.storagePath, ep.currentFile)
 	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
 	if err != nil {
@@ -264,94 +479,206 @@ func (ep *EventPersistence) MarkSent(eventIDs []string) error {
 	}
 	defer ep.closeFile(file)
 
-	sentAt := time.Now().UnixMilli()
-	for _, id := range eventIDs {
-		update := map[string]any{
-			"id":     id,
-			"status": EventStatusSent,
-			"sentAt": sentAt,
-		}
-		data, err := json.Marshal(update)
+	// Write records in JSON Lines format
+	for _, record := range records {
+		line, err := ep.encodeRecord(record)
 		if err != nil {
+			ep.logWarn("Failed to encode event record", "error", err)
 			continue
 		}
-		if _, err := file.Write(append(data, '\n')); err != nil {
-			ep.logWarn("Failed to write event status update", "error", err, "eventId", id)
+		if _, err := file.Write(append(line, '\n')); err != nil {
+			return fmt.Errorf("failed to write event: %w", err)
 		}
 	}
 
+	// Sync to disk
 	if err := file.Sync(); err != nil {
 		return fmt.Errorf("failed to sync file: %w", err)
 	}
 
-	ep.logDebug("Marked events as sent", "count", len(eventIDs))
 	return nil
 }
 
-// MarkSending marks the specified events as currently being sent.
-func (ep *EventPersistence) MarkSending(eventIDs []string) error {
-	if len(eventIDs) == 0 {
-		return nil
+// encodeRecord marshals a record, encrypting it if encryption is enabled.
+func (ep *EventPersistence) encodeRecord(record any) ([]byte, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	if ep.encryption == nil {
+		return data, nil
+	}
+	encrypted, err := ep.encryption.Encrypt(string(data))
+	if err != nil {
+		return nil, err
 	}
+	return []byte(encrypted), nil
+}
+
+// decodeRecord decrypts an encrypted record. Plaintext records are returned as-is.
+func (ep *EventPersistence) decodeRecord(line []byte) ([]byte, error) {
+	if !storage.IsEncrypted(string(line)) {
+		return line, nil
+	}
+	if ep.encryption == nil {
+		return nil, fmt.Errorf("event record is encrypted but encryption is not enabled")
+	}
+	plaintext, err := ep.encryption.Decrypt(string(line))
+	if err != nil {
+		return nil, err
+	}
+	return []byte(plaintext), nil
+}
+
+// Maintain rotates the active segment once it reaches the maximum segment
+// size, compresses rotated segments, periodically compacts the event log and
+// keeps the segments within the disk quota. It is called by the background
+// flush loop, so that appending events never waits for it.
+func (ep *EventPersistence) Maintain() error {
+	ep.maintainMu.Lock()
+	defer ep.maintainMu.Unlock()
+
+	ep.rotate()
+	ep.compressRotated()
+	if time.Since(ep.lastCompaction) >= compactInterval {
+		if err := ep.cleanup(); err != nil {
+			ep.logWarn("Failed to compact event log", "error", err)
+		}
+	}
+	return ep.enforceQuota()
+}
 
+// rotate starts a new active segment if the active segment has reached the
+// maximum segment size (must be called with maintainMu held).
+func (ep *EventPersistence) rotate() {
 	ep.mu.Lock()
 	defer ep.mu.Unlock()
 
-	lockPath := filepath.Join(ep.storagePath, "flagkit-events.lock")
-
-	// Acquire file lock
-	lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0600)
-	if err != nil {
-		return fmt.Errorf("failed to open lock file: %w", err)
+	previous := filepath.Join(ep.storagePath, ep.currentFile)
+	info, err := os.Stat(previous)
+	if err != nil || info.Size() < ep.maxSegmentSize {
+		return
 	}
-	defer ep.closeFile(lockFile)
+	ep.currentFile = ep.generateFileName()
+	ep.rotated = append(ep.rotated, previous)
 
-	if err := syscall.Flock(int(lockFile.Fd()), syscall.LOCK_EX); err != nil {
-		return fmt.Errorf("failed to acquire lock: %w", err)
-	}
-	defer ep.releaseLock(int(lockFile.Fd()))
+	ep.logDebug("Rotated event log segment", "segment", filepath.Base(previous))
+}
 
-	filePath := filepath.Join(ep.storagePath, ep.currentFile)
-	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
-	if err != nil {
-		return fmt.Errorf("failed to open event file: %w", err)
+// compressRotated compresses the rotated segments if compression is enabled.
+// Segments are compressed into temporary files without the file lock, which
+// is held only to swap the files (must be called with maintainMu held).
+func (ep *EventPersistence) compressRotated() {
+	rotated := ep.rotated
+	ep.rotated = nil
+	if ep.compression == CompressionNone {
+		return
 	}
-	defer ep.closeFile(file)
 
-	for _, id := range eventIDs {
-		update := map[string]any{
-			"id":     id,
-			"status": EventStatusSending,
-		}
-		data, err := json.Marshal(update)
-		if err != nil {
+	for _, path := range rotated {
+		target := strings.TrimSuffix(path, segmentExtension) + segmentExtensions[ep.compression]
+		temp := target + ".tmp"
+		err := compressSegment(path, temp, ep.compression)
+		if errors.Is(err, fs.ErrNotExist) {
+			// Compacted away since it was rotated
 			continue
 		}
-		if _, err := file.Write(append(data, '\n')); err != nil {
-			ep.logWarn("Failed to write event status update", "error", err, "eventId", id)
+		if err == nil {
+			err = ep.withFileLock(func() error {
+				if _, err := os.Stat(path); err != nil {
+					return os.Remove(temp)
+				}
+				if err := os.Rename(temp, target); err != nil {
+					_ = os.Remove(temp)
+					return err
+				}
+				return os.Remove(path)
+			})
+		}
+		if err != nil {
+			ep.logWarn("Failed to compress event log segment", "file", path, "error", err)
 		}
 	}
+}
 
-	if err := file.Sync(); err != nil {
-		return fmt.Errorf("failed to sync file: %w", err)
+// enforceQuota keeps the total size of the segments within the disk quota,
+// first by compacting away sent events and then by removing the oldest
+// segments. Unsent events in removed segments are reported to OnDrop (must
+// be called with maintainMu held).
+func (ep *EventPersistence) enforceQuota() error {
+	files, err := ep.segmentFiles()
+	if err != nil || segmentsSize(files) <= ep.maxDiskUsage {
+		return err
 	}
 
-	return nil
-}
+	var removed []string
+	err = ep.withFileLock(func() error {
+		files, err := ep.segmentFiles()
+		if err != nil {
+			return fmt.Errorf("failed to find event files: %w", err)
+		}
+		segments, err := ep.compactLocked(files)
+		ep.rotated = append(ep.rotated, segments...)
+		if err != nil {
+			ep.logWarn("Failed to compact event log", "error", err)
+		}
 
-// MarkFailed marks the specified events as failed to send.
-func (ep *EventPersistence) MarkFailed(eventIDs []string) error {
-	if len(eventIDs) == 0 {
+		files, err = ep.segmentFiles()
+		if err != nil {
+			return fmt.Errorf("failed to find event files: %w", err)
+		}
+		size := segmentsSize(files)
+		if size <= ep.maxDiskUsage {
+			return nil
+		}
+
+		eventMap := make(map[string]PersistedEvent)
+		for _, filePath := range files {
+			_ = ep.readEventsFromFile(filePath, eventMap)
+		}
+		for len(files) > 1 && size > ep.maxDiskUsage {
+			segmentEvents := make(map[string]PersistedEvent)
+			_ = ep.readEventsFromFile(files[0], segmentEvents)
+			info, err := os.Stat(files[0])
+			if err == nil {
+				size -= info.Size()
+			}
+			if err := os.Remove(files[0]); err != nil {
+				return fmt.Errorf("failed to remove event log segment: %w", err)
+			}
+			for id := range segmentEvents {
+				if isUnsent(eventMap[id].Status) {
+					removed = append(removed, id)
+				}
+			}
+			ep.logWarn("Event log over disk quota, removed oldest segment", "file", filepath.Base(files[0]))
+			files = files[1:]
+		}
 		return nil
-	}
+	})
 
+	if len(removed) == 0 {
+		return err
+	}
 	ep.mu.Lock()
-	defer ep.mu.Unlock()
+	for _, id := range removed {
+		delete(ep.unsent, id)
+	}
+	ep.mu.Unlock()
+	if ep.onDrop != nil {
+		ep.onDrop(len(removed))
+	}
+	return err
+}
 
-	lockPath := filepath.Join(ep.storagePath, "flagkit-events.lock")
+// withFileLock runs fn with the exclusive file lock of the event log held.
+func (ep *EventPersistence) withFileLock(fn func() error) error {
+	return ep.withLock(ep.lockPath(), fn)
+}
 
-	// Acquire file lock
-	lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0600)
+// withLock runs fn with the exclusive lock of the lock file at path held.
+func (ep *EventPersistence) withLock(path string, fn func() error) error {
+	lockFile, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
 	if err != nil {
 		return fmt.Errorf("failed to open lock file: %w", err)
 	}
@@ -362,40 +689,88 @@ func (ep *EventPersistence) MarkFailed(eventIDs []string) error {
 	}
 	defer ep.releaseLock(int(lockFile.Fd()))
 
-	filePath := filepath.Join(ep.storagePath, ep.currentFile)
-	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
-	if err != nil {
-		return fmt.Errorf("failed to open event file: %w", err)
-	}
-	defer ep.closeFile(file)
+	return fn()
+}
 
-	for _, id := range eventIDs {
-		update := map[string]any{
-			"id":     id,
-			"status": EventStatusFailed,
-		}
-		data, err := json.Marshal(update)
+// lockPath returns the path of the lock file of the event log.
+func (ep *EventPersistence) lockPath() string {
+	return filepath.Join(ep.storagePath, strings.TrimSuffix(ep.prefix, "-")+".lock")
+}
+
+// adoptDefaultNamespaceLocked moves the segments and the spill files of
+// stopped instances of the default namespace into the namespace of the event
+// log (must be called with the file lock held).
+func (ep *EventPersistence) adoptDefaultNamespaceLocked() {
+	lockPath := filepath.Join(ep.storagePath, strings.TrimSuffix(segmentPrefix, "-")+".lock")
+	adopted := 0
+	err := ep.withLock(lockPath, func() error {
+		matches, err := filepath.Glob(filepath.Join(ep.storagePath, segmentPrefix+"*"))
 		if err != nil {
-			continue
+			return err
 		}
-		if _, err := file.Write(append(data, '\n')); err != nil {
-			ep.logWarn("Failed to write event status update", "error", err, "eventId", id)
+
+		for _, path := range matches {
+			name := strings.TrimPrefix(filepath.Base(path), segmentPrefix)
+			if isSpillName(name) {
+				if !spillFileStopped(path) {
+					continue
+				}
+			} else if !isSegmentName(name) {
+				continue
+			}
+			if err := os.Rename(path, filepath.Join(ep.storagePath, ep.prefix+name)); err != nil {
+				ep.logWarn("Failed to adopt event file", "file", path, "error", err)
+				continue
+			}
+			adopted++
 		}
+		return nil
+	})
+	if err != nil {
+		ep.logWarn("Failed to adopt event files of the default namespace", "error", err)
+	}
+	if adopted > 0 {
+		ep.logInfo("Adopted event files of the default namespace", "count", adopted)
 	}
+}
 
-	if err := file.Sync(); err != nil {
-		return fmt.Errorf("failed to sync file: %w", err)
+// segmentFiles returns the event log segments of the namespace, oldest first.
+func (ep *EventPersistence) segmentFiles() ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(ep.storagePath, ep.prefix+"*"))
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	var files []string
+	for _, match := range matches {
+		if isSegmentName(strings.TrimPrefix(filepath.Base(match), ep.prefix)) {
+			files = append(files, match)
+		}
+	}
+	// Segment names start with their creation time
+	sort.Strings(files)
+	return files, nil
 }
 
-// Recover recovers pending events from disk on startup.
+// segmentsSize returns the total size of the segment files.
+func segmentsSize(files []string) int64 {
+	var size int64
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			size += info.Size()
+		}
+	}
+	return size
+}
+
+// Recover recovers unsent events from disk on startup. Pending events and
+// events that were being sent are returned as pending; events that failed to
+// send are returned as failed. Both keep their attempt count.
 func (ep *EventPersistence) Recover() ([]PersistedEvent, error) {
 	ep.mu.Lock()
 	defer ep.mu.Unlock()
 
-	lockPath := filepath.Join(ep.storagePath, "flagkit-events.lock")
+	lockPath := ep.lockPath()
 
 	// Acquire file lock
 	lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0600)
@@ -410,8 +785,7 @@ func (ep *EventPersistence) Recover() ([]PersistedEvent, error) {
 	defer ep.releaseLock(int(lockFile.Fd()))
 
 	// Find all event files
-	pattern := filepath.Join(ep.storagePath, "flagkit-events-*.jsonl")
-	files, err := filepath.Glob(pattern)
+	files, err := ep.segmentFiles()
 	if err != nil {
 		return nil, fmt.Errorf("failed to find event files: %w", err)
 	}
@@ -427,51 +801,117 @@ func (ep *EventPersistence) Recover() ([]PersistedEvent, error) {
 		}
 	}
 
-	// Collect pending and sending events (sending = crashed mid-send)
+	// Collect pending, sending (sending = crashed mid-send) and failed events
 	var pendingEvents []PersistedEvent
 	for _, event := range eventMap {
-		if event.Status == EventStatusPending || event.Status == EventStatusSending {
+		switch event.Status {
+		case EventStatusPending, EventStatusSending:
 			// Reset sending events to pending
 			event.Status = EventStatusPending
 			pendingEvents = append(pendingEvents, event)
+		case EventStatusFailed:
+			pendingEvents = append(pendingEvents, event)
+		default:
+			continue
 		}
+		ep.unsent[event.ID] = struct{}{}
 	}
 
 	ep.logInfo("Recovered pending events", "count", len(pendingEvents))
 	return pendingEvents, nil
 }
 
+// Backlog returns the number of events that have not been sent, including
+// buffered events that are not yet written to disk. It is kept up to date as
+// events are persisted and marked, without reading the event log.
+func (ep *EventPersistence) Backlog() (int, error) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	return len(ep.unsent), nil
+}
+
+// load reads the event log to find the unsent events, first moving the
+// events of spill files left by stopped instances to the event log.
+func (ep *EventPersistence) load() error {
+	return ep.withFileLock(func() error {
+		if ep.adoptDefault {
+			ep.adoptDefaultNamespaceLocked()
+		}
+
+		files, err := ep.segmentFiles()
+		if err != nil {
+			return fmt.Errorf("failed to find event files: %w", err)
+		}
+
+		eventMap := make(map[string]PersistedEvent)
+		for _, filePath := range files {
+			if err := ep.readEventsFromFile(filePath, eventMap); err != nil {
+				ep.logWarn("Failed to read event file", "file", filePath, "error", err)
+			}
+		}
+		if err := ep.adoptSpillsLocked(eventMap); err != nil {
+			return fmt.Errorf("failed to adopt spilled events: %w", err)
+		}
+
+		for id, event := range eventMap {
+			if isUnsent(event.Status) {
+				ep.unsent[id] = struct{}{}
+			}
+		}
+		return nil
+	})
+}
+
+// isUnsent reports whether an event with the given status is still to be sent.
+func isUnsent(status EventStatus) bool {
+	return status == EventStatusPending || status == EventStatusSending || status == EventStatusFailed
+}
+
 // readEventsFromFile reads events from a single file into the event map.
+// Returns an error if the file or any of its records could not be read, in
+// which case the readable events are still added.
 func (ep *EventPersistence) readEventsFromFile(filePath string, eventMap map[string]PersistedEvent) error {
-	file, err := os.Open(filePath)
+	reader, err := openSegment(filePath)
 	if err != nil {
 		return err
 	}
-	defer ep.closeFile(file)
+	defer func() {
+		if err := reader.Close(); err != nil {
+			ep.logWarn("Failed to close file", "error", err)
+		}
+	}()
 
-	scanner := bufio.NewScanner(file)
+	skipped := 0
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRecordSize)
 	for scanner.Scan() {
 		line := scanner.Bytes()
 		if len(line) == 0 {
 			continue
 		}
 
+		line, err := ep.decodeRecord(line)
+		if err != nil {
+			ep.logWarn("Failed to decode event record", "file", filepath.Base(filePath), "error", err)
+			skipped++
+			continue
+		}
+
 		var event PersistedEvent
 		if err := json.Unmarshal(line, &event); err != nil {
-			// Try parsing as status update
-			var update struct {
-				ID     string      `json:"id"`
-				Status EventStatus `json:"status"`
-				SentAt int64       `json:"sentAt,omitempty"`
-			}
-			if err := json.Unmarshal(line, &update); err != nil {
-				continue
-			}
-			// Update existing event status
-			if existing, ok := eventMap[update.ID]; ok {
-				existing.Status = update.Status
-				existing.SentAt = update.SentAt
-				eventMap[update.ID] = existing
+			skipped++
+			continue
+		}
+
+		// Status update entries carry only the ID and status
+		if event.Type == "" {
+			if existing, ok := eventMap[event.ID]; ok {
+				existing.Status = event.Status
+				existing.SentAt = event.SentAt
+				if event.Status == EventStatusFailed {
+					existing.Attempts++
+				}
+				eventMap[event.ID] = existing
 			}
 			continue
 		}
@@ -482,87 +922,157 @@ func (ep *EventPersistence) readEventsFromFile(filePath string, eventMap map[str
 		}
 	}
 
-	return scanner.Err()
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if skipped > 0 {
+		return fmt.Errorf("%d unreadable records", skipped)
+	}
+	return nil
 }
 
-// Cleanup removes old sent/failed events and compacts event files.
+// Cleanup removes sent events and compacts event files. Failed events are
+// kept with their attempt counts, and dead-lettered events until they expire.
 func (ep *EventPersistence) Cleanup() error {
-	ep.mu.Lock()
-	defer ep.mu.Unlock()
-
-	lockPath := filepath.Join(ep.storagePath, "flagkit-events.lock")
-
-	// Acquire file lock
-	lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0600)
-	if err != nil {
-		return fmt.Errorf("failed to open lock file: %w", err)
-	}
-	defer ep.closeFile(lockFile)
+	ep.maintainMu.Lock()
+	defer ep.maintainMu.Unlock()
+	return ep.cleanup()
+}
 
-	if err := syscall.Flock(int(lockFile.Fd()), syscall.LOCK_EX); err != nil {
-		return fmt.Errorf("failed to acquire lock: %w", err)
-	}
-	defer ep.releaseLock(int(lockFile.Fd()))
+// cleanup compacts all event files (must be called with maintainMu held).
+func (ep *EventPersistence) cleanup() error {
+	ep.lastCompaction = time.Now()
+	return ep.withFileLock(func() error {
+		// Find all event files
+		files, err := ep.segmentFiles()
+		if err != nil {
+			return fmt.Errorf("failed to find event files: %w", err)
+		}
 
-	// Find all event files
-	pattern := filepath.Join(ep.storagePath, "flagkit-events-*.jsonl")
-	files, err := filepath.Glob(pattern)
-	if err != nil {
-		return fmt.Errorf("failed to find event files: %w", err)
-	}
+		segments, err := ep.compactLocked(files)
+		ep.rotated = append(ep.rotated, segments...)
+		return err
+	})
+}
 
+// compactLocked rewrites the segments into new segments without sent events,
+// returning the new segments (must be called with the file lock held). The
+// new segments sort before the compacted ones, so that status updates
+// appended later to the active segment still follow their events. Segments
+// that cannot be fully read, such as segments encrypted with another key, are
+// left untouched together with their events.
+func (ep *EventPersistence) compactLocked(files []string) ([]string, error) {
 	// Collect all events and their final states
 	eventMap := make(map[string]PersistedEvent)
+	var readable, unreadable []string
 	for _, filePath := range files {
 		if err := ep.readEventsFromFile(filePath, eventMap); err != nil {
+			ep.logWarn("Failed to read event file, not compacting it", "file", filePath, "error", err)
+			unreadable = append(unreadable, filePath)
 			continue
 		}
+		readable = append(readable, filePath)
+	}
+	for _, filePath := range unreadable {
+		kept := make(map[string]PersistedEvent)
+		_ = ep.readEventsFromFile(filePath, kept)
+		for id := range kept {
+			delete(eventMap, id)
+		}
+	}
+	if len(readable) == 0 {
+		return nil, nil
 	}
 
-	// Filter out sent and failed events, keep only pending
-	var pendingEvents []PersistedEvent
+	// Filter out sent and expired dead-lettered events
+	var pendingEvents, deadLetters []PersistedEvent
+	expiry := time.Now().Add(-ep.deadLetterRetention).UnixMilli()
 	for _, event := range eventMap {
-		if event.Status == EventStatusPending || event.Status == EventStatusSending {
+		switch {
+		case isUnsent(event.Status):
 			pendingEvents = append(pendingEvents, event)
+		case event.Status == EventStatusDeadLetter && event.Timestamp >= expiry:
+			deadLetters = append(deadLetters, event)
 		}
 	}
+	pending := len(pendingEvents)
+
+	// Keep the most recent dead-lettered events
+	if len(deadLetters) > ep.maxDeadLetters {
+		sort.Slice(deadLetters, func(i, j int) bool {
+			return deadLetters[i].Timestamp > deadLetters[j].Timestamp
+		})
+		deadLetters = deadLetters[:ep.maxDeadLetters]
+	}
+	pendingEvents = append(pendingEvents, deadLetters...)
+
+	// Write pending events to new segments, oldest first
+	sort.Slice(pendingEvents, func(i, j int) bool {
+		return pendingEvents[i].Timestamp < pendingEvents[j].Timestamp
+	})
+	timestamp := ep.segmentTime(readable[0]) - 1
+	var segments []string
+	for len(pendingEvents) > 0 {
+		name := fmt.Sprintf("%s%d-%s%s", ep.prefix, timestamp, generateRandomString(8), segmentExtension)
+		segment := filepath.Join(ep.storagePath, name)
+		written, err := ep.writeSegment(segment, pendingEvents)
+		if err != nil {
+			return segments, err
+		}
+		segments = append(segments, segment)
+		pendingEvents = pendingEvents[written:]
+	}
 
 	// Remove old files
-	for _, filePath := range files {
+	for _, filePath := range readable {
 		if err := os.Remove(filePath); err != nil {
 			ep.logWarn("Failed to remove old event file", "file", filePath, "error", err)
 		}
 	}
 
-	// Generate new file name
-	ep.currentFile = ep.generateFileName()
+	ep.logInfo("Cleaned up event files", "pendingCount", pending, "removedFiles", len(readable))
+	return segments, nil
+}
+
+// segmentTime returns the creation time in the name of a segment.
+func (ep *EventPersistence) segmentTime(path string) int64 {
+	timestamp, _, _ := strings.Cut(strings.TrimPrefix(filepath.Base(path), ep.prefix), "-")
+	t, _ := strconv.ParseInt(timestamp, 10, 64)
+	return t
+}
+
+// writeSegment writes events to a new segment until it reaches the maximum
+// segment size, returning the number of events written (must be called with
+// the file lock held).
+func (ep *EventPersistence) writeSegment(filePath string, events []PersistedEvent) (int, error) {
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create new event file: %w", err)
+	}
+	defer ep.closeFile(file)
 
-	// Write pending events to new file
-	if len(pendingEvents) > 0 {
-		filePath := filepath.Join(ep.storagePath, ep.currentFile)
-		file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY, 0600)
+	var size int64
+	written := 0
+	for _, event := range events {
+		written++
+		data, err := ep.encodeRecord(event)
 		if err != nil {
-			return fmt.Errorf("failed to create new event file: %w", err)
+			continue
 		}
-		defer ep.closeFile(file)
-
-		for _, event := range pendingEvents {
-			data, err := json.Marshal(event)
-			if err != nil {
-				continue
-			}
-			if _, err := file.Write(append(data, '\n')); err != nil {
-				ep.logWarn("Failed to write event during cleanup", "error", err, "eventId", event.ID)
-			}
+		n, err := file.Write(append(data, '\n'))
+		if err != nil {
+			ep.logWarn("Failed to write event during cleanup", "error", err, "eventId", event.ID)
 		}
-
-		if err := file.Sync(); err != nil {
-			return fmt.Errorf("failed to sync file: %w", err)
+		size += int64(n)
+		if size >= ep.maxSegmentSize {
+			break
 		}
 	}
 
-	ep.logInfo("Cleaned up event files", "pendingCount", len(pendingEvents), "removedFiles", len(files))
-	return nil
+	if err := file.Sync(); err != nil {
+		return written, fmt.Errorf("failed to sync file: %w", err)
+	}
+	return written, nil
 }
 
 // Close flushes remaining events and cleans up resources.
@@ -574,6 +1084,10 @@ func (ep *EventPersistence) Close() error {
 		ep.logWarn("Failed to flush on close", "error", err)
 	}
 
+	ep.mu.Lock()
+	ep.closeSpillLocked()
+	ep.mu.Unlock()
+
 	return nil
 }
 
@@ -602,15 +1116,23 @@ func (ep *EventPersistence) run() {
 			if err := ep.Flush(); err != nil {
 				ep.logWarn("Background flush failed", "error", err)
 			}
+			if err := ep.Maintain(); err != nil {
+				ep.logWarn("Event log maintenance failed", "error", err)
+			}
 		}
 	}
 }
 
-// generateFileName generates a unique file name for the event log.
+// generateFileName generates a unique file name for the event log. Names
+// sort in creation order.
 func (ep *EventPersistence) generateFileName() string {
 	timestamp := time.Now().UnixMilli()
+	if timestamp <= ep.lastFileTime {
+		timestamp = ep.lastFileTime + 1
+	}
+	ep.lastFileTime = timestamp
 	random := generateRandomString(8)
-	return fmt.Sprintf("flagkit-events-%d-%s.jsonl", timestamp, random)
+	return fmt.Sprintf("%s%d-%s%s", ep.prefix, timestamp, random, segmentExtension)
 }
 
 // GenerateEventID generates a unique event ID.
@@ -674,15 +1196,21 @@ func NewEventPersisterAdapter(ep *EventPersistence) *EventPersisterAdapter {
 
 // Persist persists an event using the internal PersistedEvent type.
 func (a *EventPersisterAdapter) Persist(event core.PersistedEvent) error {
-	return a.ep.Persist(PersistedEvent{
-		ID:        event.ID,
-		Type:      event.Type,
-		Data:      event.Data,
-		Context:   event.Context,
-		Timestamp: event.Timestamp,
-		Status:    EventStatus(event.Status),
-		SentAt:    event.SentAt,
-	})
+	return a.ep.Persist(fromCoreEvent(event))
+}
+
+// Spill spills an event that does not fit in the event queue.
+func (a *EventPersisterAdapter) Spill(event core.PersistedEvent) error {
+	return a.ep.Spill(fromCoreEvent(event))
+}
+
+// Unspill reads back up to max spilled events.
+func (a *EventPersisterAdapter) Unspill(max int) ([]core.PersistedEvent, error) {
+	events, err := a.ep.Unspill(max)
+	if err != nil {
+		return nil, err
+	}
+	return toCoreEvents(events), nil
 }
 
 // MarkSending marks events as sending.
@@ -700,29 +1228,57 @@ func (a *EventPersisterAdapter) MarkFailed(eventIDs []string) error {
 	return a.ep.MarkFailed(eventIDs)
 }
 
-// Recover recovers pending events.
+// MarkDeadLetter marks events as dead-lettered.
+func (a *EventPersisterAdapter) MarkDeadLetter(eventIDs []string) error {
+	return a.ep.MarkDeadLetter(eventIDs)
+}
+
+// Recover recovers pending and failed events.
 func (a *EventPersisterAdapter) Recover() ([]core.PersistedEvent, error) {
 	events, err := a.ep.Recover()
 	if err != nil {
 		return nil, err
 	}
+	return toCoreEvents(events), nil
+}
+
+// Flush flushes the persistence buffer.
+func (a *EventPersisterAdapter) Flush() error {
+	return a.ep.Flush()
+}
+
+// fromCoreEvent converts an internal PersistedEvent.
+func fromCoreEvent(event core.PersistedEvent) PersistedEvent {
+	return PersistedEvent{
+		ID:          event.ID,
+		Type:        event.Type,
+		Data:        event.Data,
+		Context:     event.Context,
+		Timestamp:   event.Timestamp,
+		Status:      EventStatus(event.Status),
+		SentAt:      event.SentAt,
+		Attempts:    event.Attempts,
+		MetricValue: event.MetricValue,
+		Metadata:    event.Metadata,
+	}
+}
 
+// toCoreEvents converts events to the internal PersistedEvent type.
+func toCoreEvents(events []PersistedEvent) []core.PersistedEvent {
 	result := make([]core.PersistedEvent, len(events))
 	for i, e := range events {
 		result[i] = core.PersistedEvent{
-			ID:        e.ID,
-			Type:      e.Type,
-			Data:      e.Data,
-			Context:   e.Context,
-			Timestamp: e.Timestamp,
-			Status:    string(e.Status),
-			SentAt:    e.SentAt,
+			ID:          e.ID,
+			Type:        e.Type,
+			Data:        e.Data,
+			Context:     e.Context,
+			Timestamp:   e.Timestamp,
+			Status:      string(e.Status),
+			SentAt:      e.SentAt,
+			Attempts:    e.Attempts,
+			MetricValue: e.MetricValue,
+			Metadata:    e.Metadata,
 		}
 	}
-	return result, nil
-}
-
-// Flush flushes the persistence buffer.
-func (a *EventPersisterAdapter) Flush() error {
-	return a.ep.Flush()
+	return result
 }
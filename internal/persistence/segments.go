@@ -0,0 +1,168 @@
+package persistence
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// segmentPrefix is the prefix of event log segment names.
+	segmentPrefix = "flagkit-events-"
+
+	// segmentExtension is the extension of uncompressed event log segments.
+	segmentExtension = ".jsonl"
+
+	// maxRecordSize is the maximum size of an event log record.
+	maxRecordSize = 1 << 20
+)
+
+// segmentExtensions maps compressions to the extensions of compressed segments.
+var segmentExtensions = map[Compression]string{
+	CompressionGzip: segmentExtension + ".gz",
+	CompressionZstd: segmentExtension + ".zst",
+}
+
+// segmentCompression returns the compression of a segment file from its name.
+func segmentCompression(path string) Compression {
+	for compression, ext := range segmentExtensions {
+		if strings.HasSuffix(path, ext) {
+			return compression
+		}
+	}
+	return CompressionNone
+}
+
+// isSegmentName reports whether name, without the segment prefix and
+// namespace, is a segment name of the form <timestamp>-<random>.jsonl with an
+// optional compression extension. Segments of namespaces other than the
+// default one do not match when the prefix has no namespace.
+func isSegmentName(name string) bool {
+	for _, ext := range segmentExtensions {
+		if strings.HasSuffix(name, ext) {
+			name = strings.TrimSuffix(name, ext) + segmentExtension
+		}
+	}
+	if !strings.HasSuffix(name, segmentExtension) {
+		return false
+	}
+	timestamp, random, ok := strings.Cut(strings.TrimSuffix(name, segmentExtension), "-")
+	return ok && timestamp != "" && strings.Trim(timestamp, "0123456789") == "" &&
+		random != "" && !strings.Contains(random, "-")
+}
+
+// validNamespace reports whether a namespace contains only letters, digits
+// and underscores.
+func validNamespace(namespace string) bool {
+	for _, r := range namespace {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// compressSegment writes a compressed copy of a segment file to target.
+func compressSegment(path, target string, compression Compression) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+
+	dst, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	if err := writeCompressed(dst, src, compression); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(target)
+		return err
+	}
+	if err := dst.Sync(); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(target)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		_ = os.Remove(target)
+		return err
+	}
+
+	return nil
+}
+
+// writeCompressed compresses src into dst.
+func writeCompressed(dst io.Writer, src io.Reader, compression Compression) error {
+	var w io.WriteCloser
+	switch compression {
+	case CompressionGzip:
+		w = gzip.NewWriter(dst)
+	case CompressionZstd:
+		zw, err := zstd.NewWriter(dst)
+		if err != nil {
+			return err
+		}
+		w = zw
+	default:
+		return fmt.Errorf("unsupported compression: %q", compression)
+	}
+
+	if _, err := io.Copy(w, src); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// segmentReader reads a possibly compressed segment file.
+type segmentReader struct {
+	io.Reader
+	closers []func() error
+}
+
+// Close closes the decompressor and the file.
+func (r *segmentReader) Close() error {
+	var err error
+	for _, closeFn := range r.closers {
+		if closeErr := closeFn(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// openSegment opens a segment file for reading, decompressing it as needed.
+func openSegment(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch segmentCompression(path) {
+	case CompressionGzip:
+		gr, err := gzip.NewReader(file)
+		if err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+		return &segmentReader{Reader: gr, closers: []func() error{gr.Close, file.Close}}, nil
+	case CompressionZstd:
+		zr, err := zstd.NewReader(file)
+		if err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+		return &segmentReader{Reader: zr, closers: []func() error{
+			func() error { zr.Close(); return nil },
+			file.Close,
+		}}, nil
+	default:
+		return file, nil
+	}
+}
@@ -0,0 +1,212 @@
+package persistence
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// spillInfix follows the segment prefix and namespace in spill file names.
+const spillInfix = "spill-"
+
+// isSpillName reports whether name, without the segment prefix and
+// namespace, is a spill file name of the form spill-<random>.jsonl.
+func isSpillName(name string) bool {
+	random, ok := strings.CutPrefix(name, spillInfix)
+	if !ok {
+		return false
+	}
+	random, ok = strings.CutSuffix(random, segmentExtension)
+	return ok && random != "" && !strings.Contains(random, "-")
+}
+
+// Spill writes an event that does not fit in the event queue to the spill
+// file of this instance, to be read back with Unspill. Spilled events are
+// kept apart from the event log, so that reading them back does not read the
+// event log. Returns an error once the spill file reaches the disk quota.
+func (ep *EventPersistence) Spill(event PersistedEvent) error {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
+	if ep.spillSize >= ep.maxDiskUsage {
+		return fmt.Errorf("spill file over disk quota")
+	}
+	if ep.spillFile == nil {
+		if err := ep.openSpillLocked(); err != nil {
+			return err
+		}
+	}
+
+	ep.setDefaults(&event)
+	line, err := ep.encodeRecord(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	n, err := ep.spillFile.Write(append(line, '\n'))
+	ep.spillSize += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write event: %w", err)
+	}
+	if err := ep.spillFile.Sync(); err != nil {
+		return fmt.Errorf("failed to sync file: %w", err)
+	}
+
+	ep.unsent[event.ID] = struct{}{}
+	return nil
+}
+
+// Unspill reads back up to max spilled events, oldest first, and moves them
+// to the event log. Reading continues from where the previous call stopped.
+// Returns fewer events only when no more spilled events can be read.
+func (ep *EventPersistence) Unspill(max int) ([]PersistedEvent, error) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
+	if ep.spillFile == nil || max <= 0 {
+		return nil, nil
+	}
+
+	offset := ep.spillOffset
+	reader := bufio.NewReader(io.NewSectionReader(ep.spillFile, offset, ep.spillSize-offset))
+	var events []PersistedEvent
+	for len(events) < max {
+		line, err := reader.ReadBytes('\n')
+		offset += int64(len(line))
+		if len(line) == 0 && err != nil {
+			break
+		}
+
+		record, err := ep.decodeRecord(bytes.TrimSuffix(line, []byte("\n")))
+		if err != nil {
+			ep.logWarn("Failed to decode spilled event", "error", err)
+			continue
+		}
+		var event PersistedEvent
+		if err := json.Unmarshal(record, &event); err != nil {
+			ep.logWarn("Failed to decode spilled event", "error", err)
+			continue
+		}
+		events = append(events, event)
+	}
+
+	if len(events) > 0 {
+		records := make([]any, len(events))
+		for i, event := range events {
+			records[i] = event
+		}
+		if err := ep.appendLocked(records); err != nil {
+			return nil, err
+		}
+	}
+
+	ep.spillOffset = offset
+	if ep.spillOffset >= ep.spillSize {
+		// Everything was read back
+		if err := ep.spillFile.Truncate(0); err != nil {
+			ep.logWarn("Failed to truncate spill file", "error", err)
+		} else {
+			ep.spillOffset, ep.spillSize = 0, 0
+		}
+	}
+	return events, nil
+}
+
+// openSpillLocked creates the spill file of this instance (must be called
+// with lock held). The file stays locked while it is open, so that other
+// instances only adopt it once this one has stopped.
+func (ep *EventPersistence) openSpillLocked() error {
+	name := ep.prefix + spillInfix + generateRandomString(8) + segmentExtension
+	file, err := os.OpenFile(filepath.Join(ep.storagePath, name), os.O_CREATE|os.O_EXCL|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create spill file: %w", err)
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		ep.closeFile(file)
+		return fmt.Errorf("failed to lock spill file: %w", err)
+	}
+	ep.spillFile = file
+	ep.spillOffset, ep.spillSize = 0, 0
+	return nil
+}
+
+// closeSpillLocked closes the spill file, removing it if every spilled event
+// was read back (must be called with lock held). Events left in it are
+// adopted by the next instance.
+func (ep *EventPersistence) closeSpillLocked() {
+	if ep.spillFile == nil {
+		return
+	}
+	if ep.spillOffset >= ep.spillSize {
+		if err := os.Remove(ep.spillFile.Name()); err != nil {
+			ep.logWarn("Failed to remove spill file", "error", err)
+		}
+	}
+	ep.closeFile(ep.spillFile)
+	ep.spillFile = nil
+}
+
+// spillFileStopped reports whether the spill file at path was left by an
+// instance that has stopped. Spill files of running instances are locked.
+func spillFileStopped(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer func() { _ = file.Close() }()
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB) == nil
+}
+
+// adoptSpillsLocked moves the events of spill files left by instances that
+// have stopped to the event log, skipping events already in it (must be
+// called with the file lock held).
+func (ep *EventPersistence) adoptSpillsLocked(eventMap map[string]PersistedEvent) error {
+	matches, err := filepath.Glob(filepath.Join(ep.storagePath, ep.prefix+spillInfix+"*"))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range matches {
+		if !isSpillName(strings.TrimPrefix(filepath.Base(path), ep.prefix)) {
+			continue
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		// Spill files of running instances are locked
+		if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+			ep.closeFile(file)
+			continue
+		}
+
+		spilled := make(map[string]PersistedEvent)
+		if err := ep.readEventsFromFile(path, spilled); err != nil {
+			ep.logWarn("Failed to read spill file", "file", path, "error", err)
+		}
+		var records []any
+		for id, event := range spilled {
+			if _, ok := eventMap[id]; !ok {
+				eventMap[id] = event
+				records = append(records, event)
+			}
+		}
+		if len(records) > 0 {
+			if err := ep.writeRecordsLocked(records); err != nil {
+				ep.closeFile(file)
+				return err
+			}
+		}
+		if err := os.Remove(path); err != nil {
+			ep.logWarn("Failed to remove spill file", "file", path, "error", err)
+		}
+		ep.closeFile(file)
+		ep.logInfo("Adopted spilled events", "file", filepath.Base(path), "count", len(records))
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileSnapshotStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "flags.json")
+	store := NewFileSnapshotStore(path)
+
+	t.Run("returns nil when no snapshot exists", func(t *testing.T) {
+		data, err := store.Load()
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+		if data != nil {
+			t.Errorf("expected nil data, got %q", data)
+		}
+	})
+
+	t.Run("saves and loads a snapshot", func(t *testing.T) {
+		if err := store.Save([]byte(`{"flags":[]}`)); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := store.Save([]byte(`{"flags":[1]}`)); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		data, err := store.Load()
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+		if string(data) != `{"flags":[1]}` {
+			t.Errorf("expected latest snapshot, got %q", data)
+		}
+	})
+
+	t.Run("leaves no temporary files", func(t *testing.T) {
+		entries, err := os.ReadDir(filepath.Dir(path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 {
+			t.Errorf("expected only the snapshot file, got %d entries", len(entries))
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package types
+
+// ContextKind describes an additional entity of a multi-kind context.
+// This mirrors the public ContextKind to avoid import cycles.
+type ContextKind struct {
+	Kind              string         `json:"kind"`
+	Key               string         `json:"key"`
+	Attributes        map[string]any `json:"attributes,omitempty"`
+	PrivateAttributes []string       `json:"privateAttributes,omitempty"`
+}
+
+// strip returns a copy of the kind with private attributes removed.
+// The key is never private.
+func (k *ContextKind) strip() *ContextKind {
+	stripped := &ContextKind{
+		Kind:       k.Kind,
+		Key:        k.Key,
+		Attributes: make(map[string]any),
+	}
+
+	privateSet := make(map[string]bool)
+	for _, attr := range k.PrivateAttributes {
+		privateSet[attr] = true
+	}
+	for key, v := range k.Attributes {
+		if !privateSet[key] {
+			stripped.Attributes[key] = v
+		}
+	}
+	return stripped
+}
+
+// toMap converts the kind to a map for serialization.
+func (k *ContextKind) toMap() map[string]any {
+	m := map[string]any{"key": k.Key}
+	if len(k.Attributes) > 0 {
+		m["attributes"] = k.Attributes
+	}
+	return m
+}
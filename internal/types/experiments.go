@@ -0,0 +1,38 @@
+package types
+
+// ExperimentStatusRunning is the status of an experiment that is assigning contexts.
+const ExperimentStatusRunning = "running"
+
+// ExperimentVariant is a named arm of an experiment.
+type ExperimentVariant struct {
+	Key     string `json:"key"`
+	Payload any    `json:"payload,omitempty"`
+	// Weight is the variant's share of enrolled contexts relative to the sum
+	// of all variant weights. If all weights are zero, variants are split evenly.
+	Weight int `json:"weight"`
+}
+
+// ExperimentDefinition is the definition of an A/B test used for local assignment.
+type ExperimentDefinition struct {
+	Key     string `json:"key"`
+	Version int    `json:"version"`
+	Status  string `json:"status"`
+	Salt    string `json:"salt,omitempty"`
+	// BucketBy is the context attribute used for bucketing. Default: "userId".
+	BucketBy string `json:"bucketBy,omitempty"`
+	// TrafficAllocation is the share of eligible contexts enrolled in the
+	// experiment in RolloutWeightScale units. Nil enrolls everyone and zero
+	// enrolls nobody.
+	TrafficAllocation *int `json:"trafficAllocation,omitempty"`
+	// Audience restricts enrollment to contexts matching all clauses.
+	Audience []Clause `json:"audience,omitempty"`
+	// Variants are the experiment arms. The first variant is the control
+	// and is served to contexts that are not enrolled.
+	Variants []ExperimentVariant `json:"variants"`
+}
+
+// ExperimentsResponse represents the response from the experiments endpoint.
+type ExperimentsResponse struct {
+	Experiments []ExperimentDefinition `json:"experiments"`
+	ServerTime  string                 `json:"serverTime"`
+}
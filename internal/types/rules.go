@@ -0,0 +1,112 @@
+package types
+
+// EvaluationReason represents the reason for an evaluation result.
+// This mirrors the public EvaluationReason to avoid import cycles.
+type EvaluationReason string
+
+const (
+	ReasonFallthrough        EvaluationReason = "FALLTHROUGH"
+	ReasonTargeted           EvaluationReason = "TARGETED"
+	ReasonDisabled           EvaluationReason = "DISABLED"
+	ReasonError              EvaluationReason = "ERROR"
+	ReasonPrerequisiteFailed EvaluationReason = "PREREQUISITE_FAILED"
+)
+
+// Operator is a comparison operator used by targeting rule clauses.
+type Operator string
+
+const (
+	// OpIn matches when the attribute equals any of the clause values.
+	OpIn Operator = "in"
+	// OpStartsWith matches when the attribute starts with any of the clause values.
+	OpStartsWith Operator = "startsWith"
+	// OpEndsWith matches when the attribute ends with any of the clause values.
+	OpEndsWith Operator = "endsWith"
+	// OpContains matches when the attribute contains any of the clause values.
+	OpContains Operator = "contains"
+	// OpMatches matches when the attribute matches any of the clause regular expressions.
+	OpMatches Operator = "matches"
+	// OpLessThan matches when the numeric attribute is less than any clause value.
+	OpLessThan Operator = "lessThan"
+	// OpLessThanOrEqual matches when the numeric attribute is at most any clause value.
+	OpLessThanOrEqual Operator = "lessThanOrEqual"
+	// OpGreaterThan matches when the numeric attribute is greater than any clause value.
+	OpGreaterThan Operator = "greaterThan"
+	// OpGreaterThanOrEqual matches when the numeric attribute is at least any clause value.
+	OpGreaterThanOrEqual Operator = "greaterThanOrEqual"
+	// OpSemVerEqual matches when the attribute is the same semantic version as any clause value.
+	OpSemVerEqual Operator = "semVerEqual"
+	// OpSemVerLessThan matches when the attribute is an older semantic version than any clause value.
+	OpSemVerLessThan Operator = "semVerLessThan"
+	// OpSemVerGreaterThan matches when the attribute is a newer semantic version than any clause value.
+	OpSemVerGreaterThan Operator = "semVerGreaterThan"
+	// OpSegmentMatch matches when the context is a member of any of the clause segment keys.
+	// The clause attribute is ignored.
+	OpSegmentMatch Operator = "segmentMatch"
+)
+
+// Clause is a single condition of a targeting rule.
+type Clause struct {
+	Attribute string   `json:"attribute"`
+	Operator  Operator `json:"op"`
+	Values    []any    `json:"values"`
+	Negate    bool     `json:"negate,omitempty"`
+}
+
+// RolloutWeightScale is the total weight of a fully allocated rollout.
+// Weights are expressed in thousandths of a percent (100000 = 100%).
+const RolloutWeightScale = 100000
+
+// WeightedVariation is a variation with its share of a rollout.
+type WeightedVariation struct {
+	Variation int `json:"variation"`
+	Weight    int `json:"weight"`
+}
+
+// Rollout distributes contexts across variations by consistent hashing.
+type Rollout struct {
+	Variations []WeightedVariation `json:"variations"`
+	// BucketBy is the context attribute used for bucketing. Default: "userId".
+	BucketBy string `json:"bucketBy,omitempty"`
+}
+
+// VariationOrRollout selects the variation served by a rule or fallthrough.
+// Exactly one of Variation or Rollout is expected to be set.
+type VariationOrRollout struct {
+	Variation *int     `json:"variation,omitempty"`
+	Rollout   *Rollout `json:"rollout,omitempty"`
+}
+
+// TargetingRule serves a variation when all of its clauses match.
+type TargetingRule struct {
+	ID      string   `json:"id,omitempty"`
+	Clauses []Clause `json:"clauses"`
+	VariationOrRollout
+}
+
+// Prerequisite requires another flag to serve a specific variation.
+type Prerequisite struct {
+	Key       string `json:"key"`
+	Variation int    `json:"variation"`
+}
+
+// FlagDefinition is the full rule definition of a flag used for local evaluation.
+type FlagDefinition struct {
+	Key           string             `json:"key"`
+	FlagType      FlagType           `json:"flagType"`
+	Version       int                `json:"version"`
+	Enabled       bool               `json:"enabled"`
+	Variations    []any              `json:"variations"`
+	OffVariation  *int               `json:"offVariation,omitempty"`
+	Prerequisites []Prerequisite     `json:"prerequisites,omitempty"`
+	Fallthrough   VariationOrRollout `json:"fallthrough"`
+	Rules         []TargetingRule    `json:"rules,omitempty"`
+	Salt          string             `json:"salt,omitempty"`
+	LastModified  string             `json:"lastModified,omitempty"`
+}
+
+// RulesResponse represents the response from the rules endpoint.
+type RulesResponse struct {
+	Flags      []FlagDefinition `json:"flags"`
+	ServerTime string           `json:"serverTime"`
+}
@@ -0,0 +1,25 @@
+package types
+
+// SegmentRule adds contexts to a segment when all of its clauses match.
+type SegmentRule struct {
+	ID      string   `json:"id,omitempty"`
+	Clauses []Clause `json:"clauses"`
+}
+
+// SegmentDefinition is a reusable audience definition.
+type SegmentDefinition struct {
+	Key     string `json:"key"`
+	Version int    `json:"version"`
+	// Included lists context keys that are always members of the segment.
+	Included []string `json:"included,omitempty"`
+	// Excluded lists context keys that are never members of the segment
+	// unless they are also included.
+	Excluded []string      `json:"excluded,omitempty"`
+	Rules    []SegmentRule `json:"rules,omitempty"`
+}
+
+// SegmentsResponse represents the response from the segments endpoint.
+type SegmentsResponse struct {
+	Segments   []SegmentDefinition `json:"segments"`
+	ServerTime string              `json:"serverTime"`
+}
@@ -31,6 +31,32 @@ type FlagState struct {
 	LastModified string      `json:"lastModified"`
 }
 
+// EvaluatedFlag is a flag value evaluated by the server for a specific context.
+type EvaluatedFlag struct {
+	FlagState
+	Reason EvaluationReason `json:"reason,omitempty"`
+}
+
+// EvaluateRequest is the request body of the evaluate endpoint.
+type EvaluateRequest struct {
+	Context map[string]any `json:"context"`
+}
+
+// EvaluateResponse represents the response from the evaluate endpoint.
+type EvaluateResponse struct {
+	Flags []EvaluatedFlag `json:"flags"`
+}
+
+// FlagSnapshot is a persisted flag set used for warm starts.
+// KeyHash identifies the API key the snapshot was fetched with, so that a
+// snapshot is never loaded by a client of another environment.
+type FlagSnapshot struct {
+	EnvironmentID string      `json:"environmentId"`
+	KeyHash       string      `json:"keyHash"`
+	ServerTime    string      `json:"serverTime"`
+	Flags         []FlagState `json:"flags"`
+}
+
 // ErrorCode represents a FlagKit error code.
 type ErrorCode string
 
@@ -43,6 +69,7 @@ const (
 	ErrNetworkError      ErrorCode = "NETWORK_ERROR"
 	ErrNetworkTimeout    ErrorCode = "NETWORK_TIMEOUT"
 	ErrNetworkRetryLimit ErrorCode = "NETWORK_RETRY_LIMIT"
+	ErrNetworkRetryAfter ErrorCode = "NETWORK_RETRY_AFTER"
 
 	// Authentication errors
 	ErrAuthUnauthorized ErrorCode = "AUTH_UNAUTHORIZED"
@@ -116,7 +143,7 @@ func SecurityError(code ErrorCode, message string) *FlagKitError {
 // isRecoverableCode determines if an error code represents a recoverable error.
 func isRecoverableCode(code ErrorCode) bool {
 	switch code {
-	case ErrNetworkError, ErrNetworkTimeout, ErrNetworkRetryLimit, ErrCircuitOpen:
+	case ErrNetworkError, ErrNetworkTimeout, ErrNetworkRetryLimit, ErrNetworkRetryAfter, ErrCircuitOpen:
 		return true
 	default:
 		return false
@@ -136,6 +163,7 @@ type EvaluationContext struct {
 	Browser           string                 `json:"browser,omitempty"`
 	Custom            map[string]any `json:"custom,omitempty"`
 	PrivateAttributes []string               `json:"privateAttributes,omitempty"`
+	Kinds             map[string]*ContextKind `json:"kinds,omitempty"`
 }
 
 // StripPrivateAttributes returns a copy of the context with private attributes removed.
@@ -176,6 +204,13 @@ func (c *EvaluationContext) StripPrivateAttributes() *EvaluationContext {
 		}
 	}
 
+	for name, kind := range c.Kinds {
+		if stripped.Kinds == nil {
+			stripped.Kinds = make(map[string]*ContextKind, len(c.Kinds))
+		}
+		stripped.Kinds[name] = kind.strip()
+	}
+
 	return stripped
 }
 
@@ -198,9 +233,25 @@ func (c *EvaluationContext) ToMap() map[string]any {
 	if c.Country != "" {
 		m["country"] = c.Country
 	}
+	if c.DeviceType != "" {
+		m["deviceType"] = c.DeviceType
+	}
+	if c.OS != "" {
+		m["os"] = c.OS
+	}
+	if c.Browser != "" {
+		m["browser"] = c.Browser
+	}
 	if len(c.Custom) > 0 {
 		m["custom"] = c.Custom
 	}
+	if len(c.Kinds) > 0 {
+		kinds := make(map[string]any, len(c.Kinds))
+		for name, kind := range c.Kinds {
+			kinds[name] = kind.toMap()
+		}
+		m["kinds"] = kinds
+	}
 
 	return m
 }
@@ -0,0 +1,68 @@
+// Package metrics provides helpers for implementations of the Metrics
+// interface.
+//
+// OpenTelemetry and Prometheus implementations are in the metrics/otel and
+// metrics/prometheus modules, so that the SDK does not depend on either. Pass
+// one to a client with flagkit.WithMetrics. A single instance can be shared by
+// several clients; their event gauges are summed.
+package metrics
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/teracrafts/flagkit-go/types"
+)
+
+// Observers holds the event gauge functions registered by clients with
+// Metrics.ObserveEvents. The zero value is ready to use.
+type Observers struct {
+	nextID    uint64
+	observers map[uint64]func() types.EventStats
+	mu        sync.Mutex
+}
+
+// Add registers a gauge function and returns a function that removes it.
+func (o *Observers) Add(observe func() types.EventStats) func() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.observers == nil {
+		o.observers = make(map[uint64]func() types.EventStats)
+	}
+	o.nextID++
+	id := o.nextID
+	o.observers[id] = observe
+
+	return func() {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		delete(o.observers, id)
+	}
+}
+
+// Stats returns the sum of the event gauges of all registered clients.
+func (o *Observers) Stats() types.EventStats {
+	o.mu.Lock()
+	observe := make([]func() types.EventStats, 0, len(o.observers))
+	for _, f := range o.observers {
+		observe = append(observe, f)
+	}
+	o.mu.Unlock()
+
+	var total types.EventStats
+	for _, f := range observe {
+		stats := f()
+		total.QueueDepth += stats.QueueDepth
+		total.PersistedEvents += stats.PersistedEvents
+	}
+	return total
+}
+
+// StatusLabel returns the label value of a response status code, "error"
+// for requests that failed without a response.
+func StatusLabel(statusCode int) string {
+	if statusCode == 0 {
+		return "error"
+	}
+	return strconv.Itoa(statusCode)
+}
@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/teracrafts/flagkit-go/types"
+)
+
+func TestObservers(t *testing.T) {
+	var o Observers
+	removeFirst := o.Add(func() types.EventStats {
+		return types.EventStats{QueueDepth: 4, PersistedEvents: 2}
+	})
+	removeSecond := o.Add(func() types.EventStats {
+		return types.EventStats{QueueDepth: 1}
+	})
+	assert.Equal(t, types.EventStats{QueueDepth: 5, PersistedEvents: 2}, o.Stats())
+
+	removeFirst()
+	assert.Equal(t, types.EventStats{QueueDepth: 1}, o.Stats())
+	removeSecond()
+	assert.Equal(t, types.EventStats{}, o.Stats())
+}
+
+func TestStatusLabel(t *testing.T) {
+	assert.Equal(t, "200", StatusLabel(200))
+	assert.Equal(t, "error", StatusLabel(0))
+}
@@ -0,0 +1,148 @@
+// Package otel provides a FlagKit Metrics implementation that records client
+// measurements to an OpenTelemetry meter.
+//
+// It is a separate module, so that only applications using it depend on the
+// OpenTelemetry API.
+package otel
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/teracrafts/flagkit-go/metrics"
+	"github.com/teracrafts/flagkit-go/types"
+)
+
+// Metrics is a flagkit.Metrics implementation that records to an
+// OpenTelemetry meter:
+//
+//	m, err := flagkitotel.New(otel.Meter("flagkit"))
+//	client, err := flagkit.NewClient(apiKey, flagkit.WithMetrics(m))
+type Metrics struct {
+	evaluations        metric.Int64Counter
+	evaluationDuration metric.Float64Histogram
+	cacheLookups       metric.Int64Counter
+	requestDuration    metric.Float64Histogram
+	retries            metric.Int64Counter
+	circuitChanges     metric.Int64Counter
+	eventsDropped      metric.Int64Counter
+	queueDepth         metric.Int64ObservableGauge
+	persistedEvents    metric.Int64ObservableGauge
+	observers          metrics.Observers
+}
+
+// New creates the instruments of the client metrics with the
+// given meter.
+func New(meter metric.Meter) (*Metrics, error) {
+	o := &Metrics{}
+
+	var err error
+	if o.evaluations, err = meter.Int64Counter("flagkit.evaluations",
+		metric.WithDescription("Flag evaluations by flag and reason.")); err != nil {
+		return nil, err
+	}
+	if o.evaluationDuration, err = meter.Float64Histogram("flagkit.evaluation.duration",
+		metric.WithDescription("Flag evaluation latency, including evaluation jitter."),
+		metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+	if o.cacheLookups, err = meter.Int64Counter("flagkit.cache.lookups",
+		metric.WithDescription("Flag cache lookups by result (hit, stale or miss).")); err != nil {
+		return nil, err
+	}
+	if o.requestDuration, err = meter.Float64Histogram("flagkit.http.request.duration",
+		metric.WithDescription("API request latency by endpoint and status code."),
+		metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+	if o.retries, err = meter.Int64Counter("flagkit.http.retries",
+		metric.WithDescription("Retried API requests by endpoint.")); err != nil {
+		return nil, err
+	}
+	if o.circuitChanges, err = meter.Int64Counter("flagkit.circuit_breaker.transitions",
+		metric.WithDescription("Circuit breaker state changes by circuit and states.")); err != nil {
+		return nil, err
+	}
+	if o.eventsDropped, err = meter.Int64Counter("flagkit.events.dropped",
+		metric.WithDescription("Analytics events dropped without being sent, by reason.")); err != nil {
+		return nil, err
+	}
+	if o.queueDepth, err = meter.Int64ObservableGauge("flagkit.events.queue_depth",
+		metric.WithDescription("Analytics events waiting to be sent.")); err != nil {
+		return nil, err
+	}
+	if o.persistedEvents, err = meter.Int64ObservableGauge("flagkit.events.persisted",
+		metric.WithDescription("Persisted analytics events that have not been sent.")); err != nil {
+		return nil, err
+	}
+
+	if _, err := meter.RegisterCallback(o.observe, o.queueDepth, o.persistedEvents); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// RecordEvaluation records a flag evaluation and its latency.
+func (o *Metrics) RecordEvaluation(flagKey string, reason types.EvaluationReason, duration time.Duration) {
+	ctx := context.Background()
+	o.evaluations.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("flag", flagKey),
+		attribute.String("reason", string(reason)),
+	))
+	o.evaluationDuration.Record(ctx, duration.Seconds())
+}
+
+// RecordCacheLookup records a flag cache lookup.
+func (o *Metrics) RecordCacheLookup(result types.CacheResult) {
+	o.cacheLookups.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("result", string(result)),
+	))
+}
+
+// RecordRequest records an API request attempt.
+func (o *Metrics) RecordRequest(endpoint string, statusCode int, duration time.Duration) {
+	o.requestDuration.Record(context.Background(), duration.Seconds(), metric.WithAttributes(
+		attribute.String("endpoint", endpoint),
+		attribute.String("status", metrics.StatusLabel(statusCode)),
+	))
+}
+
+// RecordRetry records a retried API request.
+func (o *Metrics) RecordRetry(endpoint string) {
+	o.retries.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("endpoint", endpoint),
+	))
+}
+
+// RecordCircuitStateChange records a circuit breaker state change.
+func (o *Metrics) RecordCircuitStateChange(name, from, to string) {
+	o.circuitChanges.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("circuit", name),
+		attribute.String("from", from),
+		attribute.String("to", to),
+	))
+}
+
+// RecordEventsDropped records dropped analytics events.
+func (o *Metrics) RecordEventsDropped(reason string, count int) {
+	o.eventsDropped.Add(context.Background(), int64(count), metric.WithAttributes(
+		attribute.String("reason", reason),
+	))
+}
+
+// ObserveEvents registers a function that returns the event gauges of a
+// client, called on every collection.
+func (o *Metrics) ObserveEvents(observe func() types.EventStats) func() {
+	return o.observers.Add(observe)
+}
+
+// observe reports the event gauges.
+func (o *Metrics) observe(_ context.Context, observer metric.Observer) error {
+	stats := o.observers.Stats()
+	observer.ObserveInt64(o.queueDepth, int64(stats.QueueDepth))
+	observer.ObserveInt64(o.persistedEvents, int64(stats.PersistedEvents))
+	return nil
+}
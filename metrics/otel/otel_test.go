@@ -0,0 +1,78 @@
+package otel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/teracrafts/flagkit-go/types"
+)
+
+// record sends one measurement of every kind to m.
+func record(m types.Metrics) func() {
+	m.RecordEvaluation("checkout", types.ReasonCached, time.Millisecond)
+	m.RecordEvaluation("checkout", types.ReasonCached, time.Millisecond)
+	m.RecordCacheLookup(types.CacheHit)
+	m.RecordRequest("/sdk/init", 200, 50*time.Millisecond)
+	m.RecordRequest("/sdk/init", 0, time.Second)
+	m.RecordRetry("/sdk/init")
+	m.RecordCircuitStateChange("api", "CLOSED", "OPEN")
+	m.RecordEventsDropped("queue_full", 3)
+	return m.ObserveEvents(func() types.EventStats {
+		return types.EventStats{QueueDepth: 4, PersistedEvents: 2}
+	})
+}
+
+func TestOpenTelemetry(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer func() { _ = provider.Shutdown(context.Background()) }()
+
+	m, err := New(provider.Meter("flagkit"))
+	require.NoError(t, err)
+
+	record(m)
+
+	var data metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &data))
+	require.Len(t, data.ScopeMetrics, 1)
+
+	sums := map[string]int64{}
+	gauges := map[string]int64{}
+	histograms := map[string]uint64{}
+	for _, m := range data.ScopeMetrics[0].Metrics {
+		switch d := m.Data.(type) {
+		case metricdata.Sum[int64]:
+			for _, point := range d.DataPoints {
+				sums[m.Name] += point.Value
+			}
+		case metricdata.Gauge[int64]:
+			gauges[m.Name] = d.DataPoints[0].Value
+		case metricdata.Histogram[float64]:
+			for _, point := range d.DataPoints {
+				histograms[m.Name] += point.Count
+			}
+		}
+	}
+
+	assert.Equal(t, map[string]int64{
+		"flagkit.evaluations":                 2,
+		"flagkit.cache.lookups":               1,
+		"flagkit.http.retries":                1,
+		"flagkit.circuit_breaker.transitions": 1,
+		"flagkit.events.dropped":              3,
+	}, sums)
+	assert.Equal(t, map[string]int64{
+		"flagkit.events.queue_depth": 4,
+		"flagkit.events.persisted":   2,
+	}, gauges)
+	assert.Equal(t, map[string]uint64{
+		"flagkit.evaluation.duration":   2,
+		"flagkit.http.request.duration": 2,
+	}, histograms)
+}
@@ -0,0 +1,146 @@
+// Package prometheus provides a FlagKit Metrics implementation that exports
+// client measurements to Prometheus.
+//
+// It is a separate module, so that only applications using it depend on the
+// Prometheus client library.
+package prometheus
+
+import (
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+
+	"github.com/teracrafts/flagkit-go/metrics"
+	"github.com/teracrafts/flagkit-go/types"
+)
+
+// Metrics is a flagkit.Metrics implementation that is also a
+// prometheus.Collector. Register it with a Prometheus registry:
+//
+//	m := flagkitprom.New()
+//	prometheus.MustRegister(m)
+//	client, err := flagkit.NewClient(apiKey, flagkit.WithMetrics(m))
+type Metrics struct {
+	evaluations        *prom.CounterVec
+	evaluationDuration prom.Histogram
+	cacheLookups       *prom.CounterVec
+	requestDuration    *prom.HistogramVec
+	retries            *prom.CounterVec
+	circuitChanges     *prom.CounterVec
+	eventsDropped      *prom.CounterVec
+	queueDepth         *prom.Desc
+	persistedEvents    *prom.Desc
+	observers          metrics.Observers
+}
+
+// New creates a Prometheus collector with metrics in the flagkit namespace.
+func New() *Metrics {
+	return &Metrics{
+		evaluations: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: "flagkit",
+			Name:      "evaluations_total",
+			Help:      "Flag evaluations by flag and reason.",
+		}, []string{"flag", "reason"}),
+		evaluationDuration: prom.NewHistogram(prom.HistogramOpts{
+			Namespace: "flagkit",
+			Name:      "evaluation_duration_seconds",
+			Help:      "Flag evaluation latency, including evaluation jitter.",
+			Buckets:   []float64{.00001, .00005, .0001, .0005, .001, .005, .01, .05, .1},
+		}),
+		cacheLookups: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: "flagkit",
+			Name:      "cache_lookups_total",
+			Help:      "Flag cache lookups by result (hit, stale or miss).",
+		}, []string{"result"}),
+		requestDuration: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace: "flagkit",
+			Name:      "http_request_duration_seconds",
+			Help:      "API request latency by endpoint and status code.",
+			Buckets:   prom.DefBuckets,
+		}, []string{"endpoint", "status"}),
+		retries: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: "flagkit",
+			Name:      "http_retries_total",
+			Help:      "Retried API requests by endpoint.",
+		}, []string{"endpoint"}),
+		circuitChanges: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: "flagkit",
+			Name:      "circuit_breaker_transitions_total",
+			Help:      "Circuit breaker state changes by circuit and states.",
+		}, []string{"circuit", "from", "to"}),
+		eventsDropped: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: "flagkit",
+			Name:      "events_dropped_total",
+			Help:      "Analytics events dropped without being sent, by reason.",
+		}, []string{"reason"}),
+		queueDepth: prom.NewDesc("flagkit_event_queue_depth",
+			"Analytics events waiting to be sent.", nil, nil),
+		persistedEvents: prom.NewDesc("flagkit_persisted_events",
+			"Persisted analytics events that have not been sent.", nil, nil),
+	}
+}
+
+// RecordEvaluation records a flag evaluation and its latency.
+func (p *Metrics) RecordEvaluation(flagKey string, reason types.EvaluationReason, duration time.Duration) {
+	p.evaluations.WithLabelValues(flagKey, string(reason)).Inc()
+	p.evaluationDuration.Observe(duration.Seconds())
+}
+
+// RecordCacheLookup records a flag cache lookup.
+func (p *Metrics) RecordCacheLookup(result types.CacheResult) {
+	p.cacheLookups.WithLabelValues(string(result)).Inc()
+}
+
+// RecordRequest records an API request attempt.
+func (p *Metrics) RecordRequest(endpoint string, statusCode int, duration time.Duration) {
+	p.requestDuration.WithLabelValues(endpoint, metrics.StatusLabel(statusCode)).Observe(duration.Seconds())
+}
+
+// RecordRetry records a retried API request.
+func (p *Metrics) RecordRetry(endpoint string) {
+	p.retries.WithLabelValues(endpoint).Inc()
+}
+
+// RecordCircuitStateChange records a circuit breaker state change.
+func (p *Metrics) RecordCircuitStateChange(name, from, to string) {
+	p.circuitChanges.WithLabelValues(name, from, to).Inc()
+}
+
+// RecordEventsDropped records dropped analytics events.
+func (p *Metrics) RecordEventsDropped(reason string, count int) {
+	p.eventsDropped.WithLabelValues(reason).Add(float64(count))
+}
+
+// ObserveEvents registers a function that returns the event gauges of a
+// client, called on every scrape.
+func (p *Metrics) ObserveEvents(observe func() types.EventStats) func() {
+	return p.observers.Add(observe)
+}
+
+// Describe implements prometheus.Collector.
+func (p *Metrics) Describe(ch chan<- *prom.Desc) {
+	p.evaluations.Describe(ch)
+	p.evaluationDuration.Describe(ch)
+	p.cacheLookups.Describe(ch)
+	p.requestDuration.Describe(ch)
+	p.retries.Describe(ch)
+	p.circuitChanges.Describe(ch)
+	p.eventsDropped.Describe(ch)
+	ch <- p.queueDepth
+	ch <- p.persistedEvents
+}
+
+// Collect implements prometheus.Collector.
+func (p *Metrics) Collect(ch chan<- prom.Metric) {
+	p.evaluations.Collect(ch)
+	p.evaluationDuration.Collect(ch)
+	p.cacheLookups.Collect(ch)
+	p.requestDuration.Collect(ch)
+	p.retries.Collect(ch)
+	p.circuitChanges.Collect(ch)
+	p.eventsDropped.Collect(ch)
+
+	stats := p.observers.Stats()
+	ch <- prom.MustNewConstMetric(p.queueDepth, prom.GaugeValue, float64(stats.QueueDepth))
+	ch <- prom.MustNewConstMetric(p.persistedEvents, prom.GaugeValue, float64(stats.PersistedEvents))
+}
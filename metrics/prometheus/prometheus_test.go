@@ -0,0 +1,59 @@
+package prometheus
+
+import (
+	"testing"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/teracrafts/flagkit-go/types"
+)
+
+// record sends one measurement of every kind to m.
+func record(m types.Metrics) func() {
+	m.RecordEvaluation("checkout", types.ReasonCached, time.Millisecond)
+	m.RecordEvaluation("checkout", types.ReasonCached, time.Millisecond)
+	m.RecordCacheLookup(types.CacheHit)
+	m.RecordRequest("/sdk/init", 200, 50*time.Millisecond)
+	m.RecordRequest("/sdk/init", 0, time.Second)
+	m.RecordRetry("/sdk/init")
+	m.RecordCircuitStateChange("api", "CLOSED", "OPEN")
+	m.RecordEventsDropped("queue_full", 3)
+	return m.ObserveEvents(func() types.EventStats {
+		return types.EventStats{QueueDepth: 4, PersistedEvents: 2}
+	})
+}
+
+func TestPrometheus(t *testing.T) {
+	m := New()
+	registry := prom.NewPedanticRegistry()
+	require.NoError(t, registry.Register(m))
+
+	unregister := record(m)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.evaluations.WithLabelValues("checkout", "CACHED")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.cacheLookups.WithLabelValues("hit")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.retries.WithLabelValues("/sdk/init")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.circuitChanges.WithLabelValues("api", "CLOSED", "OPEN")))
+	assert.Equal(t, 3.0, testutil.ToFloat64(m.eventsDropped.WithLabelValues("queue_full")))
+	assert.Equal(t, 2, testutil.CollectAndCount(m, "flagkit_http_request_duration_seconds"))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	gauges := map[string]float64{}
+	for _, family := range families {
+		if family.GetType().String() == "GAUGE" {
+			gauges[family.GetName()] = family.GetMetric()[0].GetGauge().GetValue()
+		}
+	}
+	assert.Equal(t, map[string]float64{
+		"flagkit_event_queue_depth": 4,
+		"flagkit_persisted_events":  2,
+	}, gauges)
+
+	unregister()
+	assert.Equal(t, types.EventStats{}, m.observers.Stats())
+}
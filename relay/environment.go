@@ -0,0 +1,478 @@
+package relay
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/teracrafts/flagkit-go/internal/core"
+	"github.com/teracrafts/flagkit-go/internal/http"
+	inttypes "github.com/teracrafts/flagkit-go/internal/types"
+	"github.com/teracrafts/flagkit-go/types"
+)
+
+// maxFlags is the maximum number of flags cached per environment.
+const maxFlags = 10000
+
+// subscriberBufferSize is the number of stream events buffered per downstream
+// stream. Streams that fall further behind are closed so the SDK reconnects
+// and resynchronizes.
+const subscriberBufferSize = 64
+
+// streamEvent is an SSE event sent to downstream streams.
+type streamEvent struct {
+	name string
+	data []byte
+}
+
+// subscriber is a downstream stream.
+type subscriber struct {
+	events chan streamEvent
+	closed chan struct{}
+}
+
+// environment keeps the flag state of one environment up to date.
+type environment struct {
+	apiKey     string
+	config     *Config
+	httpClient *http.HTTPClient
+	cache      *core.Cache
+	events     *eventForwarder
+	logger     Logger
+
+	polling     *core.PollingManager
+	streaming   *core.StreamingManager
+	init        *types.InitResponse
+	lastUpdate  string
+	updatedAt   map[string]time.Time
+	subscribers map[*subscriber]struct{}
+	closed      bool
+	mu          sync.RWMutex
+}
+
+// newEnvironment creates an environment. Call start to load its flags.
+func newEnvironment(envConfig EnvironmentConfig, config *Config) *environment {
+	httpClient := http.NewHTTPClient(&http.HTTPClientConfig{
+		BaseURL:   config.BaseURL,
+		APIKey:    envConfig.APIKey,
+		Timeout:   config.Timeout,
+		Logger:    config.Logger,
+		Transport: config.Transport,
+	})
+
+	return &environment{
+		apiKey:     envConfig.APIKey,
+		config:     config,
+		httpClient: httpClient,
+		cache: core.NewCache(&core.CacheConfig{
+			TTL:     24 * time.Hour,
+			MaxSize: maxFlags,
+			Logger:  config.Logger,
+		}),
+		events:      newEventForwarder(httpClient, config),
+		logger:      config.Logger,
+		updatedAt:   make(map[string]time.Time),
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// keyID returns a loggable identifier for the environment.
+func (e *environment) keyID() string {
+	return keyID(e.apiKey)
+}
+
+// start loads the flags and starts streaming or polling for updates.
+// If loading fails, loading is retried by polling.
+func (e *environment) start() error {
+	e.events.start()
+
+	data, err := e.load()
+	if err != nil {
+		e.startPolling()
+		return err
+	}
+
+	if e.shouldStream(data) {
+		e.startStreaming(data.StreamingURL)
+	} else {
+		e.startPolling()
+	}
+	return nil
+}
+
+// load fetches all flags from the init endpoint.
+func (e *environment) load() (*types.InitResponse, error) {
+	resp, err := e.httpClient.Get("/sdk/init")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := types.ParseInitResponse(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	flags := make([]inttypes.FlagState, len(data.Flags))
+	for i, f := range data.Flags {
+		flags[i] = toInternalFlagState(f)
+	}
+	e.resetFlags(flags)
+
+	e.mu.Lock()
+	e.init = data
+	e.lastUpdate = data.ServerTime
+	e.mu.Unlock()
+
+	e.logger.Info("Environment loaded", "key", e.keyID(), "environment", data.Environment, "flag_count", len(flags))
+	return data, nil
+}
+
+// isLoaded returns whether the flags have been loaded.
+func (e *environment) isLoaded() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.init != nil
+}
+
+// name returns the environment name, or "" if the flags have not been loaded.
+func (e *environment) name() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.init == nil {
+		return ""
+	}
+	return e.init.Environment
+}
+
+// shouldStream returns whether streaming should be used for this init response.
+func (e *environment) shouldStream(data *types.InitResponse) bool {
+	if !e.config.Streaming {
+		return false
+	}
+	if data.StreamingURL != "" {
+		return true
+	}
+	return data.Metadata != nil && data.Metadata.Features != nil && data.Metadata.Features.Streaming
+}
+
+// startStreaming connects to the SSE stream for real-time flag updates.
+func (e *environment) startStreaming(streamingURL string) {
+	if streamingURL == "" {
+		streamingURL = e.httpClient.GetBaseURL()
+	}
+
+	e.mu.Lock()
+	if e.streaming != nil || e.closed {
+		e.mu.Unlock()
+		return
+	}
+	streamingConfig := core.DefaultStreamingConfig()
+	streamingConfig.HTTPClient = e.httpClient.StreamingClient()
+	e.streaming = core.NewStreamingManager(
+		streamingURL,
+		e.httpClient.GetActiveAPIKey,
+		streamingConfig,
+		e.handleFlagUpdate,
+		e.handleFlagDelete,
+		e.handleFlagsReset,
+		e.startPolling,
+		nil,
+		nil,
+		e.logger,
+	)
+	sm := e.streaming
+	e.mu.Unlock()
+
+	sm.Connect()
+}
+
+// startPolling starts polling for updates.
+func (e *environment) startPolling() {
+	e.mu.Lock()
+	if e.polling != nil || e.closed {
+		e.mu.Unlock()
+		return
+	}
+	e.polling = core.NewPollingManager(e.poll, &core.PollingConfig{
+		Interval:          e.config.PollingInterval,
+		Jitter:            time.Second,
+		BackoffMultiplier: 2.0,
+		MaxInterval:       5 * time.Minute,
+	}, e.logger)
+	pm := e.polling
+	e.mu.Unlock()
+
+	pm.Start()
+}
+
+// poll fetches updates, or loads the flags if they have not been loaded yet.
+func (e *environment) poll() {
+	e.mu.RLock()
+	pm := e.polling
+	since := e.lastUpdate
+	e.mu.RUnlock()
+
+	if !e.isLoaded() {
+		if _, err := e.load(); err != nil {
+			e.logger.Warn("Failed to load environment", "key", e.keyID(), "error", err.Error())
+			pm.OnError()
+			return
+		}
+		pm.OnSuccess()
+		return
+	}
+
+	if since == "" {
+		since = time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	}
+
+	resp, err := e.httpClient.Get("/sdk/updates?since=" + url.QueryEscape(since))
+	if err != nil {
+		e.logger.Warn("Failed to fetch updates", "key", e.keyID(), "error", err.Error())
+		pm.OnError()
+		return
+	}
+
+	data, err := types.ParseUpdatesResponse(resp.Body)
+	if err != nil {
+		e.logger.Warn("Failed to parse updates response", "key", e.keyID(), "error", err.Error())
+		pm.OnError()
+		return
+	}
+
+	for _, f := range data.Flags {
+		flag := toInternalFlagState(f)
+		e.handleFlagUpdate(&flag)
+	}
+
+	e.mu.Lock()
+	e.lastUpdate = data.CheckedAt
+	e.mu.Unlock()
+
+	pm.OnSuccess()
+}
+
+// handleFlagUpdate stores an updated flag and notifies downstream streams.
+func (e *environment) handleFlagUpdate(flag *inttypes.FlagState) {
+	if flag == nil || flag.Key == "" {
+		return
+	}
+
+	e.cache.Set(flag.Key, *flag)
+	e.mu.Lock()
+	e.updatedAt[flag.Key] = time.Now()
+	e.mu.Unlock()
+
+	e.broadcast("flag_updated", toPublicFlagState(*flag))
+}
+
+// handleFlagDelete removes a deleted flag and notifies downstream streams.
+func (e *environment) handleFlagDelete(key string) {
+	e.cache.Delete(key)
+	e.mu.Lock()
+	delete(e.updatedAt, key)
+	e.mu.Unlock()
+
+	e.broadcast("flag_deleted", map[string]string{"key": key})
+}
+
+// handleFlagsReset replaces all flags and notifies downstream streams.
+func (e *environment) handleFlagsReset(flags []*inttypes.FlagState) {
+	internalFlags := make([]inttypes.FlagState, 0, len(flags))
+	for _, f := range flags {
+		if f != nil {
+			internalFlags = append(internalFlags, *f)
+		}
+	}
+	e.resetFlags(internalFlags)
+
+	e.broadcast("flags_reset", e.flags())
+}
+
+// resetFlags replaces all flags.
+func (e *environment) resetFlags(flags []inttypes.FlagState) {
+	now := time.Now()
+	updatedAt := make(map[string]time.Time, len(flags))
+	for _, f := range flags {
+		updatedAt[f.Key] = now
+	}
+
+	e.cache.Clear()
+	e.cache.SetMany(flags)
+
+	e.mu.Lock()
+	e.updatedAt = updatedAt
+	e.mu.Unlock()
+}
+
+// flags returns all flags.
+func (e *environment) flags() []types.FlagState {
+	internalFlags := e.cache.GetAll()
+	flags := make([]types.FlagState, len(internalFlags))
+	for i, f := range internalFlags {
+		flags[i] = toPublicFlagState(f)
+	}
+	return flags
+}
+
+// flagsSince returns the flags updated after the given time.
+func (e *environment) flagsSince(since time.Time) []types.FlagState {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	flags := make([]types.FlagState, 0)
+	for _, f := range e.cache.GetAll() {
+		if updated, ok := e.updatedAt[f.Key]; ok && updated.After(since) {
+			flags = append(flags, toPublicFlagState(f))
+		}
+	}
+	return flags
+}
+
+// initResponse returns the init response served to downstream SDKs.
+// Returns nil if the flags have not been loaded.
+func (e *environment) initResponse() *types.InitResponse {
+	e.mu.RLock()
+	upstream := e.init
+	e.mu.RUnlock()
+	if upstream == nil {
+		return nil
+	}
+
+	data := *upstream
+	data.Flags = e.flags()
+	data.ServerTime = time.Now().UTC().Format(time.RFC3339Nano)
+
+	// Downstream SDKs stream from the relay itself.
+	data.StreamingURL = ""
+	metadata := types.InitResponseMetadata{}
+	if upstream.Metadata != nil {
+		metadata = *upstream.Metadata
+	}
+	features := types.InitResponseFeatures{}
+	if metadata.Features != nil {
+		features = *metadata.Features
+	}
+	features.Streaming = true
+	// The relay does not serve rules, segments or experiments.
+	features.LocalEval = false
+	features.Segments = false
+	features.Experiments = false
+	metadata.Features = &features
+	data.Metadata = &metadata
+
+	return &data
+}
+
+// subscribe registers a downstream stream.
+func (e *environment) subscribe() *subscriber {
+	sub := &subscriber{
+		events: make(chan streamEvent, subscriberBufferSize),
+		closed: make(chan struct{}),
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		close(sub.closed)
+		return sub
+	}
+	e.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// unsubscribe removes a downstream stream.
+func (e *environment) unsubscribe(sub *subscriber) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, ok := e.subscribers[sub]; ok {
+		delete(e.subscribers, sub)
+		close(sub.closed)
+	}
+}
+
+// subscriberCount returns the number of downstream streams.
+func (e *environment) subscriberCount() int {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return len(e.subscribers)
+}
+
+// broadcast sends an event to all downstream streams. Streams whose buffer
+// is full are closed.
+func (e *environment) broadcast(name string, payload any) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		e.logger.Warn("Failed to encode stream event", "event", name, "error", err.Error())
+		return
+	}
+	event := streamEvent{name: name, data: data}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for sub := range e.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			e.logger.Warn("Downstream stream is too slow, disconnecting", "key", e.keyID())
+			delete(e.subscribers, sub)
+			close(sub.closed)
+		}
+	}
+}
+
+// close stops updates, closes downstream streams and forwards buffered events.
+func (e *environment) close() {
+	e.mu.Lock()
+	e.closed = true
+	pm := e.polling
+	sm := e.streaming
+	for sub := range e.subscribers {
+		delete(e.subscribers, sub)
+		close(sub.closed)
+	}
+	e.mu.Unlock()
+
+	if pm != nil {
+		pm.Stop()
+	}
+	if sm != nil {
+		sm.Disconnect()
+	}
+	e.events.stop()
+	_ = e.httpClient.Close()
+}
+
+// toInternalFlagState converts a public FlagState to the internal type.
+func toInternalFlagState(f types.FlagState) inttypes.FlagState {
+	return inttypes.FlagState{
+		Key:          f.Key,
+		Value:        f.Value,
+		Enabled:      f.Enabled,
+		Version:      f.Version,
+		FlagType:     inttypes.FlagType(f.FlagType),
+		LastModified: f.LastModified,
+	}
+}
+
+// toPublicFlagState converts an internal FlagState to the public type.
+func toPublicFlagState(f inttypes.FlagState) types.FlagState {
+	return types.FlagState{
+		Key:          f.Key,
+		Value:        f.Value,
+		Enabled:      f.Enabled,
+		Version:      f.Version,
+		FlagType:     types.FlagType(f.FlagType),
+		LastModified: f.LastModified,
+	}
+}
+
+// generateToken generates a random stream token.
+func generateToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
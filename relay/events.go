@@ -0,0 +1,182 @@
+package relay
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/teracrafts/flagkit-go/internal/http"
+)
+
+// eventForwarder aggregates events from downstream SDKs and forwards them to
+// the API in batches.
+//
+// Events are buffered up to the configured size. Batches that fail to send
+// are returned to the buffer, so while the API is unreachable the buffer
+// fills up and new events are rejected, pushing back on downstream SDKs.
+type eventForwarder struct {
+	httpClient    *http.HTTPClient
+	bufferSize    int
+	batchSize     int
+	flushInterval time.Duration
+	logger        Logger
+
+	buffer  []json.RawMessage
+	running bool
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+	flushCh chan struct{}
+	mu      sync.Mutex
+}
+
+// newEventForwarder creates an event forwarder.
+func newEventForwarder(httpClient *http.HTTPClient, config *Config) *eventForwarder {
+	return &eventForwarder{
+		httpClient:    httpClient,
+		bufferSize:    config.EventBufferSize,
+		batchSize:     config.EventBatchSize,
+		flushInterval: config.EventFlushInterval,
+		logger:        config.Logger,
+		flushCh:       make(chan struct{}, 1),
+	}
+}
+
+// start starts the background forwarding loop.
+func (f *eventForwarder) start() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.running {
+		return
+	}
+	f.running = true
+	f.stopCh = make(chan struct{})
+	f.doneCh = make(chan struct{})
+	go f.run(f.stopCh, f.doneCh)
+}
+
+// stop stops the forwarding loop and forwards buffered events.
+func (f *eventForwarder) stop() {
+	f.mu.Lock()
+	if !f.running {
+		f.mu.Unlock()
+		return
+	}
+	f.running = false
+	close(f.stopCh)
+	doneCh := f.doneCh
+	f.mu.Unlock()
+
+	<-doneCh
+}
+
+// enqueue buffers events for forwarding.
+// Returns false, buffering nothing, if the events do not fit in the buffer.
+func (f *eventForwarder) enqueue(events []json.RawMessage) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.buffer)+len(events) > f.bufferSize {
+		return false
+	}
+	f.buffer = append(f.buffer, events...)
+
+	if len(f.buffer) >= f.batchSize {
+		select {
+		case f.flushCh <- struct{}{}:
+		default:
+		}
+	}
+	return true
+}
+
+// size returns the number of buffered events.
+func (f *eventForwarder) size() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.buffer)
+}
+
+// run is the background forwarding loop.
+func (f *eventForwarder) run(stopCh, doneCh chan struct{}) {
+	defer close(doneCh)
+
+	ticker := time.NewTicker(f.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			f.flush()
+			return
+		case <-ticker.C:
+			f.flush()
+		case <-f.flushCh:
+			f.flush()
+		}
+	}
+}
+
+// flush forwards buffered events in batches until the buffer is empty or a
+// batch fails to send.
+func (f *eventForwarder) flush() {
+	for {
+		batch := f.take()
+		if len(batch) == 0 {
+			return
+		}
+		if !f.send(batch) {
+			f.requeue(batch)
+			return
+		}
+	}
+}
+
+// take removes up to one batch of events from the buffer.
+func (f *eventForwarder) take() []json.RawMessage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	n := len(f.buffer)
+	if n > f.batchSize {
+		n = f.batchSize
+	}
+	batch := make([]json.RawMessage, n)
+	copy(batch, f.buffer[:n])
+	f.buffer = f.buffer[n:]
+	return batch
+}
+
+// requeue returns a batch that failed to send to the front of the buffer.
+// Events that no longer fit are dropped.
+func (f *eventForwarder) requeue(batch []json.RawMessage) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	room := f.bufferSize - len(f.buffer)
+	if room < len(batch) {
+		if f.logger != nil {
+			f.logger.Warn("Event buffer full, dropping events", "count", len(batch)-room)
+		}
+		batch = batch[:room]
+	}
+	f.buffer = append(batch, f.buffer...)
+}
+
+// send forwards a batch of events to the API.
+func (f *eventForwarder) send(batch []json.RawMessage) bool {
+	payload := map[string]any{
+		"events": batch,
+	}
+
+	if _, err := f.httpClient.Post("/sdk/events/batch", payload); err != nil {
+		if f.logger != nil {
+			f.logger.Warn("Failed to forward events", "error", err.Error(), "count", len(batch))
+		}
+		return false
+	}
+
+	if f.logger != nil {
+		f.logger.Debug("Forwarded events", "count", len(batch))
+	}
+	return true
+}
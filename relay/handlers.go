@@ -0,0 +1,236 @@
+package relay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/teracrafts/flagkit-go/types"
+)
+
+// eventsBatchRequest is the request body of the events batch endpoint.
+type eventsBatchRequest struct {
+	Events []json.RawMessage `json:"events"`
+}
+
+// environmentStatus is the status of an environment reported by /status.
+type environmentStatus struct {
+	Key          string `json:"key"`
+	Environment  string `json:"environment,omitempty"`
+	Loaded       bool   `json:"loaded"`
+	Flags        int    `json:"flags"`
+	Streams      int    `json:"streams"`
+	QueuedEvents int    `json:"queuedEvents"`
+}
+
+// handleInit serves all flags of the environment.
+func (r *Relay) handleInit(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed)
+		return
+	}
+	env := r.environmentFor(req)
+	if env == nil {
+		writeError(w, http.StatusUnauthorized)
+		return
+	}
+
+	data := env.initResponse()
+	if data == nil {
+		writeError(w, http.StatusServiceUnavailable)
+		return
+	}
+	writeJSON(w, http.StatusOK, data)
+}
+
+// handleUpdates serves the flags updated since the time in the since parameter.
+func (r *Relay) handleUpdates(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed)
+		return
+	}
+	env := r.environmentFor(req)
+	if env == nil {
+		writeError(w, http.StatusUnauthorized)
+		return
+	}
+	if !env.isLoaded() {
+		writeError(w, http.StatusServiceUnavailable)
+		return
+	}
+
+	checkedAt := time.Now().UTC()
+	since := req.URL.Query().Get("since")
+
+	var flags []types.FlagState
+	if sinceTime, err := time.Parse(time.RFC3339Nano, since); err == nil {
+		flags = env.flagsSince(sinceTime)
+	} else {
+		flags = env.flags()
+	}
+
+	writeJSON(w, http.StatusOK, &types.UpdatesResponse{
+		Flags:     flags,
+		CheckedAt: checkedAt.Format(time.RFC3339Nano),
+		Since:     since,
+	})
+}
+
+// handleStreamToken issues a stream token for the environment.
+func (r *Relay) handleStreamToken(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed)
+		return
+	}
+	env := r.environmentFor(req)
+	if env == nil {
+		writeError(w, http.StatusUnauthorized)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"token":     r.issueToken(env),
+		"expiresIn": int(DefaultStreamTokenTTL / time.Second),
+	})
+}
+
+// handleStream serves flag changes of the environment as Server-Sent Events.
+// All flags are sent as a flags_reset event when the stream opens.
+func (r *Relay) handleStream(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed)
+		return
+	}
+	env := r.redeemToken(req.URL.Query().Get("token"))
+	if env == nil {
+		writeError(w, http.StatusUnauthorized)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError)
+		return
+	}
+
+	sub := env.subscribe()
+	defer env.unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	reset, err := json.Marshal(env.flags())
+	if err != nil {
+		return
+	}
+	writeEvent(w, streamEvent{name: "flags_reset", data: reset})
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(r.config.HeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case <-sub.closed:
+			return
+		case event := <-sub.events:
+			writeEvent(w, event)
+		case <-heartbeat.C:
+			writeEvent(w, streamEvent{name: "heartbeat", data: []byte("{}")})
+		}
+		flusher.Flush()
+	}
+}
+
+// handleEvents buffers a batch of events for forwarding.
+// Responds 413 Request Entity Too Large if the body or the batch is larger than
+// allowed, and 429 Too Many Requests if the event buffer is full.
+func (r *Relay) handleEvents(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed)
+		return
+	}
+	env := r.environmentFor(req)
+	if env == nil {
+		writeError(w, http.StatusUnauthorized)
+		return
+	}
+
+	var body eventsBatchRequest
+	reader := http.MaxBytesReader(w, req.Body, r.config.MaxEventsBodySize)
+	if err := json.NewDecoder(reader).Decode(&body); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, http.StatusRequestEntityTooLarge)
+			return
+		}
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+	// A batch larger than the buffer would never be accepted
+	if len(body.Events) > r.config.EventBufferSize {
+		writeError(w, http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if !env.events.enqueue(body.Events) {
+		retryAfter := int(r.config.EventFlushInterval / time.Second)
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		writeError(w, http.StatusTooManyRequests)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &types.EventsBatchResponse{
+		Success:  true,
+		Message:  "events accepted",
+		Recorded: len(body.Events),
+	})
+}
+
+// handleStatus reports the status of the environment of the request's API key.
+func (r *Relay) handleStatus(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed)
+		return
+	}
+	env := r.environmentFor(req)
+	if env == nil {
+		writeError(w, http.StatusUnauthorized)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &environmentStatus{
+		Key:          env.keyID(),
+		Environment:  env.name(),
+		Loaded:       env.isLoaded(),
+		Flags:        env.cache.Size(),
+		Streams:      env.subscriberCount(),
+		QueuedEvents: env.events.size(),
+	})
+}
+
+// writeEvent writes an SSE event.
+func writeEvent(w http.ResponseWriter, event streamEvent) {
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.name, event.data)
+}
+
+// writeJSON writes a JSON response.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error response with the standard status text.
+func writeError(w http.ResponseWriter, status int) {
+	http.Error(w, http.StatusText(status), status)
+}
@@ -0,0 +1,307 @@
+// Package relay provides a relay proxy that serves FlagKit SDK endpoints on an
+// internal network.
+//
+// A relay keeps flag state for one or more environments up to date using the
+// same HTTP client, streaming, polling and cache components as the SDK client,
+// and re-serves /sdk/init, /sdk/updates, the SSE stream and /sdk/events/batch
+// to downstream SDKs. Downstream SDKs authenticate with the SDK key of their
+// environment and only the relay connects to the FlagKit API.
+//
+// Events received from downstream SDKs are aggregated into batches and
+// forwarded upstream. When the event buffer of an environment is full the
+// relay answers 429 Too Many Requests so that SDKs back off.
+//
+// GET /status reports the state of the environment of the X-API-Key header.
+package relay
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/teracrafts/flagkit-go/errors"
+	"github.com/teracrafts/flagkit-go/types"
+)
+
+// Logger is an alias for the types.Logger interface.
+type Logger = types.Logger
+
+// Default configuration values.
+const (
+	// DefaultPollingInterval is the default interval for polling the API for updates.
+	DefaultPollingInterval = 30 * time.Second
+
+	// DefaultTimeout is the default upstream request timeout.
+	DefaultTimeout = 5 * time.Second
+
+	// DefaultHeartbeatInterval is the default interval between heartbeats sent
+	// to downstream streams.
+	DefaultHeartbeatInterval = 15 * time.Second
+
+	// DefaultStreamTokenTTL is the lifetime of stream tokens issued to downstream SDKs.
+	DefaultStreamTokenTTL = 5 * time.Minute
+
+	// DefaultEventBufferSize is the default number of events buffered per environment.
+	DefaultEventBufferSize = 10000
+
+	// DefaultEventBatchSize is the default number of events forwarded per request.
+	DefaultEventBatchSize = 100
+
+	// DefaultEventFlushInterval is the default interval for forwarding buffered events.
+	DefaultEventFlushInterval = 5 * time.Second
+
+	// DefaultMaxEventsBodySize is the default maximum size of an events batch request body (1 MiB).
+	DefaultMaxEventsBodySize = 1 << 20
+)
+
+// EnvironmentConfig configures a single relayed environment.
+type EnvironmentConfig struct {
+	// APIKey is the SDK key of the environment (required).
+	// Downstream SDKs use the same key to authenticate with the relay.
+	APIKey string
+}
+
+// Config configures a Relay.
+type Config struct {
+	// Environments are the environments served by the relay (at least one is required).
+	Environments []EnvironmentConfig
+
+	// BaseURL is the FlagKit API base URL.
+	// Default: the production API, or the API selected by FLAGKIT_MODE.
+	BaseURL string
+
+	// Streaming enables an SSE connection to the API for real-time updates
+	// when the environment supports it. The relay falls back to polling otherwise.
+	Streaming bool
+
+	// PollingInterval is the interval for polling the API for updates.
+	// Default: 30 seconds.
+	PollingInterval time.Duration
+
+	// Timeout is the upstream request timeout.
+	// Default: 5 seconds.
+	Timeout time.Duration
+
+	// Transport is the HTTP transport used for upstream requests, for example
+	// to use a proxy or client certificates. Default: http.DefaultTransport.
+	Transport http.RoundTripper
+
+	// HeartbeatInterval is the interval between heartbeats sent to downstream streams.
+	// Default: 15 seconds.
+	HeartbeatInterval time.Duration
+
+	// EventBufferSize is the maximum number of events buffered per environment.
+	// Event batches that do not fit are rejected with 429 Too Many Requests,
+	// or 413 Request Entity Too Large if they are larger than the buffer.
+	// Default: 10000.
+	EventBufferSize int
+
+	// EventBatchSize is the maximum number of events forwarded per request.
+	// Default: 100.
+	EventBatchSize int
+
+	// EventFlushInterval is the interval for forwarding buffered events.
+	// Default: 5 seconds.
+	EventFlushInterval time.Duration
+
+	// MaxEventsBodySize is the maximum size in bytes of an events batch
+	// request body. Larger bodies are rejected with 413 Request Entity Too Large.
+	// Default: 1 MiB.
+	MaxEventsBodySize int64
+
+	// Logger is a custom logger implementation.
+	Logger Logger
+}
+
+// Validate validates the configuration and applies defaults.
+func (c *Config) Validate() error {
+	if len(c.Environments) == 0 {
+		return errors.NewError(errors.ErrConfigMissingRequired, "at least one environment is required")
+	}
+
+	seen := make(map[string]bool, len(c.Environments))
+	for _, env := range c.Environments {
+		if env.APIKey == "" {
+			return errors.NewError(errors.ErrConfigMissingRequired, "API key is required")
+		}
+		if len(env.APIKey) < 10 {
+			return errors.NewError(errors.ErrAuthInvalidKey, "API key is too short")
+		}
+		if seen[env.APIKey] {
+			return errors.NewError(errors.ErrConfigMissingRequired, "duplicate environment API key")
+		}
+		seen[env.APIKey] = true
+	}
+
+	if c.BaseURL != "" {
+		u, err := url.Parse(c.BaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.NewError(errors.ErrConfigInvalidURL, "invalid base URL: "+c.BaseURL)
+		}
+	}
+
+	if c.PollingInterval == 0 {
+		c.PollingInterval = DefaultPollingInterval
+	}
+	if c.PollingInterval < time.Second {
+		return errors.NewError(errors.ErrConfigInvalidInterval, "Polling interval must be at least 1 second")
+	}
+
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+	if c.HeartbeatInterval <= 0 {
+		c.HeartbeatInterval = DefaultHeartbeatInterval
+	}
+	if c.EventBufferSize <= 0 {
+		c.EventBufferSize = DefaultEventBufferSize
+	}
+	if c.EventBatchSize <= 0 {
+		c.EventBatchSize = DefaultEventBatchSize
+	}
+	if c.EventFlushInterval <= 0 {
+		c.EventFlushInterval = DefaultEventFlushInterval
+	}
+	if c.MaxEventsBodySize <= 0 {
+		c.MaxEventsBodySize = DefaultMaxEventsBodySize
+	}
+	if c.Logger == nil {
+		c.Logger = &types.NullLogger{}
+	}
+
+	return nil
+}
+
+// Relay maintains flag state for a set of environments and serves it to
+// downstream SDKs.
+type Relay struct {
+	config       *Config
+	environments map[string]*environment
+	tokens       map[string]streamToken
+	logger       Logger
+	closed       bool
+	mu           sync.Mutex
+}
+
+// streamToken is a stream token issued to a downstream SDK.
+type streamToken struct {
+	env       *environment
+	expiresAt time.Time
+}
+
+// New creates a new relay. Call Start to connect to the API.
+func New(config Config) (*Relay, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	r := &Relay{
+		config:       &config,
+		environments: make(map[string]*environment, len(config.Environments)),
+		tokens:       make(map[string]streamToken),
+		logger:       config.Logger,
+	}
+	for _, envConfig := range config.Environments {
+		r.environments[envConfig.APIKey] = newEnvironment(envConfig, r.config)
+	}
+	return r, nil
+}
+
+// Start loads the flags of every environment and starts keeping them up to
+// date. Environments that fail to load are retried by polling; the first
+// error is returned.
+func (r *Relay) Start() error {
+	var firstErr error
+	for _, env := range r.environments {
+		if err := env.start(); err != nil {
+			r.logger.Error("Failed to initialize environment", "key", env.keyID(), "error", err.Error())
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	if firstErr != nil {
+		return errors.NewErrorWithCause(errors.ErrInitFailed, "failed to initialize relay", firstErr)
+	}
+
+	r.logger.Info("Relay started", "environments", len(r.environments))
+	return nil
+}
+
+// Close stops all environments and forwards any buffered events.
+func (r *Relay) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	r.tokens = make(map[string]streamToken)
+	r.mu.Unlock()
+
+	for _, env := range r.environments {
+		env.close()
+	}
+
+	r.logger.Info("Relay closed")
+	return nil
+}
+
+// Handler returns the HTTP handler serving the SDK endpoints.
+func (r *Relay) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sdk/init", r.handleInit)
+	mux.HandleFunc("/sdk/updates", r.handleUpdates)
+	mux.HandleFunc("/sdk/stream/token", r.handleStreamToken)
+	mux.HandleFunc("/sdk/stream", r.handleStream)
+	mux.HandleFunc("/sdk/events/batch", r.handleEvents)
+	mux.HandleFunc("/status", r.handleStatus)
+	return mux
+}
+
+// environmentFor returns the environment of a request's API key, or nil.
+func (r *Relay) environmentFor(req *http.Request) *environment {
+	return r.environments[req.Header.Get("X-API-Key")]
+}
+
+// issueToken issues a single-use stream token for an environment.
+func (r *Relay) issueToken(env *environment) string {
+	token := generateToken()
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for t, st := range r.tokens {
+		if now.After(st.expiresAt) {
+			delete(r.tokens, t)
+		}
+	}
+	r.tokens[token] = streamToken{env: env, expiresAt: now.Add(DefaultStreamTokenTTL)}
+	return token
+}
+
+// redeemToken returns the environment of a stream token and invalidates it.
+func (r *Relay) redeemToken(token string) *environment {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	st, ok := r.tokens[token]
+	if !ok {
+		return nil
+	}
+	delete(r.tokens, token)
+	if time.Now().After(st.expiresAt) {
+		return nil
+	}
+	return st.env
+}
+
+// keyID returns a loggable identifier for an API key.
+func keyID(apiKey string) string {
+	if len(apiKey) <= 8 {
+		return apiKey
+	}
+	return fmt.Sprintf("%s...", apiKey[:8])
+}
@@ -0,0 +1,257 @@
+package relay
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	inttypes "github.com/teracrafts/flagkit-go/internal/types"
+	"github.com/teracrafts/flagkit-go/types"
+)
+
+const testKey = "sdk_relay_test_key"
+
+// newTestRelay creates a relay whose environment is loaded with the given
+// flags without contacting the API.
+func newTestRelay(t *testing.T, config Config, flags ...inttypes.FlagState) (*Relay, *environment) {
+	t.Helper()
+
+	config.Environments = []EnvironmentConfig{{APIKey: testKey}}
+	r, err := New(config)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = r.Close() })
+
+	env := r.environments[testKey]
+	env.resetFlags(flags)
+	env.init = &types.InitResponse{
+		Environment:            "production",
+		EnvironmentID:          "env-1",
+		PollingIntervalSeconds: 30,
+		StreamingURL:           "https://stream.flagkit.dev",
+		Metadata: &types.InitResponseMetadata{
+			Features: &types.InitResponseFeatures{LocalEval: true, Segments: true, Experiments: true},
+		},
+	}
+	return r, env
+}
+
+func doRequest(t *testing.T, handler http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("X-API-Key", testKey)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestConfigValidate(t *testing.T) {
+	config := &Config{}
+	assert.Error(t, config.Validate())
+
+	config = &Config{Environments: []EnvironmentConfig{{APIKey: "short"}}}
+	assert.Error(t, config.Validate())
+
+	config = &Config{Environments: []EnvironmentConfig{{APIKey: testKey}, {APIKey: testKey}}}
+	assert.Error(t, config.Validate())
+
+	config = &Config{Environments: []EnvironmentConfig{{APIKey: testKey}}, PollingInterval: time.Millisecond}
+	assert.Error(t, config.Validate())
+
+	config = &Config{Environments: []EnvironmentConfig{{APIKey: testKey}}, BaseURL: "api.flagkit.dev"}
+	assert.Error(t, config.Validate())
+
+	config = &Config{Environments: []EnvironmentConfig{{APIKey: testKey}}}
+	require.NoError(t, config.Validate())
+	assert.Equal(t, DefaultPollingInterval, config.PollingInterval)
+	assert.Equal(t, DefaultEventBufferSize, config.EventBufferSize)
+	assert.Equal(t, DefaultEventBatchSize, config.EventBatchSize)
+	assert.NotNil(t, config.Logger)
+}
+
+func TestHandleInit(t *testing.T) {
+	r, _ := newTestRelay(t, Config{}, inttypes.FlagState{Key: "checkout", Value: true, Enabled: true, Version: 2})
+	handler := r.Handler()
+
+	req := httptest.NewRequest(http.MethodGet, "/sdk/init", nil)
+	req.Header.Set("X-API-Key", "sdk_unknown_key")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = doRequest(t, handler, http.MethodGet, "/sdk/init", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	data, err := types.ParseInitResponse(rec.Body.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, "production", data.Environment)
+	assert.Empty(t, data.StreamingURL)
+	require.NotNil(t, data.Metadata)
+	assert.True(t, data.Metadata.Features.Streaming)
+	assert.False(t, data.Metadata.Features.LocalEval)
+	assert.False(t, data.Metadata.Features.Segments)
+	assert.False(t, data.Metadata.Features.Experiments)
+	require.Len(t, data.Flags, 1)
+	assert.Equal(t, "checkout", data.Flags[0].Key)
+	assert.Equal(t, 2, data.Flags[0].Version)
+}
+
+func TestHandleInitNotLoaded(t *testing.T) {
+	r, env := newTestRelay(t, Config{})
+	env.init = nil
+
+	rec := doRequest(t, r.Handler(), http.MethodGet, "/sdk/init", "")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestHandleUpdates(t *testing.T) {
+	r, env := newTestRelay(t, Config{}, inttypes.FlagState{Key: "checkout", Value: true, Enabled: true})
+	handler := r.Handler()
+
+	since := time.Now().UTC().Format(time.RFC3339Nano)
+	time.Sleep(time.Millisecond)
+	env.handleFlagUpdate(&inttypes.FlagState{Key: "banner", Value: "blue", Enabled: true})
+
+	rec := doRequest(t, handler, http.MethodGet, "/sdk/updates?since="+since, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	data, err := types.ParseUpdatesResponse(rec.Body.Bytes())
+	require.NoError(t, err)
+	require.Len(t, data.Flags, 1)
+	assert.Equal(t, "banner", data.Flags[0].Key)
+	assert.NotEmpty(t, data.CheckedAt)
+
+	rec = doRequest(t, handler, http.MethodGet, "/sdk/updates", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	data, err = types.ParseUpdatesResponse(rec.Body.Bytes())
+	require.NoError(t, err)
+	assert.Len(t, data.Flags, 2)
+}
+
+func TestHandleEventsBackpressure(t *testing.T) {
+	r, env := newTestRelay(t, Config{EventBufferSize: 3, EventFlushInterval: 2 * time.Second})
+	handler := r.Handler()
+
+	rec := doRequest(t, handler, http.MethodPost, "/sdk/events/batch", `{"events":[{"type":"a"},{"type":"b"}]}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 2, env.events.size())
+
+	rec = doRequest(t, handler, http.MethodPost, "/sdk/events/batch", `{"events":[{"type":"c"},{"type":"d"}]}`)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+	assert.Equal(t, 2, env.events.size())
+
+	rec = doRequest(t, handler, http.MethodPost, "/sdk/events/batch", `not json`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestHandleEventsTooLarge(t *testing.T) {
+	r, env := newTestRelay(t, Config{EventBufferSize: 3, MaxEventsBodySize: 64})
+	handler := r.Handler()
+
+	rec := doRequest(t, handler, http.MethodPost, "/sdk/events/batch", `{"events":[{},{},{},{}]}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Empty(t, rec.Header().Get("Retry-After"))
+
+	rec = doRequest(t, handler, http.MethodPost, "/sdk/events/batch", `{"events":[{"type":"`+strings.Repeat("a", 64)+`"}]}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Equal(t, 0, env.events.size())
+}
+
+func TestHandleStatus(t *testing.T) {
+	r, _ := newTestRelay(t, Config{}, inttypes.FlagState{Key: "banner"})
+	handler := r.Handler()
+
+	req := httptest.NewRequest(http.MethodGet, "/status", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = doRequest(t, handler, http.MethodGet, "/status", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var status environmentStatus
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.Equal(t, keyID(testKey), status.Key)
+	assert.Equal(t, 1, status.Flags)
+}
+
+func TestEventForwarderRequeue(t *testing.T) {
+	_, env := newTestRelay(t, Config{EventBufferSize: 4, EventBatchSize: 3})
+
+	f := env.events
+	require.True(t, f.enqueue([]json.RawMessage{[]byte(`1`), []byte(`2`), []byte(`3`), []byte(`4`)}))
+
+	batch := f.take()
+	assert.Len(t, batch, 3)
+	assert.Equal(t, 1, f.size())
+
+	f.requeue(batch)
+	assert.Equal(t, 4, f.size())
+	assert.Equal(t, json.RawMessage(`1`), f.take()[0])
+}
+
+func TestHandleStream(t *testing.T) {
+	r, env := newTestRelay(t, Config{}, inttypes.FlagState{Key: "checkout", Value: true, Enabled: true})
+	server := httptest.NewServer(r.Handler())
+	defer server.Close()
+
+	rec := doRequest(t, r.Handler(), http.MethodPost, "/sdk/stream/token", "{}")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var token struct {
+		Token     string `json:"token"`
+		ExpiresIn int    `json:"expiresIn"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &token))
+	require.NotEmpty(t, token.Token)
+	assert.Positive(t, token.ExpiresIn)
+
+	resp, err := http.Get(server.URL + "/sdk/stream?token=" + token.Token)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	reader := bufio.NewReader(resp.Body)
+	name, data := readEvent(t, reader)
+	assert.Equal(t, "flags_reset", name)
+	assert.Contains(t, data, "checkout")
+
+	require.Eventually(t, func() bool { return env.subscriberCount() == 1 }, time.Second, 10*time.Millisecond)
+	env.handleFlagUpdate(&inttypes.FlagState{Key: "banner", Value: "blue", Enabled: true})
+
+	name, data = readEvent(t, reader)
+	assert.Equal(t, "flag_updated", name)
+	assert.Contains(t, data, "banner")
+
+	// Tokens are single-use.
+	resp2, err := http.Get(server.URL + "/sdk/stream?token=" + token.Token)
+	require.NoError(t, err)
+	resp2.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp2.StatusCode)
+}
+
+// readEvent reads the next SSE event from a stream.
+func readEvent(t *testing.T, reader *bufio.Reader) (string, string) {
+	t.Helper()
+
+	var name, data string
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" && name != "":
+			return name, data
+		case strings.HasPrefix(line, "event:"):
+			name = strings.TrimSpace(line[6:])
+		case strings.HasPrefix(line, "data:"):
+			data = strings.TrimSpace(line[5:])
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/teracrafts/flagkit-go/types"
+)
+
+// fileContents is the on-disk format of a FileStore.
+type fileContents struct {
+	Initialized bool                       `json:"initialized"`
+	Flags       map[string]types.FlagState `json:"flags"`
+}
+
+// FileStore is a FlagStore kept in a JSON file, so that clients in separate
+// processes on one host can share flags written by a single writer.
+//
+// Every write replaces the file atomically, so readers never observe a
+// partially written store. Writes from multiple processes are not
+// coordinated; use a single writer. The file is parsed again only when its
+// modification time or size changes.
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+
+	// Contents last read or written, reused while the file is unchanged
+	contents *fileContents
+	modTime  time.Time
+	size     int64
+}
+
+// NewFileStore creates a store backed by the file at path.
+// The file is created by the first write.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Get returns a flag, or nil if the store does not contain it.
+func (s *FileStore) Get(key string) (*types.FlagState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	contents, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	flag, ok := contents.Flags[key]
+	if !ok {
+		return nil, nil
+	}
+	return &flag, nil
+}
+
+// GetAll returns all flags in the store.
+func (s *FileStore) GetAll() ([]types.FlagState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	contents, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	flags := make([]types.FlagState, 0, len(contents.Flags))
+	for _, flag := range contents.Flags {
+		flags = append(flags, flag)
+	}
+	return flags, nil
+}
+
+// Init replaces all flags in the store and marks it as initialized.
+func (s *FileStore) Init(flags []types.FlagState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	contents := &fileContents{
+		Initialized: true,
+		Flags:       make(map[string]types.FlagState, len(flags)),
+	}
+	for _, flag := range flags {
+		contents.Flags[flag.Key] = flag
+	}
+	return s.write(contents)
+}
+
+// Upsert adds or replaces a flag, ignoring flags older than the stored version.
+func (s *FileStore) Upsert(flag types.FlagState) error {
+	return s.UpsertAll([]types.FlagState{flag})
+}
+
+// UpsertAll adds or replaces flags with a single write, ignoring flags older
+// than the stored versions.
+func (s *FileStore) UpsertAll(flags []types.FlagState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	contents, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	updated := &fileContents{Initialized: contents.Initialized, Flags: maps.Clone(contents.Flags)}
+	changed := false
+	for _, flag := range flags {
+		if existing, ok := updated.Flags[flag.Key]; ok && existing.Version > flag.Version {
+			continue
+		}
+		updated.Flags[flag.Key] = flag
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return s.write(updated)
+}
+
+// Delete removes a flag.
+func (s *FileStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	contents, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := contents.Flags[key]; !ok {
+		return nil
+	}
+
+	updated := &fileContents{Initialized: contents.Initialized, Flags: maps.Clone(contents.Flags)}
+	delete(updated.Flags, key)
+	return s.write(updated)
+}
+
+// Initialized reports whether the store has been initialized.
+func (s *FileStore) Initialized() (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	contents, err := s.read()
+	if err != nil {
+		return false, err
+	}
+	return contents.Initialized, nil
+}
+
+// read loads the store file, reusing the contents last read or written if
+// the file has not changed (must be called with lock held). The returned
+// contents must not be modified. A missing file is an empty, uninitialized
+// store.
+func (s *FileStore) read() (*fileContents, error) {
+	info, err := os.Stat(s.path)
+	if os.IsNotExist(err) {
+		s.contents = nil
+		return &fileContents{Flags: make(map[string]types.FlagState)}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read flag store: %w", err)
+	}
+	if s.contents != nil && info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return s.contents, nil
+	}
+
+	contents := &fileContents{}
+	data, err := os.ReadFile(s.path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read flag store: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, contents); err != nil {
+			return nil, fmt.Errorf("failed to parse flag store: %w", err)
+		}
+	}
+
+	if contents.Flags == nil {
+		contents.Flags = make(map[string]types.FlagState)
+	}
+	s.contents, s.modTime, s.size = contents, info.ModTime(), info.Size()
+	return contents, nil
+}
+
+// write replaces the store file atomically.
+func (s *FileStore) write(contents *fileContents) error {
+	data, err := json.Marshal(contents)
+	if err != nil {
+		return fmt.Errorf("failed to serialize flag store: %w", err)
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create flag store directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create flag store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write flag store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close flag store: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace flag store: %w", err)
+	}
+
+	s.contents = nil
+	if info, err := os.Stat(s.path); err == nil {
+		s.contents, s.modTime, s.size = contents, info.ModTime(), info.Size()
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Package store provides reference FlagStore implementations.
+//
+// Stores let one client in writer mode keep flags up to date for many
+// clients in daemon mode, which read flags from the store instead of the API.
+package store
+
+import (
+	"sync"
+
+	"github.com/teracrafts/flagkit-go/types"
+)
+
+// MemoryStore is a FlagStore held in memory.
+// It is safe for concurrent use and can be shared by clients in one process.
+type MemoryStore struct {
+	flags       map[string]types.FlagState
+	initialized bool
+	mu          sync.RWMutex
+}
+
+// NewMemoryStore creates an empty, uninitialized memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		flags: make(map[string]types.FlagState),
+	}
+}
+
+// Get returns a flag, or nil if the store does not contain it.
+func (s *MemoryStore) Get(key string) (*types.FlagState, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	flag, ok := s.flags[key]
+	if !ok {
+		return nil, nil
+	}
+	return &flag, nil
+}
+
+// GetAll returns all flags in the store.
+func (s *MemoryStore) GetAll() ([]types.FlagState, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	flags := make([]types.FlagState, 0, len(s.flags))
+	for _, flag := range s.flags {
+		flags = append(flags, flag)
+	}
+	return flags, nil
+}
+
+// Init replaces all flags in the store and marks it as initialized.
+func (s *MemoryStore) Init(flags []types.FlagState) error {
+	byKey := make(map[string]types.FlagState, len(flags))
+	for _, flag := range flags {
+		byKey[flag.Key] = flag
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flags = byKey
+	s.initialized = true
+	return nil
+}
+
+// Upsert adds or replaces a flag, ignoring flags older than the stored version.
+func (s *MemoryStore) Upsert(flag types.FlagState) error {
+	return s.UpsertAll([]types.FlagState{flag})
+}
+
+// UpsertAll adds or replaces flags, ignoring flags older than the stored versions.
+func (s *MemoryStore) UpsertAll(flags []types.FlagState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, flag := range flags {
+		if existing, ok := s.flags[flag.Key]; ok && existing.Version > flag.Version {
+			continue
+		}
+		s.flags[flag.Key] = flag
+	}
+	return nil
+}
+
+// Delete removes a flag.
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.flags, key)
+	return nil
+}
+
+// Initialized reports whether the store has been initialized.
+func (s *MemoryStore) Initialized() (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.initialized, nil
+}
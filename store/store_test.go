@@ -0,0 +1,135 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/teracrafts/flagkit-go/types"
+)
+
+func testStores(t *testing.T) map[string]types.FlagStore {
+	return map[string]types.FlagStore{
+		"memory": NewMemoryStore(),
+		"file":   NewFileStore(filepath.Join(t.TempDir(), "flags.json")),
+	}
+}
+
+func TestFlagStores(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			initialized, err := store.Initialized()
+			require.NoError(t, err)
+			assert.False(t, initialized)
+
+			flag, err := store.Get("checkout")
+			require.NoError(t, err)
+			assert.Nil(t, flag)
+
+			require.NoError(t, store.Init([]types.FlagState{
+				{Key: "checkout", Value: true, Enabled: true, Version: 2, FlagType: types.FlagTypeBoolean},
+				{Key: "banner", Value: "hello", Enabled: true, Version: 1, FlagType: types.FlagTypeString},
+			}))
+
+			initialized, err = store.Initialized()
+			require.NoError(t, err)
+			assert.True(t, initialized)
+
+			flag, err = store.Get("checkout")
+			require.NoError(t, err)
+			require.NotNil(t, flag)
+			assert.Equal(t, true, flag.Value)
+
+			// Older versions are ignored
+			require.NoError(t, store.Upsert(types.FlagState{Key: "checkout", Value: false, Version: 1}))
+			flag, _ = store.Get("checkout")
+			assert.Equal(t, true, flag.Value)
+
+			require.NoError(t, store.Upsert(types.FlagState{Key: "checkout", Value: false, Enabled: true, Version: 3}))
+			flag, _ = store.Get("checkout")
+			assert.Equal(t, false, flag.Value)
+
+			require.NoError(t, store.Delete("banner"))
+			flags, err := store.GetAll()
+			require.NoError(t, err)
+			assert.Len(t, flags, 1)
+
+			// Init replaces all flags
+			require.NoError(t, store.Init([]types.FlagState{{Key: "other", Value: 1.0, Version: 1}}))
+			flags, _ = store.GetAll()
+			require.Len(t, flags, 1)
+			assert.Equal(t, "other", flags[0].Key)
+		})
+	}
+}
+
+func TestFileStoreIsSharedAcrossInstances(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+
+	require.NoError(t, NewFileStore(path).Init([]types.FlagState{{Key: "checkout", Value: true, Version: 1}}))
+
+	reader := NewFileStore(path)
+	initialized, err := reader.Initialized()
+	require.NoError(t, err)
+	assert.True(t, initialized)
+
+	flag, err := reader.Get("checkout")
+	require.NoError(t, err)
+	require.NotNil(t, flag)
+	assert.Equal(t, true, flag.Value)
+}
+
+func TestFlagStoresUpsertAll(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, store.Init([]types.FlagState{{Key: "checkout", Value: true, Version: 2}}))
+
+			require.NoError(t, store.UpsertAll([]types.FlagState{
+				{Key: "checkout", Value: false, Version: 1},
+				{Key: "banner", Value: "hello", Version: 1},
+			}))
+
+			// Older versions are ignored
+			flag, err := store.Get("checkout")
+			require.NoError(t, err)
+			require.NotNil(t, flag)
+			assert.Equal(t, true, flag.Value)
+
+			flag, err = store.Get("banner")
+			require.NoError(t, err)
+			require.NotNil(t, flag)
+			assert.Equal(t, "hello", flag.Value)
+
+			initialized, err := store.Initialized()
+			require.NoError(t, err)
+			assert.True(t, initialized)
+		})
+	}
+}
+
+func TestFileStoreReloadsChangedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+	reader := NewFileStore(path)
+
+	flag, err := reader.Get("checkout")
+	require.NoError(t, err)
+	assert.Nil(t, flag)
+
+	// Writes by another instance are seen once the file changes
+	writer := NewFileStore(path)
+	require.NoError(t, writer.Init([]types.FlagState{{Key: "checkout", Value: true, Version: 1}}))
+
+	flag, err = reader.Get("checkout")
+	require.NoError(t, err)
+	require.NotNil(t, flag)
+	assert.Equal(t, true, flag.Value)
+
+	require.NoError(t, writer.UpsertAll([]types.FlagState{{Key: "checkout", Value: false, Version: 2, Enabled: true}}))
+
+	flag, err = reader.Get("checkout")
+	require.NoError(t, err)
+	require.NotNil(t, flag)
+	assert.Equal(t, false, flag.Value)
+}
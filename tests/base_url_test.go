@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/teracrafts/flagkit-go"
+	"github.com/teracrafts/flagkit-go/relay"
+	"github.com/teracrafts/flagkit-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAPI is a stand-in for the FlagKit API serving a single flag.
+type fakeAPI struct {
+	server   *httptest.Server
+	events   atomic.Int32
+	received []map[string]any
+	mu       sync.Mutex
+}
+
+func newFakeAPI(t *testing.T) *fakeAPI {
+	api := &fakeAPI{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/sdk/init", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(&types.InitResponse{
+			Flags: []FlagState{
+				{Key: "checkout", Value: true, Enabled: true, Version: 1, FlagType: FlagTypeBoolean},
+			},
+			Environment:            "test",
+			EnvironmentID:          "env-1",
+			ServerTime:             "2026-01-01T00:00:00Z",
+			PollingIntervalSeconds: 30,
+		})
+	})
+	mux.HandleFunc("/api/v1/sdk/events/batch", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Events []map[string]any `json:"events"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		api.mu.Lock()
+		api.received = append(api.received, body.Events...)
+		api.mu.Unlock()
+		api.events.Add(int32(len(body.Events)))
+		_ = json.NewEncoder(w).Encode(&types.EventsBatchResponse{Success: true, Recorded: len(body.Events)})
+	})
+	api.server = httptest.NewServer(mux)
+	t.Cleanup(api.server.Close)
+	return api
+}
+
+func (a *fakeAPI) baseURL() string {
+	return a.server.URL + "/api/v1"
+}
+
+// receivedEvents returns the events received by the events endpoint.
+func (a *fakeAPI) receivedEvents() []map[string]any {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]map[string]any(nil), a.received...)
+}
+
+func TestClientUsesBaseURL(t *testing.T) {
+	api := newFakeAPI(t)
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithPollingDisabled(),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Initialize())
+	assert.True(t, client.GetBooleanValue("checkout", false))
+
+	client.Track("checkout_clicked", nil)
+	client.Flush()
+	assert.Equal(t, int32(1), api.events.Load())
+}
+
+func TestClientUsesEventsBaseURL(t *testing.T) {
+	api := newFakeAPI(t)
+	eventsAPI := newFakeAPI(t)
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithEventsBaseURL(eventsAPI.baseURL()),
+		WithPollingDisabled(),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Initialize())
+
+	client.Track("checkout_clicked", nil)
+	client.Flush()
+	assert.Equal(t, int32(0), api.events.Load())
+	assert.Equal(t, int32(1), eventsAPI.events.Load())
+}
+
+func TestClientInvalidBaseURL(t *testing.T) {
+	_, err := NewClient("sdk_test_key_12345", WithBaseURL("api.flagkit.dev"))
+	require.Error(t, err)
+
+	var fkErr *FlagKitError
+	require.ErrorAs(t, err, &fkErr)
+	assert.Equal(t, ErrConfigInvalidURL, fkErr.Code)
+}
+
+func TestClientThroughRelay(t *testing.T) {
+	api := newFakeAPI(t)
+
+	r, err := relay.New(relay.Config{
+		Environments: []relay.EnvironmentConfig{{APIKey: "sdk_test_key_12345"}},
+		BaseURL:      api.baseURL(),
+	})
+	require.NoError(t, err)
+	require.NoError(t, r.Start())
+
+	relayServer := httptest.NewServer(r.Handler())
+	defer relayServer.Close()
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(relayServer.URL),
+		WithPollingDisabled(),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Initialize())
+	assert.True(t, client.GetBooleanValue("checkout", false))
+
+	client.Track("checkout_clicked", nil)
+	client.Flush()
+
+	// Closing the relay forwards the buffered events.
+	require.NoError(t, r.Close())
+	assert.Equal(t, int32(1), api.events.Load())
+}
@@ -141,3 +141,44 @@ func TestCircuitBreakerStats(t *testing.T) {
 	assert.Equal(t, 2, stats["failures"])
 	assert.Equal(t, 5, stats["failure_threshold"])
 }
+
+func TestCircuitBreakerOnStateChange(t *testing.T) {
+	var changes []string
+	var cb *CircuitBreaker
+	cb = NewCircuitBreaker(&CircuitBreakerConfig{
+		FailureThreshold:   1,
+		SuccessThreshold:   1,
+		ResetTimeout:       10 * time.Millisecond,
+		HalfOpenMaxAllowed: 1,
+		OnStateChange: func(from, to CircuitState) {
+			// The circuit breaker can be used from the callback
+			assert.Equal(t, to, cb.State())
+			changes = append(changes, from.String()+"->"+to.String())
+		},
+	})
+
+	cb.RecordFailure()
+	time.Sleep(20 * time.Millisecond)
+	assert.True(t, cb.Allow())
+	cb.RecordSuccess()
+
+	assert.Equal(t, []string{"CLOSED->OPEN", "OPEN->HALF_OPEN", "HALF_OPEN->CLOSED"}, changes)
+}
+
+func TestCircuitBreakerOpenFor(t *testing.T) {
+	cb := NewCircuitBreaker(&CircuitBreakerConfig{
+		FailureThreshold: 1,
+		ResetTimeout:     50 * time.Millisecond,
+	})
+	assert.Zero(t, cb.OpenFor())
+
+	cb.RecordFailure()
+	openFor := cb.OpenFor()
+	assert.Greater(t, openFor, time.Duration(0))
+	assert.LessOrEqual(t, openFor, 50*time.Millisecond)
+
+	// Still open until a request is allowed
+	time.Sleep(60 * time.Millisecond)
+	assert.Zero(t, cb.OpenFor())
+	assert.Equal(t, CircuitOpen, cb.State())
+}
@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	. "github.com/teracrafts/flagkit-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func evaluatedFlags(keys ...string) []EvaluatedFlag {
+	flags := make([]EvaluatedFlag, 0, len(keys))
+	for _, key := range keys {
+		flag := EvaluatedFlag{Reason: "TARGETED"}
+		flag.Key = key
+		flag.Value = true
+		flag.Enabled = true
+		flags = append(flags, flag)
+	}
+	return flags
+}
+
+func TestContextCacheGetSet(t *testing.T) {
+	cache := NewContextCache(&ContextCacheConfig{TTL: time.Minute, MaxSize: 10})
+
+	cache.Set("ctx-1", evaluatedFlags("a", "b"))
+
+	flags, ok := cache.Get("ctx-1")
+	require.True(t, ok)
+	assert.Len(t, flags, 2)
+	assert.EqualValues(t, "TARGETED", flags["a"].Reason)
+
+	_, ok = cache.Get("ctx-2")
+	assert.False(t, ok)
+}
+
+func TestContextCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewContextCache(&ContextCacheConfig{TTL: time.Minute, MaxSize: 3})
+
+	for i := 0; i < 3; i++ {
+		cache.Set(fmt.Sprintf("ctx-%d", i), evaluatedFlags("a"))
+	}
+
+	// Touch ctx-0 so that ctx-1 becomes the least recently used
+	_, ok := cache.Get("ctx-0")
+	require.True(t, ok)
+
+	cache.Set("ctx-3", evaluatedFlags("a"))
+
+	assert.Equal(t, 3, cache.Size())
+	_, ok = cache.Get("ctx-1")
+	assert.False(t, ok)
+	_, ok = cache.Get("ctx-0")
+	assert.True(t, ok)
+}
+
+func TestContextCacheExpiresEntries(t *testing.T) {
+	cache := NewContextCache(&ContextCacheConfig{TTL: 10 * time.Millisecond, MaxSize: 10})
+
+	cache.Set("ctx-1", evaluatedFlags("a"))
+	time.Sleep(20 * time.Millisecond)
+
+	_, ok := cache.Get("ctx-1")
+	assert.False(t, ok)
+	assert.Equal(t, 0, cache.Size())
+}
+
+func TestContextCacheClear(t *testing.T) {
+	cache := NewContextCache(nil)
+
+	cache.Set("ctx-1", evaluatedFlags("a"))
+	cache.Clear()
+
+	assert.Equal(t, 0, cache.Size())
+}
+
+func TestContextFingerprint(t *testing.T) {
+	a := NewContext("user-1").WithCountry("US").WithCustom("plan", "pro")
+	b := NewContext("user-1").WithCustom("plan", "pro").WithCountry("US")
+	c := NewContext("user-2").WithCountry("US").WithCustom("plan", "pro")
+	d := NewContext("user-1").WithCountry("US").WithCustom("plan", "pro").WithOS("iOS")
+
+	fingerprint := func(ctx *EvaluationContext) string {
+		f, err := ContextFingerprint(ctx.ToMap())
+		require.NoError(t, err)
+		return f
+	}
+
+	assert.Equal(t, fingerprint(a), fingerprint(b))
+	assert.NotEqual(t, fingerprint(a), fingerprint(c))
+	assert.NotEqual(t, fingerprint(a), fingerprint(d))
+
+	_, err := ContextFingerprint(NewContext("user-1").WithCustom("score", math.NaN()).ToMap())
+	assert.Error(t, err)
+}
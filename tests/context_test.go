@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/teracrafts/flagkit-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSlowServer returns a server that answers every request after a delay.
+func newSlowServer(t *testing.T, delay time.Duration) *httptest.Server {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+		case <-done:
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(func() {
+		close(done)
+		server.Close()
+	})
+	return server
+}
+
+func TestWaitForReadyContext(t *testing.T) {
+	api := newFakeAPI(t)
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithPollingDisabled(),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require.NoError(t, client.WaitForReadyContext(ctx))
+	assert.True(t, client.IsReady())
+	assert.True(t, client.GetBooleanValueContext(ctx, "checkout", false))
+}
+
+func TestWaitForReadyContextTimeout(t *testing.T) {
+	server := newSlowServer(t, 5*time.Second)
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(server.URL),
+		WithPollingDisabled(),
+		WithRetries(1),
+		WithBootstrap(map[string]any{"checkout": true}),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = client.WaitForReadyContext(ctx)
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+
+	var fkErr *FlagKitError
+	require.ErrorAs(t, err, &fkErr)
+	assert.Equal(t, ErrInitTimeout, fkErr.Code)
+
+	// Bootstrap values are served while initialization continues
+	assert.True(t, client.GetBooleanValue("checkout", false))
+}
+
+func TestInitializeContextCanceled(t *testing.T) {
+	server := newSlowServer(t, 5*time.Second)
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(server.URL),
+		WithPollingDisabled(),
+		WithRetries(1),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err = client.InitializeContext(ctx)
+	require.Error(t, err)
+
+	var fkErr *FlagKitError
+	require.ErrorAs(t, err, &fkErr)
+	assert.Equal(t, ErrInitTimeout, fkErr.Code)
+	assert.True(t, client.IsReady())
+}
+
+func TestFlushContext(t *testing.T) {
+	api := newFakeAPI(t)
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithPollingDisabled(),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.InitializeContext(context.Background()))
+
+	client.Track("checkout_clicked", nil)
+	require.NoError(t, client.FlushContext(context.Background()))
+	assert.Equal(t, int32(1), api.events.Load())
+}
+
+func TestCloseContextDeadline(t *testing.T) {
+	api := newFakeAPI(t)
+	eventsServer := newSlowServer(t, 5*time.Second)
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithEventsBaseURL(eventsServer.URL),
+		WithPollingDisabled(),
+		WithRetries(1),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, client.Initialize())
+
+	client.Track("checkout_clicked", nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = client.CloseContext(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/teracrafts/flagkit-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnostics(t *testing.T) {
+	api := newFakeAPI(t)
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithPollingInterval(time.Minute),
+		WithPersistEvents(true),
+		WithEventStoragePath(t.TempDir()),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Initialize())
+	require.NoError(t, client.Track("checkout_clicked", nil))
+
+	diagnostics := client.Diagnostics()
+	assert.Equal(t, InitStatusReadyFromNetwork, diagnostics.Status)
+	assert.True(t, diagnostics.Ready)
+	assert.True(t, diagnostics.Healthy())
+	require.NotNil(t, diagnostics.LastSuccessfulFetch)
+	assert.WithinDuration(t, time.Now(), *diagnostics.LastSuccessfulFetch, 5*time.Second)
+	assert.Empty(t, diagnostics.LastError)
+	assert.Equal(t, "CLOSED", diagnostics.CircuitBreakers[CircuitAPI]["state"])
+	assert.NotContains(t, diagnostics.CircuitBreakers, CircuitEvents)
+	require.NotNil(t, diagnostics.Polling)
+	assert.Equal(t, time.Minute.Milliseconds(), diagnostics.Polling.CurrentIntervalMs)
+	assert.Empty(t, diagnostics.StreamingState)
+	assert.Equal(t, 1, diagnostics.Cache["size"])
+	assert.Equal(t, 1, diagnostics.EventQueueSize)
+	require.NotNil(t, diagnostics.PersistedEvents)
+	assert.Equal(t, 1, *diagnostics.PersistedEvents)
+	assert.Equal(t, "sdk_test", diagnostics.KeyID)
+	assert.False(t, diagnostics.KeyRotation)
+}
+
+func TestDiagnosticsLastError(t *testing.T) {
+	api := newFakeAPI(t)
+	server, _ := newFlakyServer(t, api, http.StatusServiceUnavailable, 100)
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(server.URL+"/api/v1"),
+		WithPollingDisabled(),
+		WithRetries(1),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.Error(t, client.Initialize())
+
+	diagnostics := client.Diagnostics()
+	assert.Equal(t, InitStatusFailed, diagnostics.Status)
+	assert.False(t, diagnostics.Healthy())
+	assert.Nil(t, diagnostics.LastSuccessfulFetch)
+	assert.NotEmpty(t, diagnostics.LastError)
+	assert.NotNil(t, diagnostics.LastErrorTime)
+	assert.Nil(t, diagnostics.Polling)
+	assert.Nil(t, diagnostics.PersistedEvents)
+}
+
+func TestDiagnosticsHandler(t *testing.T) {
+	api := newFakeAPI(t)
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithPollingDisabled(),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	handler := client.DiagnosticsHandler()
+
+	// Not ready until initialized
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	require.NoError(t, client.Initialize())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "READY_FROM_NETWORK", body["status"])
+	assert.Equal(t, true, body["ready"])
+	assert.Contains(t, body, "lastSuccessfulFetch")
+	assert.Contains(t, body, "circuitBreakers")
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/healthz", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
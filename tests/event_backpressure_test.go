@@ -0,0 +1,196 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "github.com/teracrafts/flagkit-go"
+)
+
+// trackAll tracks events of the given types and flushes them.
+func trackAll(t *testing.T, client *Client, eventTypes ...string) {
+	t.Helper()
+	for _, eventType := range eventTypes {
+		require.NoError(t, client.Track(eventType, nil))
+	}
+	client.Flush()
+}
+
+// receivedTypes returns the types of the events received by api.
+func receivedTypes(api *fakeAPI) []string {
+	var eventTypes []string
+	for _, event := range api.receivedEvents() {
+		eventTypes = append(eventTypes, event["type"].(string))
+	}
+	return eventTypes
+}
+
+func TestEventOverflow_DropNewest(t *testing.T) {
+	api := newFakeAPI(t)
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithPollingDisabled(),
+		WithEventQueueSize(3),
+		WithEventBatchSize(100),
+		WithEventFlushInterval(time.Hour),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Initialize())
+
+	trackAll(t, client, "e1", "e2", "e3", "e4", "e5")
+
+	assert.Equal(t, []string{"e1", "e2", "e3"}, receivedTypes(api))
+	assert.Equal(t, map[string]int64{"queue_full": 2}, client.DroppedEvents())
+	assert.Equal(t, map[string]int64{"queue_full": 2}, client.Diagnostics().EventsDropped)
+}
+
+func TestEventOverflow_DropOldest(t *testing.T) {
+	api := newFakeAPI(t)
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithPollingDisabled(),
+		WithEventQueueSize(3),
+		WithEventBatchSize(100),
+		WithEventFlushInterval(time.Hour),
+		WithEventOverflowPolicy(EventOverflowDropOldest),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Initialize())
+
+	trackAll(t, client, "e1", "e2", "e3", "e4", "e5")
+
+	assert.Equal(t, []string{"e3", "e4", "e5"}, receivedTypes(api))
+	assert.Equal(t, map[string]int64{"queue_full": 2}, client.DroppedEvents())
+}
+
+func TestEventOverflow_Spill(t *testing.T) {
+	api := newFakeAPI(t)
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithPollingDisabled(),
+		WithEventQueueSize(2),
+		WithEventBatchSize(100),
+		WithEventFlushInterval(time.Hour),
+		WithEventOverflowPolicy(EventOverflowSpill),
+		WithPersistEvents(true),
+		WithEventStoragePath(t.TempDir()),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Initialize())
+
+	for _, eventType := range []string{"e1", "e2", "e3", "e4", "e5"} {
+		require.NoError(t, client.Track(eventType, nil))
+	}
+	assert.Equal(t, 5, client.Diagnostics().EventQueueSize)
+
+	// Spilled events are read back from disk and sent in order
+	client.Flush()
+	assert.Equal(t, []string{"e1", "e2", "e3", "e4", "e5"}, receivedTypes(api))
+	assert.Empty(t, client.DroppedEvents())
+	assert.Equal(t, 0, client.Diagnostics().EventQueueSize)
+}
+
+func TestEventOverflow_SpillWithoutPersistence(t *testing.T) {
+	eq := NewEventQueue(&EventQueueOptions{
+		SessionID:  "test-session",
+		SDKVersion: "1.0.0",
+		Logger:     &NullLogger{},
+		Config: &EventQueueConfig{
+			MaxSize:       1,
+			FlushInterval: time.Hour,
+			BatchSize:     10,
+			Overflow:      EventOverflowSpill,
+		},
+	})
+
+	eq.Track("e1", nil)
+	eq.Track("e2", nil)
+
+	assert.Equal(t, 1, eq.QueueSize())
+	assert.Equal(t, map[string]int64{"queue_full": 1}, eq.DroppedEvents())
+}
+
+func TestEventOverflow_Block(t *testing.T) {
+	api := newFakeAPI(t)
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithPollingDisabled(),
+		WithEventQueueSize(2),
+		WithEventBatchSize(100),
+		WithEventFlushInterval(time.Hour),
+		WithEventOverflowPolicy(EventOverflowBlock),
+		WithEventBlockTimeout(5*time.Second),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Initialize())
+
+	// Tracking into a full queue waits for the queue to be flushed
+	trackAll(t, client, "e1", "e2", "e3")
+
+	assert.Equal(t, []string{"e1", "e2", "e3"}, receivedTypes(api))
+	assert.Empty(t, client.DroppedEvents())
+}
+
+func TestEventOverflow_BlockNotRunning(t *testing.T) {
+	eq := NewEventQueue(&EventQueueOptions{
+		SessionID:  "test-session",
+		SDKVersion: "1.0.0",
+		Logger:     &NullLogger{},
+		Config: &EventQueueConfig{
+			MaxSize:       1,
+			FlushInterval: time.Hour,
+			BatchSize:     10,
+			Overflow:      EventOverflowBlock,
+			BlockTimeout:  50 * time.Millisecond,
+		},
+	})
+
+	// A queue that is not running drops events instead of waiting
+	eq.Track("e1", nil)
+	start := time.Now()
+	eq.Track("e2", nil)
+
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+	assert.Equal(t, 1, eq.QueueSize())
+	assert.Equal(t, map[string]int64{"queue_full": 1}, eq.DroppedEvents())
+}
+
+func TestEventQueueHighWaterMark(t *testing.T) {
+	var marks []int
+	eq := NewEventQueue(&EventQueueOptions{
+		SessionID:  "test-session",
+		SDKVersion: "1.0.0",
+		Logger:     &NullLogger{},
+		Config: &EventQueueConfig{
+			MaxSize:       10,
+			FlushInterval: time.Hour,
+			BatchSize:     10,
+			HighWaterMark: 3,
+		},
+		OnHighWater: func(queued int) {
+			marks = append(marks, queued)
+		},
+	})
+
+	for i := 0; i < 5; i++ {
+		eq.Track("event", nil)
+	}
+	assert.Equal(t, []int{3}, marks)
+
+	// The callback is re-armed once the queue is flushed below the mark
+	eq.Flush()
+	for i := 0; i < 3; i++ {
+		eq.Track("event", nil)
+	}
+	assert.Equal(t, []int{3, 3}, marks)
+}
@@ -225,6 +225,44 @@ func TestEventPersistence_MarkSent(t *testing.T) {
 	assert.Equal(t, "evt_sent2", recovered[0].ID)
 }
 
+func TestEventPersistence_Backlog(t *testing.T) {
+	tempDir := t.TempDir()
+
+	ep, err := NewEventPersistence(tempDir, 10000, time.Second, &NullLogger{})
+	require.NoError(t, err)
+	defer func() { _ = ep.Close() }()
+
+	for _, id := range []string{"evt_1", "evt_2", "evt_3"} {
+		_ = ep.Persist(PersistedEvent{ID: id, Type: "test.event", Status: EventStatusPending})
+	}
+	_ = ep.Flush()
+	require.NoError(t, ep.MarkSent([]string{"evt_1"}))
+
+	// Buffered events count towards the backlog
+	_ = ep.Persist(PersistedEvent{ID: "evt_4", Type: "test.event", Status: EventStatusPending})
+
+	backlog, err := ep.Backlog()
+	require.NoError(t, err)
+	assert.Equal(t, 3, backlog)
+
+	require.NoError(t, ep.MarkFailed([]string{"evt_2"}))
+	require.NoError(t, ep.MarkDeadLetter([]string{"evt_3"}))
+	require.NoError(t, ep.Flush())
+
+	backlog, err = ep.Backlog()
+	require.NoError(t, err)
+	assert.Equal(t, 2, backlog)
+
+	// Unsent events left by a previous run are counted on startup
+	restarted, err := NewEventPersistence(tempDir, 10000, time.Second, &NullLogger{})
+	require.NoError(t, err)
+	defer func() { _ = restarted.Close() }()
+
+	backlog, err = restarted.Backlog()
+	require.NoError(t, err)
+	assert.Equal(t, 2, backlog)
+}
+
 func TestEventPersistence_Cleanup(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -444,10 +482,55 @@ func TestEventPersistence_MarkFailed(t *testing.T) {
 	err = ep.MarkFailed([]string{"evt_fail1"})
 	require.NoError(t, err)
 
-	// Recover - failed events should not be recovered
+	// Recover - failed events are recovered for redelivery
+	recovered, err := ep.Recover()
+	require.NoError(t, err)
+	require.Len(t, recovered, 1)
+	assert.Equal(t, EventStatusFailed, recovered[0].Status)
+	assert.Equal(t, "test.event", recovered[0].Type)
+	assert.Equal(t, 1, recovered[0].Attempts)
+
+	// Each failure counts as an attempt
+	err = ep.MarkFailed([]string{"evt_fail1"})
+	require.NoError(t, err)
+	recovered, err = ep.Recover()
+	require.NoError(t, err)
+	require.Len(t, recovered, 1)
+	assert.Equal(t, 2, recovered[0].Attempts)
+}
+
+func TestEventPersistence_MarkDeadLetter(t *testing.T) {
+	tempDir := t.TempDir()
+
+	ep, err := NewEventPersistence(tempDir, 10000, time.Second, &NullLogger{})
+	require.NoError(t, err)
+	defer func() { _ = ep.Close() }()
+
+	for _, id := range []string{"evt_dead1", "evt_sent1"} {
+		err = ep.Persist(PersistedEvent{ID: id, Type: "test.event"})
+		require.NoError(t, err)
+	}
+	require.NoError(t, ep.Flush())
+
+	require.NoError(t, ep.MarkFailed([]string{"evt_dead1"}))
+	require.NoError(t, ep.MarkDeadLetter([]string{"evt_dead1"}))
+	require.NoError(t, ep.MarkSent([]string{"evt_sent1"}))
+
+	// Dead-lettered events are not recovered
 	recovered, err := ep.Recover()
 	require.NoError(t, err)
 	assert.Empty(t, recovered)
+
+	// Cleanup keeps dead-lettered events and drops sent ones
+	require.NoError(t, ep.Cleanup())
+	files, err := filepath.Glob(filepath.Join(tempDir, "flagkit-events-*.jsonl"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	content, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"id":"evt_dead1"`)
+	assert.Contains(t, string(content), `"status":"dead_letter"`)
+	assert.NotContains(t, string(content), "evt_sent1")
 }
 
 func TestEventPersisterAdapter(t *testing.T) {
@@ -505,3 +588,358 @@ func TestWithPersistenceFlushIntervalOption(t *testing.T) {
 
 	assert.Equal(t, 2*time.Second, opts.PersistenceFlushInterval)
 }
+
+func TestEventPersistence_CompressedSegments(t *testing.T) {
+	for _, tc := range []struct {
+		compression EventCompression
+		ext         string
+	}{
+		{EventCompressionGzip, ".jsonl.gz"},
+		{EventCompressionZstd, ".jsonl.zst"},
+	} {
+		t.Run(string(tc.compression), func(t *testing.T) {
+			tempDir := t.TempDir()
+
+			ep, err := NewEventPersistenceWithConfig(&EventPersistenceConfig{
+				StoragePath:    tempDir,
+				Compression:    tc.compression,
+				MaxSegmentSize: 256,
+			})
+			require.NoError(t, err)
+			defer func() { _ = ep.Close() }()
+
+			for i := 0; i < 10; i++ {
+				require.NoError(t, ep.Persist(PersistedEvent{Type: "test.event", Data: map[string]any{"index": i}}))
+				require.NoError(t, ep.Flush())
+				require.NoError(t, ep.Maintain())
+			}
+
+			compressed, err := filepath.Glob(filepath.Join(tempDir, "flagkit-events-*"+tc.ext))
+			require.NoError(t, err)
+			assert.NotEmpty(t, compressed)
+
+			recovered, err := ep.Recover()
+			require.NoError(t, err)
+			assert.Len(t, recovered, 10)
+		})
+	}
+}
+
+func TestEventPersistence_DiskQuota(t *testing.T) {
+	tempDir := t.TempDir()
+
+	var dropped int
+	ep, err := NewEventPersistenceWithConfig(&EventPersistenceConfig{
+		StoragePath:    tempDir,
+		MaxSegmentSize: 256,
+		MaxDiskUsage:   1024,
+		OnDrop:         func(count int) { dropped += count },
+	})
+	require.NoError(t, err)
+	defer func() { _ = ep.Close() }()
+
+	// Sent events are compacted away before segments are removed
+	var sent []string
+	for i := 0; i < 10; i++ {
+		id := GenerateEventID()
+		sent = append(sent, id)
+		require.NoError(t, ep.Persist(PersistedEvent{ID: id, Type: "test.event"}))
+		require.NoError(t, ep.Flush())
+		require.NoError(t, ep.Maintain())
+	}
+	require.NoError(t, ep.MarkSent(sent))
+	require.NoError(t, ep.Persist(PersistedEvent{ID: "evt_kept", Type: "test.event"}))
+	require.NoError(t, ep.Flush())
+	require.NoError(t, ep.Maintain())
+
+	for i := 0; i < 40; i++ {
+		require.NoError(t, ep.Persist(PersistedEvent{Type: "test.event"}))
+		require.NoError(t, ep.Flush())
+		require.NoError(t, ep.Maintain())
+	}
+
+	files, err := filepath.Glob(filepath.Join(tempDir, "flagkit-events-*.jsonl"))
+	require.NoError(t, err)
+	var size int64
+	for _, file := range files {
+		info, err := os.Stat(file)
+		require.NoError(t, err)
+		size += info.Size()
+	}
+	assert.LessOrEqual(t, size, int64(1024+256))
+
+	// The oldest unsent events were removed to stay within the quota
+	recovered, err := ep.Recover()
+	require.NoError(t, err)
+	assert.NotEmpty(t, recovered)
+	assert.Less(t, len(recovered), 41)
+	assert.Equal(t, 41-len(recovered), dropped)
+}
+
+func TestEventPersistence_Namespace(t *testing.T) {
+	tempDir := t.TempDir()
+
+	newPersistence := func(namespace string) *EventPersistence {
+		ep, err := NewEventPersistenceWithConfig(&EventPersistenceConfig{
+			StoragePath: tempDir,
+			Namespace:   namespace,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = ep.Close() })
+		return ep
+	}
+
+	first := newPersistence("first")
+	second := newPersistence("second")
+	legacy := newPersistence("")
+
+	require.NoError(t, first.Persist(PersistedEvent{ID: "evt_first", Type: "test.event"}))
+	require.NoError(t, first.Flush())
+	require.NoError(t, second.Persist(PersistedEvent{ID: "evt_second", Type: "test.event"}))
+	require.NoError(t, second.Flush())
+	require.NoError(t, second.MarkSent([]string{"evt_second"}))
+
+	// Compacting one namespace leaves the others alone
+	require.NoError(t, second.Cleanup())
+	require.NoError(t, legacy.Cleanup())
+
+	recovered, err := first.Recover()
+	require.NoError(t, err)
+	require.Len(t, recovered, 1)
+	assert.Equal(t, "evt_first", recovered[0].ID)
+
+	recovered, err = legacy.Recover()
+	require.NoError(t, err)
+	assert.Empty(t, recovered)
+
+	_, err = NewEventPersistenceWithConfig(&EventPersistenceConfig{StoragePath: tempDir, Namespace: "../x"})
+	assert.Error(t, err)
+}
+
+func TestEventPersistence_AdoptDefaultNamespace(t *testing.T) {
+	tempDir := t.TempDir()
+
+	legacy, err := NewEventPersistence(tempDir, 10000, time.Second, &NullLogger{})
+	require.NoError(t, err)
+	require.NoError(t, legacy.Persist(PersistedEvent{ID: "evt_legacy", Type: "test.event"}))
+	require.NoError(t, legacy.Close())
+
+	newPersistence := func(namespace string, adopt bool) *EventPersistence {
+		ep, err := NewEventPersistenceWithConfig(&EventPersistenceConfig{
+			StoragePath:           tempDir,
+			Namespace:             namespace,
+			AdoptDefaultNamespace: adopt,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = ep.Close() })
+		return ep
+	}
+
+	// Without adopting, the default namespace is left alone
+	recovered, err := newPersistence("other", false).Recover()
+	require.NoError(t, err)
+	assert.Empty(t, recovered)
+
+	recovered, err = newPersistence("current", true).Recover()
+	require.NoError(t, err)
+	require.Len(t, recovered, 1)
+	assert.Equal(t, "evt_legacy", recovered[0].ID)
+
+	recovered, err = newPersistence("", false).Recover()
+	require.NoError(t, err)
+	assert.Empty(t, recovered)
+}
+
+func TestEventPersistence_CleanupKeepsUnreadableSegments(t *testing.T) {
+	tempDir := t.TempDir()
+
+	ep, err := NewEventPersistence(tempDir, 10000, time.Second, &NullLogger{})
+	require.NoError(t, err)
+	defer func() { _ = ep.Close() }()
+
+	require.NoError(t, ep.Persist(PersistedEvent{ID: "evt_sent", Type: "test.event"}))
+	require.NoError(t, ep.Flush())
+	require.NoError(t, ep.MarkSent([]string{"evt_sent"}))
+
+	// A segment written with another encryption key cannot be decoded
+	unreadable := filepath.Join(tempDir, "flagkit-events-1-abcdef01.jsonl")
+	require.NoError(t, os.WriteFile(unreadable, []byte("not an event\n"), 0600))
+
+	require.NoError(t, ep.Cleanup())
+
+	_, err = os.Stat(unreadable)
+	assert.NoError(t, err)
+}
+
+func TestEventPersistence_FlushDoesNotRotate(t *testing.T) {
+	tempDir := t.TempDir()
+
+	ep, err := NewEventPersistenceWithConfig(&EventPersistenceConfig{
+		StoragePath:    tempDir,
+		Compression:    EventCompressionGzip,
+		MaxSegmentSize: 256,
+	})
+	require.NoError(t, err)
+	defer func() { _ = ep.Close() }()
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, ep.Persist(PersistedEvent{Type: "test.event"}))
+		require.NoError(t, ep.Flush())
+	}
+
+	// Rotation and compression are left to the background maintenance
+	files, err := filepath.Glob(filepath.Join(tempDir, "flagkit-events-*"))
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	require.NoError(t, ep.Maintain())
+	require.NoError(t, ep.Persist(PersistedEvent{Type: "test.event"}))
+	require.NoError(t, ep.Flush())
+	require.NoError(t, ep.Maintain())
+
+	compressed, err := filepath.Glob(filepath.Join(tempDir, "flagkit-events-*.jsonl.gz"))
+	require.NoError(t, err)
+	assert.Len(t, compressed, 1)
+
+	recovered, err := ep.Recover()
+	require.NoError(t, err)
+	assert.Len(t, recovered, 11)
+}
+
+func TestEventPersistence_DeadLetterRetention(t *testing.T) {
+	tempDir := t.TempDir()
+
+	ep, err := NewEventPersistenceWithConfig(&EventPersistenceConfig{
+		StoragePath:         tempDir,
+		DeadLetterRetention: time.Hour,
+		MaxDeadLetters:      2,
+	})
+	require.NoError(t, err)
+	defer func() { _ = ep.Close() }()
+
+	now := time.Now()
+	events := []PersistedEvent{
+		{ID: "evt_expired", Type: "test.event", Timestamp: now.Add(-2 * time.Hour).UnixMilli()},
+		{ID: "evt_oldest", Type: "test.event", Timestamp: now.Add(-3 * time.Minute).UnixMilli()},
+		{ID: "evt_older", Type: "test.event", Timestamp: now.Add(-2 * time.Minute).UnixMilli()},
+		{ID: "evt_newest", Type: "test.event", Timestamp: now.Add(-time.Minute).UnixMilli()},
+	}
+	var ids []string
+	for _, e := range events {
+		require.NoError(t, ep.Persist(e))
+		ids = append(ids, e.ID)
+	}
+	require.NoError(t, ep.MarkDeadLetter(ids))
+	require.NoError(t, ep.Cleanup())
+
+	// Only the most recent unexpired dead-lettered events are kept
+	files, err := filepath.Glob(filepath.Join(tempDir, "flagkit-events-*.jsonl"))
+	require.NoError(t, err)
+	var content string
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		content += string(data)
+	}
+	assert.NotContains(t, content, "evt_expired")
+	assert.NotContains(t, content, "evt_oldest")
+	assert.Contains(t, content, "evt_older")
+	assert.Contains(t, content, "evt_newest")
+}
+
+func TestEventPersistence_RecoverSendingKeepsAttempts(t *testing.T) {
+	tempDir := t.TempDir()
+
+	ep, err := NewEventPersistence(tempDir, 10000, time.Second, &NullLogger{})
+	require.NoError(t, err)
+	defer func() { _ = ep.Close() }()
+
+	require.NoError(t, ep.Persist(PersistedEvent{ID: "evt_retried", Type: "test.event"}))
+	require.NoError(t, ep.MarkFailed([]string{"evt_retried"}))
+	require.NoError(t, ep.MarkFailed([]string{"evt_retried"}))
+	// Rejected by an open circuit breaker while being resent
+	require.NoError(t, ep.MarkSending([]string{"evt_retried"}))
+
+	recovered, err := ep.Recover()
+	require.NoError(t, err)
+	require.Len(t, recovered, 1)
+	assert.Equal(t, EventStatusPending, recovered[0].Status)
+	assert.Equal(t, 2, recovered[0].Attempts)
+}
+
+func TestEventPersistence_Spill(t *testing.T) {
+	tempDir := t.TempDir()
+
+	ep, err := NewEventPersistence(tempDir, 10000, time.Second, &NullLogger{})
+	require.NoError(t, err)
+	defer func() { _ = ep.Close() }()
+
+	for _, id := range []string{"evt_1", "evt_2", "evt_3"} {
+		require.NoError(t, ep.Spill(PersistedEvent{ID: id, Type: "test.event"}))
+	}
+
+	// Spilled events are read back in order, continuing where reading stopped
+	unspilled, err := ep.Unspill(2)
+	require.NoError(t, err)
+	require.Len(t, unspilled, 2)
+	assert.Equal(t, "evt_1", unspilled[0].ID)
+	assert.Equal(t, "evt_2", unspilled[1].ID)
+
+	unspilled, err = ep.Unspill(5)
+	require.NoError(t, err)
+	require.Len(t, unspilled, 1)
+	assert.Equal(t, "evt_3", unspilled[0].ID)
+
+	unspilled, err = ep.Unspill(5)
+	require.NoError(t, err)
+	assert.Empty(t, unspilled)
+
+	// Events read back are moved to the event log
+	require.NoError(t, ep.MarkSent([]string{"evt_1"}))
+	recovered, err := ep.Recover()
+	require.NoError(t, err)
+	assert.Len(t, recovered, 2)
+
+	backlog, err := ep.Backlog()
+	require.NoError(t, err)
+	assert.Equal(t, 2, backlog)
+}
+
+func TestEventPersistence_AdoptsSpilledEvents(t *testing.T) {
+	tempDir := t.TempDir()
+
+	ep, err := NewEventPersistence(tempDir, 10000, time.Second, &NullLogger{})
+	require.NoError(t, err)
+
+	require.NoError(t, ep.Spill(PersistedEvent{ID: "evt_read", Type: "test.event"}))
+	require.NoError(t, ep.Spill(PersistedEvent{ID: "evt_unread", Type: "test.event"}))
+	_, err = ep.Unspill(1)
+	require.NoError(t, err)
+
+	// The spill file of a running instance is left alone
+	other, err := NewEventPersistence(tempDir, 10000, time.Second, &NullLogger{})
+	require.NoError(t, err)
+	require.NoError(t, other.Close())
+	spills, err := filepath.Glob(filepath.Join(tempDir, "flagkit-events-spill-*"))
+	require.NoError(t, err)
+	assert.Len(t, spills, 1)
+
+	require.NoError(t, ep.Close())
+
+	// The next instance moves the unread events to the event log
+	ep, err = NewEventPersistence(tempDir, 10000, time.Second, &NullLogger{})
+	require.NoError(t, err)
+	defer func() { _ = ep.Close() }()
+
+	spills, err = filepath.Glob(filepath.Join(tempDir, "flagkit-events-spill-*"))
+	require.NoError(t, err)
+	assert.Empty(t, spills)
+
+	recovered, err := ep.Recover()
+	require.NoError(t, err)
+	var ids []string
+	for _, event := range recovered {
+		ids = append(ids, event.ID)
+	}
+	assert.ElementsMatch(t, []string{"evt_read", "evt_unread"}, ids)
+}
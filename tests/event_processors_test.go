@@ -0,0 +1,48 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "github.com/teracrafts/flagkit-go"
+	"github.com/teracrafts/flagkit-go/events"
+)
+
+func TestEventProcessors(t *testing.T) {
+	api := newFakeAPI(t)
+
+	var seen []string
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithPollingDisabled(),
+		WithEventProcessors(
+			EventProcessorFunc(func(event *AnalyticsEvent) bool {
+				seen = append(seen, event.Type)
+				return true
+			}),
+			events.Deny("debug.*"),
+			events.Sample(1, map[string]float64{"page.viewed": 0}),
+			events.RedactPII(),
+		),
+		WithEventProcessors(events.Deployment("checkout", "eu-west-1", "1.4.2")),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Initialize())
+	require.NoError(t, client.Track("debug.trace", nil))
+	require.NoError(t, client.Track("page.viewed", nil))
+	require.NoError(t, client.TrackMetric("purchase", 42, map[string]any{"amount": 42, "password": "hunter2"}))
+	client.Flush()
+
+	assert.Equal(t, []string{"debug.trace", "page.viewed", "purchase"}, seen)
+
+	received := api.receivedEvents()
+	require.Len(t, received, 1)
+	assert.Equal(t, "purchase", received[0]["type"])
+	assert.Equal(t, map[string]any{"amount": 42.0, "password": events.Redacted}, received[0]["data"])
+	assert.Equal(t, 42.0, received[0]["metricValue"])
+	assert.Equal(t, map[string]any{"service": "checkout", "region": "eu-west-1", "version": "1.4.2"}, received[0]["metadata"])
+}
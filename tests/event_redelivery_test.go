@@ -0,0 +1,212 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "github.com/teracrafts/flagkit-go"
+)
+
+func TestEventRedelivery(t *testing.T) {
+	api := newFakeAPI(t)
+	eventsServer, requests := newFlakyServer(t, api, http.StatusServiceUnavailable, 2)
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithEventsBaseURL(eventsServer.URL+"/api/v1"),
+		WithPollingDisabled(),
+		WithRetries(1),
+		WithEventRedeliveryPolicy(&RetryConfig{BaseDelay: 10 * time.Millisecond, MaxDelay: 20 * time.Millisecond}),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Initialize())
+	require.NoError(t, client.Track("checkout_clicked", nil))
+	require.NoError(t, client.Track("checkout_completed", nil))
+	require.Error(t, client.FlushContext(context.Background()))
+
+	// The failed batch is resent in the background until it succeeds
+	assert.Eventually(t, func() bool {
+		return api.events.Load() == 2
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestEventRedelivery_WaitsForCircuitBreaker(t *testing.T) {
+	api := newFakeAPI(t)
+	eventsServer, requests := newFlakyServer(t, api, http.StatusServiceUnavailable, 1)
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithEventsBaseURL(eventsServer.URL+"/api/v1"),
+		WithPollingDisabled(),
+		WithRetries(1),
+		WithEventsCircuitBreaker(&CircuitBreakerConfig{FailureThreshold: 1, ResetTimeout: 300 * time.Millisecond}),
+		WithEventRedeliveryPolicy(&RetryConfig{MaxAttempts: 2, BaseDelay: 10 * time.Millisecond}),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Initialize())
+	require.NoError(t, client.Track("checkout_clicked", nil))
+	require.Error(t, client.FlushContext(context.Background()))
+
+	// No redelivery while the circuit is open
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, int32(1), requests.Load())
+	assert.Equal(t, int32(0), api.events.Load())
+
+	assert.Eventually(t, func() bool {
+		return api.events.Load() == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestEventRedelivery_DeadLetter(t *testing.T) {
+	api := newFakeAPI(t)
+	eventsServer, requests := newFlakyServer(t, api, http.StatusServiceUnavailable, 100)
+	recorder := newMetricsRecorder()
+	storagePath := t.TempDir()
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithEventsBaseURL(eventsServer.URL+"/api/v1"),
+		WithPollingDisabled(),
+		WithRetries(1),
+		WithPersistEvents(true),
+		WithEventStoragePath(storagePath),
+		WithEventRedeliveryPolicy(&RetryConfig{MaxAttempts: 3, BaseDelay: 10 * time.Millisecond, MaxDelay: 20 * time.Millisecond}),
+		WithMetrics(recorder),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Initialize())
+	require.NoError(t, client.Track("checkout_clicked", nil))
+	require.NoError(t, client.Track("checkout_completed", nil))
+	require.Error(t, client.FlushContext(context.Background()))
+
+	assert.Eventually(t, func() bool {
+		recorder.mu.Lock()
+		defer recorder.mu.Unlock()
+		return recorder.dropped["send_failed"] == 2
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(3), requests.Load())
+
+	require.NoError(t, client.Close())
+
+	// Dead-lettered events are not recovered by a new client
+	client, err = NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithPollingDisabled(),
+		WithPersistEvents(true),
+		WithEventStoragePath(storagePath),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Initialize())
+	require.NoError(t, client.FlushContext(context.Background()))
+	assert.Equal(t, int32(0), api.events.Load())
+}
+
+func TestEventRedelivery_RecoversFailedEvents(t *testing.T) {
+	api := newFakeAPI(t)
+	eventsServer, _ := newFlakyServer(t, api, http.StatusServiceUnavailable, 100)
+	storagePath := t.TempDir()
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithEventsBaseURL(eventsServer.URL+"/api/v1"),
+		WithPollingDisabled(),
+		WithRetries(1),
+		WithPersistEvents(true),
+		WithEventStoragePath(storagePath),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, client.Initialize())
+	require.NoError(t, client.Track("checkout_clicked", nil))
+	require.Error(t, client.FlushContext(context.Background()))
+	require.NoError(t, client.Close())
+
+	// A new client resends the failed events
+	client, err = NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithPollingDisabled(),
+		WithPersistEvents(true),
+		WithEventStoragePath(storagePath),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Initialize())
+	require.NoError(t, client.FlushContext(context.Background()))
+	assert.Equal(t, int32(1), api.events.Load())
+}
+
+func TestEventPersistence_Encrypted(t *testing.T) {
+	api := newFakeAPI(t)
+	eventsServer, _ := newFlakyServer(t, api, http.StatusServiceUnavailable, 100)
+	storagePath := t.TempDir()
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithEventsBaseURL(eventsServer.URL+"/api/v1"),
+		WithPollingDisabled(),
+		WithRetries(1),
+		WithPersistEvents(true),
+		WithEventStoragePath(storagePath),
+		WithEventEncryption(),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, client.Initialize())
+	require.NoError(t, client.Track("checkout_clicked", map[string]any{"plan": "enterprise"}))
+	require.Error(t, client.FlushContext(context.Background()))
+	require.NoError(t, client.Close())
+
+	// Records are encrypted on disk
+	files, err := filepath.Glob(filepath.Join(storagePath, "flagkit-events-*.jsonl"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.NotContains(t, string(content), "checkout_clicked")
+		assert.NotContains(t, string(content), "enterprise")
+	}
+
+	// A client with the same API key decrypts and resends them
+	client, err = NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithPollingDisabled(),
+		WithPersistEvents(true),
+		WithEventStoragePath(storagePath),
+		WithEventEncryption(),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Initialize())
+	require.NoError(t, client.FlushContext(context.Background()))
+	events := api.receivedEvents()
+	require.Len(t, events, 1)
+	assert.Equal(t, "checkout_clicked", events[0]["type"])
+	assert.Equal(t, map[string]any{"plan": "enterprise"}, events[0]["data"])
+}
@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"testing"
+
+	. "github.com/teracrafts/flagkit-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func newImpressionQueue() *EventQueue {
+	return NewEventQueue(&EventQueueOptions{
+		SessionID:  "test-session",
+		SDKVersion: "1.0.0",
+		Logger:     &NullLogger{},
+		Config: &EventQueueConfig{
+			MaxSize:       100,
+			FlushInterval: DefaultEventQueueConfig().FlushInterval,
+			BatchSize:     100,
+		},
+	})
+}
+
+func TestImpressionTrackerDeduplicatesPerContextFlagVersion(t *testing.T) {
+	eq := newImpressionQueue()
+	it := NewImpressionTracker(eq, nil, &NullLogger{})
+
+	imp := Impression{FlagKey: "checkout", Value: true, Version: 3, Reason: "TARGETED", ContextKey: "user-1"}
+	it.Record(imp)
+	it.Record(imp)
+	it.Record(imp)
+	assert.Equal(t, 1, eq.QueueSize())
+
+	// A different context, or a new flag version, is a new impression
+	it.Record(Impression{FlagKey: "checkout", Value: true, Version: 3, Reason: "TARGETED", ContextKey: "user-2"})
+	it.Record(Impression{FlagKey: "checkout", Value: false, Version: 4, Reason: "TARGETED", ContextKey: "user-1"})
+	assert.Equal(t, 3, eq.QueueSize())
+}
+
+func TestImpressionTrackerFlushEmitsSummary(t *testing.T) {
+	eq := newImpressionQueue()
+	it := NewImpressionTracker(eq, nil, &NullLogger{})
+
+	for i := 0; i < 50; i++ {
+		it.Record(Impression{FlagKey: "checkout", Value: true, Version: 1, Reason: "CACHED", ContextKey: "user-1"})
+	}
+	assert.Equal(t, 1, eq.QueueSize())
+
+	it.Flush()
+	assert.Equal(t, 2, eq.QueueSize())
+
+	// Nothing to summarize after a flush
+	it.Flush()
+	assert.Equal(t, 2, eq.QueueSize())
+}
+
+func TestImpressionTrackerLRUEviction(t *testing.T) {
+	eq := newImpressionQueue()
+	it := NewImpressionTracker(eq, &ImpressionTrackerConfig{MaxContexts: 1}, &NullLogger{})
+
+	it.Record(Impression{FlagKey: "a", Version: 1, ContextKey: "user-1"})
+	it.Record(Impression{FlagKey: "b", Version: 1, ContextKey: "user-1"})
+	it.Record(Impression{FlagKey: "a", Version: 1, ContextKey: "user-1"})
+
+	assert.Equal(t, 3, eq.QueueSize())
+}
+
+func TestImpressionTrackerStopFlushesSummary(t *testing.T) {
+	eq := newImpressionQueue()
+	it := NewImpressionTracker(eq, nil, &NullLogger{})
+	it.Start()
+
+	it.Record(Impression{FlagKey: "a", Value: "x", Version: 1, ContextKey: "user-1"})
+	it.Stop()
+
+	assert.Equal(t, 2, eq.QueueSize())
+}
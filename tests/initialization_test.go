@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/teracrafts/flagkit-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFlakyServer returns a server that answers the first failures requests
+// with the given status and forwards the rest to the fake API.
+func newFlakyServer(t *testing.T, api *fakeAPI, status int, failures int32) (*httptest.Server, *atomic.Int32) {
+	target, err := url.Parse(api.server.URL)
+	require.NoError(t, err)
+	proxy := httputil.NewSingleHostReverseProxy(target)
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		proxy.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestBackgroundInitialization(t *testing.T) {
+	api := newFakeAPI(t)
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithPollingDisabled(),
+		WithStartWaitTimeout(0),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	select {
+	case <-client.Ready():
+	case <-time.After(5 * time.Second):
+		t.Fatal("client not ready")
+	}
+
+	assert.Equal(t, InitStatusReadyFromNetwork, client.InitializationStatus())
+	assert.True(t, client.GetBooleanValue("checkout", false))
+
+	// Initialize waits for the background initialization
+	require.NoError(t, client.Initialize())
+}
+
+func TestStartWaitTimeout(t *testing.T) {
+	api := newFakeAPI(t)
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithPollingDisabled(),
+		WithStartWaitTimeout(5*time.Second),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	assert.True(t, client.IsReady())
+	assert.Equal(t, InitStatusReadyFromNetwork, client.InitializationStatus())
+}
+
+func TestStartWaitTimeoutExpires(t *testing.T) {
+	server := newSlowServer(t, 5*time.Second)
+
+	start := time.Now()
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(server.URL),
+		WithPollingDisabled(),
+		WithRetries(1),
+		WithStartWaitTimeout(100*time.Millisecond),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.False(t, client.IsReady())
+	assert.Equal(t, InitStatusInitializing, client.InitializationStatus())
+}
+
+func TestInitializationRetry(t *testing.T) {
+	api := newFakeAPI(t)
+	server, requests := newFlakyServer(t, api, http.StatusServiceUnavailable, 1)
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(server.URL+"/api/v1"),
+		WithPollingDisabled(),
+		WithRetries(1),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.Error(t, client.Initialize())
+	assert.True(t, client.IsReady())
+	assert.Equal(t, InitStatusFailed, client.InitializationStatus())
+	assert.False(t, client.GetBooleanValue("checkout", false))
+
+	require.Eventually(t, func() bool {
+		return client.InitializationStatus() == InitStatusReadyFromNetwork
+	}, 5*time.Second, 20*time.Millisecond)
+	assert.True(t, client.GetBooleanValue("checkout", false))
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestInitializationAuthErrorNotRetried(t *testing.T) {
+	api := newFakeAPI(t)
+	server, requests := newFlakyServer(t, api, http.StatusUnauthorized, 100)
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(server.URL+"/api/v1"),
+		WithPollingDisabled(),
+		WithBootstrap(map[string]any{"checkout": true}),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.Error(t, client.Initialize())
+	assert.Equal(t, InitStatusReadyFromCache, client.InitializationStatus())
+
+	time.Sleep(1500 * time.Millisecond)
+	assert.Equal(t, int32(1), requests.Load())
+}
@@ -0,0 +1,223 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "github.com/teracrafts/flagkit-go"
+)
+
+// metricsRecorder is a Metrics hook that records measurements.
+type metricsRecorder struct {
+	evaluations map[string]int
+	cache       map[CacheResult]int
+	requests    map[string]int
+	retries     int
+	circuits    []string
+	dropped     map[string]int
+	onDropped   func()
+	observe     func() EventStats
+	mu          sync.Mutex
+}
+
+func newMetricsRecorder() *metricsRecorder {
+	return &metricsRecorder{
+		evaluations: make(map[string]int),
+		cache:       make(map[CacheResult]int),
+		requests:    make(map[string]int),
+		dropped:     make(map[string]int),
+	}
+}
+
+func (r *metricsRecorder) RecordEvaluation(flagKey string, reason EvaluationReason, duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.evaluations[flagKey+":"+string(reason)]++
+}
+
+func (r *metricsRecorder) RecordCacheLookup(result CacheResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cache[result]++
+}
+
+func (r *metricsRecorder) RecordRequest(endpoint string, statusCode int, duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests[endpoint+":"+http.StatusText(statusCode)]++
+}
+
+func (r *metricsRecorder) RecordRetry(endpoint string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.retries++
+}
+
+func (r *metricsRecorder) RecordCircuitStateChange(name, from, to string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.circuits = append(r.circuits, name+":"+from+"->"+to)
+}
+
+func (r *metricsRecorder) RecordEventsDropped(reason string, count int) {
+	r.mu.Lock()
+	r.dropped[reason] += count
+	onDropped := r.onDropped
+	r.mu.Unlock()
+
+	if onDropped != nil {
+		onDropped()
+	}
+}
+
+func (r *metricsRecorder) ObserveEvents(observe func() EventStats) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.observe = observe
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.observe = nil
+	}
+}
+
+func (r *metricsRecorder) eventStats() (EventStats, bool) {
+	r.mu.Lock()
+	observe := r.observe
+	r.mu.Unlock()
+	if observe == nil {
+		return EventStats{}, false
+	}
+	return observe(), true
+}
+
+func TestMetrics(t *testing.T) {
+	api := newFakeAPI(t)
+	recorder := newMetricsRecorder()
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithPollingDisabled(),
+		WithMetrics(recorder),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, client.Initialize())
+	assert.True(t, client.GetBooleanValue("checkout", false))
+	assert.False(t, client.GetBooleanValue("missing", false))
+
+	require.NoError(t, client.Track("checkout_clicked", nil))
+	stats, ok := recorder.eventStats()
+	require.True(t, ok)
+	assert.Equal(t, EventStats{QueueDepth: 1}, stats)
+
+	require.NoError(t, client.Close())
+	_, ok = recorder.eventStats()
+	assert.False(t, ok)
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	assert.Equal(t, map[string]int{
+		"checkout:CACHED":        1,
+		"missing:FLAG_NOT_FOUND": 1,
+	}, recorder.evaluations)
+	assert.Equal(t, map[CacheResult]int{CacheHit: 1, CacheMiss: 1}, recorder.cache)
+	assert.Equal(t, 1, recorder.requests["/sdk/init:OK"])
+	assert.Equal(t, 1, recorder.requests["/sdk/events/batch:OK"])
+}
+
+func TestMetricsRetriesAndCircuitBreaker(t *testing.T) {
+	api := newFakeAPI(t)
+	server, _ := newFlakyServer(t, api, http.StatusServiceUnavailable, 100)
+	recorder := newMetricsRecorder()
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(server.URL+"/api/v1"),
+		WithPollingDisabled(),
+		WithRetryPolicy(&RetryConfig{MaxAttempts: 2, BaseDelay: time.Millisecond}),
+		WithCircuitBreaker(&CircuitBreakerConfig{FailureThreshold: 1}),
+		WithMetrics(recorder),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.Error(t, client.Initialize())
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	assert.Equal(t, 2, recorder.requests["/sdk/init:Service Unavailable"])
+	assert.Equal(t, 1, recorder.retries)
+	assert.Equal(t, []string{"api:CLOSED->OPEN"}, recorder.circuits)
+}
+
+func TestMetricsDroppedEvents(t *testing.T) {
+	api := newFakeAPI(t)
+	eventsServer, _ := newFlakyServer(t, api, http.StatusBadRequest, 100)
+	recorder := newMetricsRecorder()
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithEventsBaseURL(eventsServer.URL+"/api/v1"),
+		WithPollingDisabled(),
+		WithRetries(1),
+		WithEventRedeliveryPolicy(&RetryConfig{MaxAttempts: 1}),
+		WithMetrics(recorder),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Initialize())
+	require.NoError(t, client.Track("checkout_clicked", nil))
+	require.NoError(t, client.Track("checkout_completed", nil))
+	require.Error(t, client.FlushContext(context.Background()))
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	assert.Equal(t, map[string]int{"send_failed": 2}, recorder.dropped)
+}
+
+func TestMetricsDroppedEventsReportedOutsideQueueLock(t *testing.T) {
+	api := newFakeAPI(t)
+	recorder := newMetricsRecorder()
+	var depths []int
+	recorder.onDropped = func() {
+		stats, _ := recorder.eventStats()
+		depths = append(depths, stats.QueueDepth)
+	}
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithPollingDisabled(),
+		WithEventQueueSize(1),
+		WithEventBatchSize(100),
+		WithEventFlushInterval(time.Hour),
+		WithMetrics(recorder),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Initialize())
+
+	// The hook can read the queue while an event is dropped
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = client.Track("e1", nil)
+		_ = client.Track("e2", nil)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("tracking deadlocked in the drop hook")
+	}
+
+	assert.Equal(t, []int{1}, depths)
+}
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	. "github.com/teracrafts/flagkit-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// circuitRecorder records circuit state changes reported by a client.
+type circuitRecorder struct {
+	changes []string
+	mu      sync.Mutex
+}
+
+func (r *circuitRecorder) record(name string, from, to CircuitState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.changes = append(r.changes, name+":"+from.String()+"->"+to.String())
+}
+
+func (r *circuitRecorder) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.changes...)
+}
+
+func TestClientCircuitStateChange(t *testing.T) {
+	api := newFakeAPI(t)
+	server, requests := newFlakyServer(t, api, http.StatusServiceUnavailable, 100)
+
+	c, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(server.URL+"/api/v1"),
+		WithPollingDisabled(),
+		WithRetryPolicy(&RetryConfig{MaxAttempts: 1}),
+		WithCircuitBreaker(&CircuitBreakerConfig{FailureThreshold: 1}),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer c.Close()
+
+	recorder := &circuitRecorder{}
+	c.OnCircuitStateChange(recorder.record)
+
+	require.Error(t, c.Initialize())
+	assert.Equal(t, []string{CircuitAPI + ":CLOSED->OPEN"}, recorder.get())
+
+	// Requests are rejected while the circuit is open
+	c.Refresh()
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestClientEventsCircuitBreaker(t *testing.T) {
+	api := newFakeAPI(t)
+	eventsServer, _ := newFlakyServer(t, api, http.StatusServiceUnavailable, 100)
+
+	c, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithEventsBaseURL(eventsServer.URL+"/api/v1"),
+		WithPollingDisabled(),
+		WithEventsRetryPolicy(&RetryConfig{MaxAttempts: 1}),
+		WithEventsCircuitBreaker(&CircuitBreakerConfig{FailureThreshold: 1}),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer c.Close()
+
+	recorder := &circuitRecorder{}
+	c.OnCircuitStateChange(recorder.record)
+
+	require.NoError(t, c.Initialize())
+
+	require.NoError(t, c.Track("checkout_clicked", nil))
+	require.Error(t, c.FlushContext(context.Background()))
+	assert.Equal(t, []string{CircuitEvents + ":CLOSED->OPEN"}, recorder.get())
+
+	// Flag requests use their own circuit breaker
+	c.Refresh()
+	assert.True(t, c.GetBooleanValue("checkout", false))
+	assert.Equal(t, InitStatusReadyFromNetwork, c.InitializationStatus())
+}
@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "github.com/teracrafts/flagkit-go"
+	"github.com/teracrafts/flagkit-go/types"
+)
+
+// newEvaluateServer returns an API serving "checkout" as false from init and
+// evaluating it to true on the server for mobile devices. The evaluate
+// endpoint responds with status instead when it is not 200.
+func newEvaluateServer(t *testing.T, status int) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/sdk/init", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(&types.InitResponse{
+			Flags: []FlagState{
+				{Key: "checkout", Value: false, Enabled: true, Version: 1, FlagType: FlagTypeBoolean},
+			},
+			EnvironmentID:          "env-1",
+			PollingIntervalSeconds: 30,
+		})
+	})
+	mux.HandleFunc("/api/v1/sdk/evaluate", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		var body struct {
+			Context map[string]any `json:"context"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mobile := body.Context["deviceType"] == "mobile"
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"flags": []map[string]any{{
+				"key": "checkout", "value": mobile, "enabled": true, "version": 1,
+				"flagType": FlagTypeBoolean, "reason": "TARGETED",
+			}},
+		})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestServerEvaluation_DistinctContexts(t *testing.T) {
+	server, requests := newEvaluateServer(t, http.StatusOK)
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(server.URL+"/api/v1"),
+		WithPollingDisabled(),
+		WithServerEvaluation(),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Initialize())
+
+	// Contexts differing only in device type are evaluated and cached separately
+	mobile := NewContext("user-1").WithDeviceType("mobile")
+	desktop := NewContext("user-1").WithDeviceType("desktop")
+
+	assert.True(t, client.GetBooleanValue("checkout", false, mobile))
+	assert.False(t, client.GetBooleanValue("checkout", true, desktop))
+	assert.True(t, client.GetBooleanValue("checkout", false, mobile))
+	assert.False(t, client.GetBooleanValue("checkout", true, desktop))
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestServerEvaluation_UnserializableContext(t *testing.T) {
+	server, requests := newEvaluateServer(t, http.StatusOK)
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(server.URL+"/api/v1"),
+		WithPollingDisabled(),
+		WithServerEvaluation(),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Initialize())
+
+	// Contexts that cannot be serialized fall back to the shared cache
+	// instead of sharing a context cache entry
+	a := NewContext("user-1").WithDeviceType("mobile").WithCustom("score", math.NaN())
+	b := NewContext("user-2").WithCustom("score", math.NaN())
+
+	result := client.Evaluate("checkout", a)
+	assert.Equal(t, false, result.Value)
+	assert.Equal(t, types.ReasonCached, result.Reason)
+	assert.Equal(t, false, client.Evaluate("checkout", b).Value)
+	assert.Equal(t, int32(0), requests.Load())
+}
+
+func TestServerEvaluation_BacksOffAfterFailure(t *testing.T) {
+	server, requests := newEvaluateServer(t, http.StatusServiceUnavailable)
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(server.URL+"/api/v1"),
+		WithPollingDisabled(),
+		WithServerEvaluation(),
+		WithRetries(1),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Initialize())
+
+	assert.False(t, client.GetBooleanValue("checkout", true, NewContext("user-1")))
+	assert.False(t, client.GetBooleanValue("checkout", true, NewContext("user-2")))
+	assert.Equal(t, int32(1), requests.Load())
+}
@@ -0,0 +1,165 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "github.com/teracrafts/flagkit-go"
+)
+
+// newStreamServer creates a test SSE server that writes the given events and
+// then holds the connection open until the client disconnects.
+func newStreamServer(t *testing.T, events ...string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sdk/stream/token", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "sdk_test_key_12345", r.Header.Get("X-API-Key"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"token":"stream-token","expiresIn":300}`))
+	})
+	mux.HandleFunc("/sdk/stream", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "stream-token", r.URL.Query().Get("token"))
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		for _, event := range events {
+			_, _ = fmt.Fprint(w, event)
+		}
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestStreamingManagerAppliesEvents(t *testing.T) {
+	server := newStreamServer(t,
+		"event: flag_updated\ndata: {\"key\":\"kill-switch\",\"value\":false,\"enabled\":true,\"version\":7,\"flagType\":\"boolean\"}\n\n",
+		"event: flag_deleted\ndata: {\"key\":\"old-flag\"}\n\n",
+		"event: flags_reset\ndata: [{\"key\":\"a\",\"value\":1,\"flagType\":\"number\"},{\"key\":\"b\",\"value\":\"x\",\"flagType\":\"string\"}]\n\n",
+	)
+
+	var mu sync.Mutex
+	var updated []*FlagState
+	var deleted []string
+	var reset []*FlagState
+	done := make(chan struct{})
+
+	sm := NewStreamingManager(
+		server.URL,
+		func() string { return "sdk_test_key_12345" },
+		nil,
+		func(flag *FlagState) {
+			mu.Lock()
+			defer mu.Unlock()
+			updated = append(updated, flag)
+		},
+		func(key string) {
+			mu.Lock()
+			defer mu.Unlock()
+			deleted = append(deleted, key)
+		},
+		func(flags []*FlagState) {
+			mu.Lock()
+			defer mu.Unlock()
+			reset = flags
+			close(done)
+		},
+		func() { t.Error("unexpected fallback to polling") },
+		&NullLogger{},
+	)
+	defer sm.Disconnect()
+
+	sm.Connect()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for streamed events")
+	}
+
+	assert.Equal(t, StreamingStateConnected, sm.GetState())
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, updated, 1)
+	assert.Equal(t, "kill-switch", updated[0].Key)
+	assert.Equal(t, false, updated[0].Value)
+	assert.Equal(t, 7, updated[0].Version)
+	assert.Equal(t, []string{"old-flag"}, deleted)
+	require.Len(t, reset, 2)
+	assert.Equal(t, "a", reset[0].Key)
+	assert.Equal(t, "b", reset[1].Key)
+}
+
+func TestStreamingManagerFallsBackToPolling(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	fallback := make(chan struct{}, 1)
+
+	sm := NewStreamingManager(
+		server.URL,
+		func() string { return "sdk_test_key_12345" },
+		&StreamingConfig{
+			ReconnectInterval:    10 * time.Millisecond,
+			MaxReconnectAttempts: 2,
+			HeartbeatInterval:    time.Second,
+		},
+		func(flag *FlagState) {},
+		func(key string) {},
+		func(flags []*FlagState) {},
+		func() { fallback <- struct{}{} },
+		&NullLogger{},
+	)
+	defer sm.Disconnect()
+
+	sm.Connect()
+
+	select {
+	case <-fallback:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for fallback to polling")
+	}
+
+	assert.Equal(t, StreamingStateFailed, sm.GetState())
+}
+
+func TestStreamingManagerUnavailableErrorFallsBack(t *testing.T) {
+	server := newStreamServer(t,
+		"event: error\ndata: {\"code\":\"STREAMING_UNAVAILABLE\",\"message\":\"maintenance\"}\n\n",
+	)
+
+	fallback := make(chan struct{}, 1)
+
+	sm := NewStreamingManager(
+		server.URL,
+		func() string { return "sdk_test_key_12345" },
+		nil,
+		func(flag *FlagState) {},
+		func(key string) {},
+		func(flags []*FlagState) {},
+		func() { fallback <- struct{}{} },
+		&NullLogger{},
+	)
+	defer sm.Disconnect()
+
+	sm.Connect()
+
+	select {
+	case <-fallback:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for fallback to polling")
+	}
+}
@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "github.com/teracrafts/flagkit-go"
+)
+
+func TestTrackAttachesContext(t *testing.T) {
+	api := newFakeAPI(t)
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithPollingDisabled(),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Initialize())
+	require.NoError(t, client.SetContext(NewContext("user-1").
+		WithCountry("US").
+		WithEmail("user@example.com").
+		WithPrivateAttribute("email")))
+
+	require.NoError(t, client.Track("checkout_clicked", map[string]any{"button": "buy"}))
+	require.NoError(t, client.TrackWithContext("checkout_viewed", NewContext("user-2")))
+	require.NoError(t, client.TrackMetric("checkout_completed", 99.5, nil, NewContext("user-3").WithCustom("plan", "pro")))
+	client.Flush()
+
+	events := api.receivedEvents()
+	require.Len(t, events, 3)
+
+	// Track uses the global context without private attributes
+	assert.Equal(t, "checkout_clicked", events[0]["type"])
+	assert.Equal(t, map[string]any{"userId": "user-1", "country": "US"}, events[0]["context"])
+	assert.Equal(t, map[string]any{"button": "buy"}, events[0]["data"])
+	assert.NotContains(t, events[0], "metricValue")
+
+	// Per-call contexts are merged over the global context
+	assert.Equal(t, map[string]any{"userId": "user-2", "country": "US"}, events[1]["context"])
+	assert.Equal(t, map[string]any{
+		"userId":  "user-3",
+		"country": "US",
+		"custom":  map[string]any{"plan": "pro"},
+	}, events[2]["context"])
+	assert.Equal(t, 99.5, events[2]["metricValue"])
+}
+
+func TestTrackMetricStrictPIIMode(t *testing.T) {
+	client, err := NewClient("sdk_test_key_12345",
+		WithOffline(),
+		WithStrictPIIMode(),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	err = client.TrackMetric("purchase", 10, map[string]any{"email": "user@example.com"})
+	assert.Error(t, err)
+	err = client.TrackWithContext("purchase", nil, map[string]any{"email": "user@example.com"})
+	assert.Error(t, err)
+}
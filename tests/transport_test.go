@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/teracrafts/flagkit-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingTransport counts the requests sent through it.
+type countingTransport struct {
+	requests atomic.Int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests.Add(1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestClientUsesTransport(t *testing.T) {
+	api := newFakeAPI(t)
+	transport := &countingTransport{}
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithTransport(transport),
+		WithPollingDisabled(),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Initialize())
+	assert.True(t, client.GetBooleanValue("checkout", false))
+	assert.Equal(t, int32(1), transport.requests.Load())
+}
+
+func TestClientUsesHTTPClient(t *testing.T) {
+	api := newFakeAPI(t)
+	transport := &countingTransport{}
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(api.baseURL()),
+		WithHTTPClient(&http.Client{Transport: transport}),
+		WithPollingDisabled(),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Initialize())
+	assert.Equal(t, int32(1), transport.requests.Load())
+}
+
+func TestClientUsesTLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"flags":[{"key":"checkout","value":true,"enabled":true,"flagType":"boolean"}]}`))
+	}))
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+
+	client, err := NewClient("sdk_test_key_12345",
+		WithBaseURL(server.URL),
+		WithTLSConfig(&tls.Config{RootCAs: roots}),
+		WithPollingDisabled(),
+		WithLogger(&NullLogger{}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Initialize())
+	assert.True(t, client.GetBooleanValue("checkout", false))
+}
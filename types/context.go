@@ -12,6 +12,7 @@ type EvaluationContext struct {
 	Browser           string                 `json:"browser,omitempty"`
 	Custom            map[string]any `json:"custom,omitempty"`
 	PrivateAttributes []string               `json:"privateAttributes,omitempty"`
+	Kinds             map[string]*ContextKind `json:"kinds,omitempty"`
 }
 
 // NewContext creates a new EvaluationContext with the given user ID.
@@ -99,6 +100,7 @@ func (c *EvaluationContext) Merge(other *EvaluationContext) *EvaluationContext {
 		Browser:           c.Browser,
 		Custom:            make(map[string]any),
 		PrivateAttributes: make([]string, 0),
+		Kinds:             copyKinds(c.Kinds),
 	}
 
 	// Copy custom from base
@@ -149,6 +151,21 @@ func (c *EvaluationContext) Merge(other *EvaluationContext) *EvaluationContext {
 		merged.PrivateAttributes = append(merged.PrivateAttributes, attr)
 	}
 
+	// Merge kinds
+	for name, kind := range other.Kinds {
+		if kind == nil {
+			continue
+		}
+		if merged.Kinds == nil {
+			merged.Kinds = make(map[string]*ContextKind)
+		}
+		if existing, ok := merged.Kinds[name]; ok {
+			merged.Kinds[name] = existing.merge(kind)
+		} else {
+			merged.Kinds[name] = kind.copy()
+		}
+	}
+
 	return merged
 }
 
@@ -190,6 +207,16 @@ func (c *EvaluationContext) StripPrivateAttributes() *EvaluationContext {
 		}
 	}
 
+	for name, kind := range c.Kinds {
+		if kind == nil {
+			continue
+		}
+		if stripped.Kinds == nil {
+			stripped.Kinds = make(map[string]*ContextKind, len(c.Kinds))
+		}
+		stripped.Kinds[name] = kind.strip()
+	}
+
 	return stripped
 }
 
@@ -206,6 +233,7 @@ func (c *EvaluationContext) Copy() *EvaluationContext {
 		Browser:           c.Browser,
 		Custom:            make(map[string]any),
 		PrivateAttributes: make([]string, len(c.PrivateAttributes)),
+		Kinds:             copyKinds(c.Kinds),
 	}
 
 	for k, v := range c.Custom {
@@ -235,9 +263,27 @@ func (c *EvaluationContext) ToMap() map[string]any {
 	if c.Country != "" {
 		m["country"] = c.Country
 	}
+	if c.DeviceType != "" {
+		m["deviceType"] = c.DeviceType
+	}
+	if c.OS != "" {
+		m["os"] = c.OS
+	}
+	if c.Browser != "" {
+		m["browser"] = c.Browser
+	}
 	if len(c.Custom) > 0 {
 		m["custom"] = c.Custom
 	}
+	if len(c.Kinds) > 0 {
+		kinds := make(map[string]any, len(c.Kinds))
+		for name, kind := range c.Kinds {
+			if kind != nil {
+				kinds[name] = kind.toMap()
+			}
+		}
+		m["kinds"] = kinds
+	}
 
 	return m
 }
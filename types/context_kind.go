@@ -0,0 +1,219 @@
+package types
+
+import (
+	"sort"
+	"strings"
+)
+
+// Built-in context kinds.
+const (
+	KindUser         = "user"
+	KindOrganization = "org"
+	KindDevice       = "device"
+)
+
+// ContextKind describes an additional entity of a multi-kind context, such as
+// an organization or a device. The user is described by the EvaluationContext
+// fields themselves.
+//
+// Targeting rules reference kind attributes as "<kind>.<attribute>", for
+// example "org.plan" or "device.key".
+type ContextKind struct {
+	Kind              string         `json:"kind"`
+	Key               string         `json:"key"`
+	Attributes        map[string]any `json:"attributes,omitempty"`
+	PrivateAttributes []string       `json:"privateAttributes,omitempty"`
+}
+
+// NewKindContext creates a new ContextKind of the given kind with the given key.
+func NewKindContext(kind, key string) *ContextKind {
+	return &ContextKind{
+		Kind:       kind,
+		Key:        key,
+		Attributes: make(map[string]any),
+	}
+}
+
+// NewOrgContext creates a new organization ContextKind with the given key.
+func NewOrgContext(key string) *ContextKind {
+	return NewKindContext(KindOrganization, key)
+}
+
+// NewDeviceContext creates a new device ContextKind with the given key.
+func NewDeviceContext(key string) *ContextKind {
+	return NewKindContext(KindDevice, key)
+}
+
+// NewMultiContext creates a context describing a user together with other
+// kinds such as an organization and a device.
+// The user may be nil when only other kinds are known.
+func NewMultiContext(user *EvaluationContext, kinds ...*ContextKind) *EvaluationContext {
+	var ctx *EvaluationContext
+	if user != nil {
+		ctx = user.Copy()
+	} else {
+		ctx = &EvaluationContext{Custom: make(map[string]any)}
+	}
+	for _, kind := range kinds {
+		ctx.WithKind(kind)
+	}
+	return ctx
+}
+
+// WithAttribute sets an attribute and returns the kind.
+func (k *ContextKind) WithAttribute(key string, value any) *ContextKind {
+	if k.Attributes == nil {
+		k.Attributes = make(map[string]any)
+	}
+	k.Attributes[key] = value
+	return k
+}
+
+// WithPrivateAttribute marks an attribute of the kind as private.
+func (k *ContextKind) WithPrivateAttribute(attr string) *ContextKind {
+	k.PrivateAttributes = append(k.PrivateAttributes, attr)
+	return k
+}
+
+// WithKind adds a context kind, replacing any existing kind of the same name,
+// and returns the context. Kinds without a name are ignored, as is the user
+// kind, which is described by the context fields.
+func (c *EvaluationContext) WithKind(kind *ContextKind) *EvaluationContext {
+	if kind == nil || kind.Kind == "" || kind.Kind == KindUser {
+		return c
+	}
+	if c.Kinds == nil {
+		c.Kinds = make(map[string]*ContextKind)
+	}
+	c.Kinds[kind.Kind] = kind.copy()
+	return c
+}
+
+// Kind returns the context kind with the given name, or nil if absent.
+func (c *EvaluationContext) Kind(name string) *ContextKind {
+	return c.Kinds[name]
+}
+
+// Keys returns the key of every kind in the context, including the user.
+func (c *EvaluationContext) Keys() map[string]string {
+	keys := make(map[string]string, len(c.Kinds)+1)
+	if c.UserID != "" {
+		keys[KindUser] = c.UserID
+	}
+	for name, kind := range c.Kinds {
+		if kind != nil && kind.Key != "" {
+			keys[name] = kind.Key
+		}
+	}
+	return keys
+}
+
+// CanonicalKey returns a key that uniquely identifies the combination of
+// kinds in the context. For a single-kind user context it is the user ID.
+func (c *EvaluationContext) CanonicalKey() string {
+	if len(c.Kinds) == 0 {
+		return c.UserID
+	}
+
+	keys := c.Keys()
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+":"+keys[name])
+	}
+	return strings.Join(parts, ",")
+}
+
+// copy creates a deep copy of the kind.
+func (k *ContextKind) copy() *ContextKind {
+	result := &ContextKind{
+		Kind:              k.Kind,
+		Key:               k.Key,
+		Attributes:        make(map[string]any, len(k.Attributes)),
+		PrivateAttributes: make([]string, len(k.PrivateAttributes)),
+	}
+	for key, v := range k.Attributes {
+		result.Attributes[key] = v
+	}
+	copy(result.PrivateAttributes, k.PrivateAttributes)
+	return result
+}
+
+// merge returns a copy of the kind with other's values taking precedence.
+// A nil other leaves the kind unchanged.
+func (k *ContextKind) merge(other *ContextKind) *ContextKind {
+	merged := k.copy()
+	if other == nil {
+		return merged
+	}
+	if other.Key != "" {
+		merged.Key = other.Key
+	}
+	for key, v := range other.Attributes {
+		merged.Attributes[key] = v
+	}
+
+	privateSet := make(map[string]bool)
+	for _, attr := range merged.PrivateAttributes {
+		privateSet[attr] = true
+	}
+	for _, attr := range other.PrivateAttributes {
+		if !privateSet[attr] {
+			privateSet[attr] = true
+			merged.PrivateAttributes = append(merged.PrivateAttributes, attr)
+		}
+	}
+	return merged
+}
+
+// strip returns a copy of the kind with private attributes removed, or nil
+// for a nil kind. The key is never private.
+func (k *ContextKind) strip() *ContextKind {
+	if k == nil {
+		return nil
+	}
+	stripped := &ContextKind{
+		Kind:       k.Kind,
+		Key:        k.Key,
+		Attributes: make(map[string]any),
+	}
+
+	privateSet := make(map[string]bool)
+	for _, attr := range k.PrivateAttributes {
+		privateSet[attr] = true
+	}
+	for key, v := range k.Attributes {
+		if !privateSet[key] {
+			stripped.Attributes[key] = v
+		}
+	}
+	return stripped
+}
+
+// toMap converts the kind to a map for serialization.
+func (k *ContextKind) toMap() map[string]any {
+	m := map[string]any{"key": k.Key}
+	if len(k.Attributes) > 0 {
+		m["attributes"] = k.Attributes
+	}
+	return m
+}
+
+// copyKinds deep copies a set of kinds, skipping nil kinds.
+func copyKinds(kinds map[string]*ContextKind) map[string]*ContextKind {
+	if len(kinds) == 0 {
+		return nil
+	}
+	result := make(map[string]*ContextKind, len(kinds))
+	for name, kind := range kinds {
+		if kind != nil {
+			result[name] = kind.copy()
+		}
+	}
+	return result
+}
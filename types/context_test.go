@@ -126,3 +126,106 @@ func TestContextCopy(t *testing.T) {
 	copied.Custom["key"] = "modified"
 	assert.NotEqual(t, ctx.Custom["key"], copied.Custom["key"])
 }
+
+func TestNewMultiContext(t *testing.T) {
+	user := NewContext("user-123").WithEmail("user@example.com")
+	org := NewOrgContext("acme").WithAttribute("plan", "enterprise")
+	device := NewDeviceContext("device-9").WithAttribute("os", "iOS")
+
+	ctx := NewMultiContext(user, org, device)
+
+	assert.Equal(t, "user-123", ctx.UserID)
+	assert.Equal(t, "acme", ctx.Kind(KindOrganization).Key)
+	assert.Equal(t, "enterprise", ctx.Kind(KindOrganization).Attributes["plan"])
+	assert.Equal(t, "iOS", ctx.Kind(KindDevice).Attributes["os"])
+	assert.Nil(t, ctx.Kind("team"))
+
+	// Kinds are copied into the context
+	org.WithAttribute("plan", "free")
+	assert.Equal(t, "enterprise", ctx.Kind(KindOrganization).Attributes["plan"])
+}
+
+func TestContextKeys(t *testing.T) {
+	ctx := NewMultiContext(NewContext("user-123"), NewOrgContext("acme"), NewDeviceContext("device-9"))
+
+	assert.Equal(t, map[string]string{
+		KindUser:         "user-123",
+		KindOrganization: "acme",
+		KindDevice:       "device-9",
+	}, ctx.Keys())
+	assert.Equal(t, "device:device-9,org:acme,user:user-123", ctx.CanonicalKey())
+
+	assert.Equal(t, "user-123", NewContext("user-123").CanonicalKey())
+}
+
+func TestContextMergeKinds(t *testing.T) {
+	base := NewMultiContext(NewContext("user-123"),
+		NewOrgContext("acme").WithAttribute("plan", "free").WithAttribute("region", "eu"))
+	other := NewMultiContext(nil,
+		NewOrgContext("").WithAttribute("plan", "enterprise").WithPrivateAttribute("region"),
+		NewDeviceContext("device-9"))
+
+	merged := base.Merge(other)
+
+	org := merged.Kind(KindOrganization)
+	assert.Equal(t, "acme", org.Key)
+	assert.Equal(t, "enterprise", org.Attributes["plan"])
+	assert.Equal(t, "eu", org.Attributes["region"])
+	assert.Contains(t, org.PrivateAttributes, "region")
+	assert.Equal(t, "device-9", merged.Kind(KindDevice).Key)
+
+	// The base context is unchanged
+	assert.Equal(t, "free", base.Kind(KindOrganization).Attributes["plan"])
+}
+
+func TestContextStripPrivateKindAttributes(t *testing.T) {
+	ctx := NewMultiContext(NewContext("user-123"),
+		NewOrgContext("acme").
+			WithAttribute("plan", "enterprise").
+			WithAttribute("billingEmail", "billing@acme.com").
+			WithPrivateAttribute("billingEmail"))
+
+	stripped := ctx.StripPrivateAttributes()
+
+	org := stripped.Kind(KindOrganization)
+	assert.Equal(t, "acme", org.Key)
+	assert.Equal(t, "enterprise", org.Attributes["plan"])
+	_, hasBillingEmail := org.Attributes["billingEmail"]
+	assert.False(t, hasBillingEmail)
+}
+
+func TestContextToMapIncludesKinds(t *testing.T) {
+	ctx := NewMultiContext(NewContext("user-123"), NewOrgContext("acme").WithAttribute("plan", "enterprise"))
+
+	m := ctx.ToMap()
+
+	kinds := m["kinds"].(map[string]any)
+	org := kinds[KindOrganization].(map[string]any)
+	assert.Equal(t, "acme", org["key"])
+	assert.Equal(t, "enterprise", org["attributes"].(map[string]any)["plan"])
+}
+
+func TestContextCopyKinds(t *testing.T) {
+	ctx := NewMultiContext(NewContext("user-123"), NewOrgContext("acme").WithAttribute("plan", "free"))
+
+	copied := ctx.Copy()
+	copied.Kind(KindOrganization).Attributes["plan"] = "enterprise"
+
+	assert.Equal(t, "free", ctx.Kind(KindOrganization).Attributes["plan"])
+}
+
+func TestContextSkipsNilKinds(t *testing.T) {
+	ctx := NewContext("user-123")
+	ctx.Kinds = map[string]*ContextKind{KindOrganization: nil, KindDevice: NewDeviceContext("ios-1")}
+	other := NewContext("user-123")
+	other.Kinds = map[string]*ContextKind{KindDevice: nil}
+
+	assert.NotContains(t, ctx.Copy().Kinds, KindOrganization)
+	assert.NotContains(t, ctx.StripPrivateAttributes().Kinds, KindOrganization)
+	assert.Equal(t, map[string]string{KindUser: "user-123", KindDevice: "ios-1"}, ctx.Keys())
+
+	merged := ctx.Merge(other)
+	assert.NotContains(t, merged.Kinds, KindOrganization)
+	assert.Equal(t, "ios-1", merged.Kind(KindDevice).Key)
+	assert.NotContains(t, ctx.ToMap()["kinds"], KindOrganization)
+}
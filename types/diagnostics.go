@@ -0,0 +1,69 @@
+package types
+
+import "time"
+
+// Diagnostics is a snapshot of the state of a client, for health checks and
+// debugging.
+type Diagnostics struct {
+	// Status is where flags are served from.
+	Status InitializationStatus `json:"status"`
+
+	// Ready reports whether the first initialization attempt has completed.
+	Ready bool `json:"ready"`
+
+	// LastSuccessfulFetch is when flags were last fetched from the API.
+	LastSuccessfulFetch *time.Time `json:"lastSuccessfulFetch,omitempty"`
+
+	// LastError is the last error fetching flags, if any.
+	LastError string `json:"lastError,omitempty"`
+
+	// LastErrorTime is when LastError occurred.
+	LastErrorTime *time.Time `json:"lastErrorTime,omitempty"`
+
+	// CircuitBreakers holds the statistics of each circuit breaker by name.
+	CircuitBreakers map[string]map[string]any `json:"circuitBreakers"`
+
+	// Polling is the state of polling, or nil if the client is not polling.
+	Polling *PollingDiagnostics `json:"polling,omitempty"`
+
+	// StreamingState is the state of the streaming connection, or empty if
+	// the client is not streaming.
+	StreamingState string `json:"streamingState,omitempty"`
+
+	// Cache holds the statistics of the flag cache.
+	Cache map[string]int `json:"cache"`
+
+	// EventQueueSize is the number of events waiting to be sent.
+	EventQueueSize int `json:"eventQueueSize"`
+
+	// EventsDropped is the number of events dropped without being sent, by
+	// reason.
+	EventsDropped map[string]int64 `json:"eventsDropped,omitempty"`
+
+	// PersistedEvents is the number of persisted events that have not been
+	// sent, or nil if event persistence is disabled.
+	PersistedEvents *int `json:"persistedEvents,omitempty"`
+
+	// KeyID is the ID of the active API key.
+	KeyID string `json:"keyId"`
+
+	// KeyRotation reports whether requests use the secondary API key during
+	// the key rotation grace period.
+	KeyRotation bool `json:"keyRotation"`
+}
+
+// PollingDiagnostics is the state of polling.
+type PollingDiagnostics struct {
+	// Active reports whether polling is running.
+	Active bool `json:"active"`
+
+	// CurrentIntervalMs is the current polling interval in milliseconds,
+	// including backoff after errors.
+	CurrentIntervalMs int64 `json:"currentIntervalMs"`
+}
+
+// Healthy reports whether the client is ready and serves flags fetched from
+// the API, a snapshot, the flag store or bootstrap values.
+func (d *Diagnostics) Healthy() bool {
+	return d.Ready && d.Status != InitStatusFailed
+}
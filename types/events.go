@@ -0,0 +1,38 @@
+package types
+
+// AnalyticsEvent is an analytics event as seen by event processors.
+type AnalyticsEvent struct {
+	// Type is the event type, such as "purchase" or "flag.evaluated".
+	Type string
+
+	// Data is the event data. Processors that modify it should copy it
+	// first, as it may be shared with the caller.
+	Data map[string]any
+
+	// Context is the evaluation context of the event, without private
+	// attributes.
+	Context map[string]any
+
+	// MetricValue is the numeric value of metric events.
+	MetricValue *float64
+
+	// Metadata is sent with the event, such as deployment metadata added by
+	// enrichment processors.
+	Metadata map[string]any
+}
+
+// EventProcessor processes analytics events before they are queued. See the
+// events package for built-in processors. Processors run in order on the
+// goroutine that tracks the event; returning false drops the event.
+// Implementations must be safe for concurrent use.
+type EventProcessor interface {
+	Process(event *AnalyticsEvent) bool
+}
+
+// EventProcessorFunc adapts a function to an EventProcessor.
+type EventProcessorFunc func(event *AnalyticsEvent) bool
+
+// Process calls f(event).
+func (f EventProcessorFunc) Process(event *AnalyticsEvent) bool {
+	return f(event)
+}
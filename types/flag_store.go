@@ -0,0 +1,31 @@
+package types
+
+// FlagStore is a shared store of flag states, such as Redis or a file, that
+// lets one writer keep flags up to date for many clients.
+//
+// A client in writer mode keeps the store updated from initialization,
+// polling and streaming. A client in daemon mode never requests flags from
+// the API and reads them from the store instead.
+type FlagStore interface {
+	// Get returns a flag, or nil if the store does not contain it.
+	Get(key string) (*FlagState, error)
+
+	// GetAll returns all flags in the store.
+	GetAll() ([]FlagState, error)
+
+	// Init replaces all flags in the store and marks it as initialized.
+	Init(flags []FlagState) error
+
+	// Upsert adds or replaces a flag.
+	// Flags older than the version already stored are ignored.
+	Upsert(flag FlagState) error
+
+	// UpsertAll adds or replaces flags in one write, like Upsert.
+	UpsertAll(flags []FlagState) error
+
+	// Delete removes a flag.
+	Delete(key string) error
+
+	// Initialized reports whether a writer has initialized the store.
+	Initialized() (bool, error)
+}
@@ -0,0 +1,61 @@
+package types
+
+import "time"
+
+// CacheResult is the result of a flag cache lookup.
+type CacheResult string
+
+const (
+	// CacheHit means the flag was cached and not expired.
+	CacheHit CacheResult = "hit"
+	// CacheStale means the flag was cached but expired.
+	CacheStale CacheResult = "stale"
+	// CacheMiss means the flag was not cached.
+	CacheMiss CacheResult = "miss"
+)
+
+// EventStats holds the event delivery gauges of a client.
+type EventStats struct {
+	// QueueDepth is the number of events waiting to be sent.
+	QueueDepth int
+
+	// PersistedEvents is the number of persisted events that have not been
+	// sent. Zero when event persistence is disabled.
+	PersistedEvents int
+}
+
+// Metrics receives measurements from clients, for export to a metrics system
+// such as OpenTelemetry or Prometheus. The metrics/otel and metrics/prometheus
+// modules have built-in implementations. Implementations must be safe for
+// concurrent use and should return quickly, as most methods are called on the
+// evaluation and request paths.
+type Metrics interface {
+	// RecordEvaluation records a flag evaluation and its latency, including
+	// evaluation jitter.
+	RecordEvaluation(flagKey string, reason EvaluationReason, duration time.Duration)
+
+	// RecordCacheLookup records a flag cache lookup.
+	RecordCacheLookup(result CacheResult)
+
+	// RecordRequest records an API request attempt to an endpoint path, such
+	// as "/sdk/init". statusCode is 0 when no response was received.
+	RecordRequest(endpoint string, statusCode int, duration time.Duration)
+
+	// RecordRetry records that a request to an endpoint path is retried.
+	RecordRetry(endpoint string)
+
+	// RecordCircuitStateChange records a circuit breaker state change.
+	// name is "api" or "events"; from and to are CLOSED, OPEN or HALF_OPEN.
+	RecordCircuitStateChange(name, from, to string)
+
+	// RecordEventsDropped records events that were dropped without being
+	// sent, because the event queue was full ("queue_full"), sending
+	// failed the maximum number of attempts ("send_failed") or persisted
+	// events exceeded the disk quota ("disk_quota").
+	RecordEventsDropped(reason string, count int)
+
+	// ObserveEvents registers a function that returns the event gauges of a
+	// client, to be called when metrics are collected. The returned function
+	// unregisters it and is called when the client is closed.
+	ObserveEvents(observe func() EventStats) (unregister func())
+}
@@ -0,0 +1,11 @@
+package types
+
+// SnapshotStore persists the last known good flag set of a client so that a
+// restarted client can serve it before, or instead of, a successful fetch.
+//
+// Snapshots are opaque to the store; they may be encrypted. Load returns nil
+// data and a nil error when no snapshot has been saved.
+type SnapshotStore interface {
+	Load() ([]byte, error)
+	Save(data []byte) error
+}
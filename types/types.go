@@ -19,15 +19,31 @@ const (
 type EvaluationReason string
 
 const (
-	ReasonCached       EvaluationReason = "CACHED"
-	ReasonFallthrough  EvaluationReason = "FALLTHROUGH"
-	ReasonTargeted     EvaluationReason = "TARGETED"
-	ReasonDefault      EvaluationReason = "DEFAULT"
-	ReasonDisabled     EvaluationReason = "DISABLED"
-	ReasonFlagNotFound EvaluationReason = "FLAG_NOT_FOUND"
-	ReasonError        EvaluationReason = "ERROR"
-	ReasonStaleCache   EvaluationReason = "STALE_CACHE"
-	ReasonBootstrap    EvaluationReason = "BOOTSTRAP"
+	ReasonCached             EvaluationReason = "CACHED"
+	ReasonFallthrough        EvaluationReason = "FALLTHROUGH"
+	ReasonTargeted           EvaluationReason = "TARGETED"
+	ReasonDefault            EvaluationReason = "DEFAULT"
+	ReasonDisabled           EvaluationReason = "DISABLED"
+	ReasonFlagNotFound       EvaluationReason = "FLAG_NOT_FOUND"
+	ReasonError              EvaluationReason = "ERROR"
+	ReasonStaleCache         EvaluationReason = "STALE_CACHE"
+	ReasonBootstrap          EvaluationReason = "BOOTSTRAP"
+	ReasonPrerequisiteFailed EvaluationReason = "PREREQUISITE_FAILED"
+)
+
+// InitializationStatus describes the progress of client initialization.
+type InitializationStatus string
+
+const (
+	// InitStatusInitializing means the first initialization attempt has not completed.
+	InitStatusInitializing InitializationStatus = "INITIALIZING"
+	// InitStatusReadyFromNetwork means flags were fetched from the API.
+	InitStatusReadyFromNetwork InitializationStatus = "READY_FROM_NETWORK"
+	// InitStatusReadyFromCache means flags could not be fetched and are served
+	// from a snapshot, the flag store or bootstrap values.
+	InitStatusReadyFromCache InitializationStatus = "READY_FROM_CACHE"
+	// InitStatusFailed means flags could not be fetched and default values are served.
+	InitStatusFailed InitializationStatus = "FAILED"
 )
 
 // FlagState represents the state of a feature flag.
@@ -49,6 +65,20 @@ type EvaluationResult struct {
 	Version   int              `json:"version"`
 	Timestamp time.Time        `json:"timestamp"`
 	Error     error            `json:"-"`
+
+	// VariationIndex is the index of the served variation.
+	// Only set for locally evaluated flags.
+	VariationIndex *int `json:"variationIndex,omitempty"`
+
+	// RuleID is the ID of the matched targeting rule, if any.
+	RuleID string `json:"ruleId,omitempty"`
+
+	// InRollout reports whether the variation was selected by a percentage rollout.
+	InRollout bool `json:"inRollout,omitempty"`
+
+	// PrerequisiteKey is the key of the prerequisite flag that was not met
+	// when Reason is PREREQUISITE_FAILED.
+	PrerequisiteKey string `json:"prerequisiteKey,omitempty"`
 }
 
 // BoolValue returns the value as a boolean.
@@ -107,6 +137,18 @@ func (r *EvaluationResult) JSONValue() map[string]any {
 	return nil
 }
 
+// Variant is the result of assigning a context to an experiment.
+type Variant struct {
+	ExperimentKey string `json:"experimentKey"`
+	// Name is the assigned variant. Contexts that are not enrolled in the
+	// experiment receive the control variant.
+	Name    string `json:"name"`
+	Payload any    `json:"payload,omitempty"`
+	Version int    `json:"version"`
+	// InExperiment reports whether the context is enrolled in the experiment.
+	InExperiment bool `json:"inExperiment"`
+}
+
 // InitResponseMetadata contains version and feature metadata from the init response.
 type InitResponseMetadata struct {
 	// SDKVersionMin is the minimum SDK version required (older versions may not work).